@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ConversionFailure строка, значение которой не удаётся привести к новому типу
+type ConversionFailure struct {
+	CTID  string
+	Value string
+	Error string
+}
+
+// ColumnTypeCheck результат предварительной проверки изменения типа столбца
+type ColumnTypeCheck struct {
+	CurrentType    string
+	NewType        string
+	Using          string // выражение USING, указанное пользователем
+	UsingRequired  bool   // без USING PostgreSQL не сможет привести тип автоматически
+	SuggestedUsing string // предлагаемое выражение USING, если оно обязательно
+	Rewrite        bool   // изменение потребует полной перезаписи таблицы и индексов
+	RewriteReason  string
+	TotalRows      int64
+	FailedRows     int64
+	Samples        []ConversionFailure
+}
+
+// DefaultConversionSampleLimit сколько примеров неудачных строк возвращает проверка
+const DefaultConversionSampleLimit = 20
+
+// CheckColumnTypeChange проверяет изменение типа столбца до выполнения ALTER:
+// нужен ли USING, будет ли перезаписана таблица и какие строки не удастся преобразовать.
+// Каждая строка проверяется в отдельном блоке EXCEPTION временной функции,
+// поэтому проверка не изменяет данные, но читает всю таблицу.
+func CheckColumnTypeChange(ctx context.Context, pool *pgxpool.Pool, table, col, newtyp, using string, sampleLimit int) (*ColumnTypeCheck, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return nil, err
+	}
+	if err := validateSQLIdent(col); err != nil {
+		return nil, err
+	}
+	newtyp = strings.TrimSpace(newtyp)
+	if newtyp == "" {
+		return nil, fmt.Errorf("Новый тип столбца не может быть пустым")
+	}
+	if sampleLimit <= 0 {
+		sampleLimit = DefaultConversionSampleLimit
+	}
+
+	check := &ColumnTypeCheck{NewType: newtyp, Using: strings.TrimSpace(using)}
+
+	typeQuery := `
+	WITH cur AS (
+		SELECT a.atttypid AS oid, format_type(a.atttypid, a.atttypmod) AS name
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND a.attname = $2
+		AND a.attnum > 0 AND NOT a.attisdropped
+	), target AS (
+		SELECT t.oid, t.typcategory::text AS category
+		FROM pg_type t WHERE t.oid = $3::text::regtype::oid
+	)
+	SELECT cur.name, cur.oid = target.oid, target.category,
+		COALESCE((SELECT pc.castcontext::text FROM pg_cast pc
+			WHERE pc.castsource = cur.oid AND pc.casttarget = target.oid), ''),
+		COALESCE((SELECT pc.castmethod::text FROM pg_cast pc
+			WHERE pc.castsource = cur.oid AND pc.casttarget = target.oid), '')
+	FROM cur, target
+	`
+
+	var sameType bool
+	var category, castContext, castMethod string
+	err := pool.QueryRow(ctx, typeQuery, table, col, newtyp).Scan(&check.CurrentType, &sameType, &category, &castContext, &castMethod)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("столбец %s.%s не найден", table, col)
+		}
+		return nil, fmt.Errorf("ошибка определения типов: %w", err)
+	}
+
+	// Преобразование без USING выполняется только неявным или присваивающим приведением;
+	// приведение к строковым типам через ввод/вывод PostgreSQL добавляет автоматически
+	autoCast := castContext == "a" || castContext == "i" || category == "S"
+
+	switch {
+	case check.Using != "":
+		check.Rewrite = true
+		check.RewriteReason = "с выражением USING каждая строка вычисляется заново — таблица и её индексы будут перезаписаны"
+	case sameType:
+		check.RewriteReason = "изменяется только модификатор типа: увеличение длины обходится без перезаписи, " +
+			"уменьшение длины или изменение масштаба требует проверки или перезаписи всех строк"
+	case castMethod == "b" && autoCast:
+		check.RewriteReason = "типы двоично совместимы — перезапись таблицы не требуется"
+	case autoCast:
+		check.Rewrite = true
+		check.RewriteReason = "значения будут преобразованы — таблица и её индексы будут полностью перезаписаны (ACCESS EXCLUSIVE блокировка)"
+	default:
+		check.UsingRequired = true
+		check.Rewrite = true
+		check.RewriteReason = "автоматического приведения нет — нужен USING; таблица и её индексы будут полностью перезаписаны"
+	}
+
+	expression := check.Using
+	if expression == "" {
+		expression = col
+	}
+
+	// Временная функция живёт только в транзакции, которая затем откатывается.
+	// Значение присваивается переменной нового типа: как и ALTER COLUMN TYPE, присваивание
+	// использует приведение присваивания (например, "value too long" вместо усечения varchar).
+	// Строка ищется по (tableoid, ctid), так как ctid уникален только внутри одной секции
+	fn := fmt.Sprintf(`
+	CREATE FUNCTION pg_temp.bd_check_conversion(sample_limit int)
+	RETURNS TABLE(row_ctid text, value text, error text, failed bigint, total bigint)
+	LANGUAGE plpgsql AS $fn$
+	#variable_conflict use_column
+	DECLARE
+		r record;
+		converted %[4]s;
+	BEGIN
+		failed := 0;
+		total := 0;
+		FOR r IN SELECT t.tableoid AS o, t.ctid AS c, t.%[2]s::text AS v FROM %[1]s t LOOP
+			total := total + 1;
+			BEGIN
+				converted := (SELECT (%[3]s) FROM %[1]s WHERE tableoid = r.o AND ctid = r.c);
+			EXCEPTION WHEN others THEN
+				failed := failed + 1;
+				IF failed <= sample_limit THEN
+					row_ctid := r.c::text;
+					value := r.v;
+					error := SQLERRM;
+					RETURN NEXT;
+				END IF;
+			END;
+		END LOOP;
+		row_ctid := NULL;
+		value := NULL;
+		error := NULL;
+		RETURN NEXT;
+	END
+	$fn$`, table, col, expression, newtyp)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fn); err != nil {
+		log.Printf("Проверка преобразования типа: %v", err)
+		return nil, fmt.Errorf("ошибка подготовки проверки (проверьте тип и выражение USING): %w", err)
+	}
+
+	rows, err := tx.Query(ctx, "SELECT row_ctid, value, error, failed, total FROM pg_temp.bd_check_conversion($1)", sampleLimit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки преобразования: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ctid, value, errText *string
+		var failed, total int64
+		if err := rows.Scan(&ctid, &value, &errText, &failed, &total); err != nil {
+			return nil, fmt.Errorf("ошибка чтения результата проверки: %w", err)
+		}
+		if ctid == nil {
+			// Итоговая строка
+			check.FailedRows, check.TotalRows = failed, total
+			continue
+		}
+		failure := ConversionFailure{CTID: *ctid, Value: "NULL"}
+		if value != nil {
+			failure.Value = *value
+		}
+		if errText != nil {
+			failure.Error = *errText
+		}
+		check.Samples = append(check.Samples, failure)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка проверки преобразования: %w", err)
+	}
+
+	if check.UsingRequired {
+		check.SuggestedUsing = fmt.Sprintf("%s::%s", col, newtyp)
+	}
+
+	return check, nil
+}
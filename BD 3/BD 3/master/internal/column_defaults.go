@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ColumnDefaultInfo столбец таблицы и его значение по умолчанию
+type ColumnDefaultInfo struct {
+	Column   string
+	Type     string
+	Default  string // выражение DEFAULT, пустое если не задано
+	NotNull  bool
+	Computed string // "GENERATED", "IDENTITY ALWAYS", "IDENTITY BY DEFAULT" или пусто
+}
+
+// DefaultBackfillBatchSize размер пакета по умолчанию для заполнения NULL значений
+const DefaultBackfillBatchSize = 1000
+
+// GetColumnDefaults возвращает столбцы таблицы с их значениями по умолчанию
+func GetColumnDefaults(ctx context.Context, pool *pgxpool.Pool, table string) ([]ColumnDefaultInfo, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT
+		a.attname,
+		format_type(a.atttypid, a.atttypmod),
+		CASE WHEN a.attgenerated = '' THEN COALESCE(pg_get_expr(d.adbin, d.adrelid), '') ELSE '' END,
+		a.attnotnull,
+		CASE
+			WHEN a.attgenerated = 's' THEN 'GENERATED'
+			WHEN a.attidentity = 'a' THEN 'IDENTITY ALWAYS'
+			WHEN a.attidentity = 'd' THEN 'IDENTITY BY DEFAULT'
+			ELSE ''
+		END
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE n.nspname = 'public' AND c.relname = $1
+	AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY a.attnum
+	`
+
+	rows, err := pool.Query(ctx, query, table)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения значений по умолчанию: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnDefaultInfo
+	for rows.Next() {
+		var info ColumnDefaultInfo
+		if err := rows.Scan(&info.Column, &info.Type, &info.Default, &info.NotNull, &info.Computed); err != nil {
+			return nil, fmt.Errorf("ошибка чтения столбца: %w", err)
+		}
+		columns = append(columns, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("таблица %s не найдена", table)
+	}
+	return columns, nil
+}
+
+// SetColumnDefault устанавливает значение по умолчанию столбца (ALTER COLUMN SET DEFAULT)
+// Пример: SetColumnDefault(ctx, pool, "products", "created_at", "now()")
+func SetColumnDefault(ctx context.Context, pool *pgxpool.Pool, table, col, expression string) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(col); err != nil {
+		return err
+	}
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return fmt.Errorf("выражение DEFAULT не может быть пустым")
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", table, col, expression)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Установка DEFAULT: %v", err)
+		return fmt.Errorf("Не удалось установить DEFAULT: %w", err)
+	}
+
+	fmt.Printf("Для столбца %s.%s установлено DEFAULT %s\n", table, col, expression)
+	return nil
+}
+
+// DropColumnDefault удаляет значение по умолчанию столбца (ALTER COLUMN DROP DEFAULT)
+func DropColumnDefault(ctx context.Context, pool *pgxpool.Pool, table, col string) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(col); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", table, col)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Удаление DEFAULT: %v", err)
+		return fmt.Errorf("Не удалось удалить DEFAULT: %w", err)
+	}
+
+	fmt.Printf("Для столбца %s.%s удалено DEFAULT\n", table, col)
+	return nil
+}
+
+// CountNullValues возвращает количество строк, где столбец равен NULL
+func CountNullValues(ctx context.Context, pool *pgxpool.Pool, table, col string) (int64, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return 0, err
+	}
+	if err := validateSQLIdent(col); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IS NULL", table, col)
+	if err := pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("ошибка подсчёта NULL значений: %w", err)
+	}
+	return count, nil
+}
+
+// BackfillColumnDefault заполняет существующие NULL значения столбца его значением по умолчанию.
+// Обновление идёт пакетами по batchSize строк, каждый пакет — отдельная транзакция,
+// чтобы не держать долгие блокировки. progress (может быть nil) вызывается после каждого пакета
+// с общим числом обновлённых строк. Возвращает количество обновлённых строк.
+func BackfillColumnDefault(ctx context.Context, pool *pgxpool.Pool, table, col string, batchSize int,
+	progress func(updated int64)) (int64, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return 0, err
+	}
+	if err := validateSQLIdent(col); err != nil {
+		return 0, err
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBackfillBatchSize
+	}
+
+	// UPDATE по (tableoid, ctid) ограничивает размер пакета: ctid уникален только внутри одной секции.
+	// Повторная проверка IS NULL не даёт перезаписать строку, изменённую после выборки.
+	// RETURNING позволяет заметить DEFAULT, равный NULL
+	query := fmt.Sprintf(`
+	WITH batch AS (
+		UPDATE %[1]s SET %[2]s = DEFAULT
+		WHERE (tableoid, ctid) IN (SELECT tableoid, ctid FROM %[1]s WHERE %[2]s IS NULL LIMIT %[3]d)
+			AND %[2]s IS NULL
+		RETURNING %[2]s
+	)
+	SELECT COUNT(*), COUNT(*) FILTER (WHERE %[2]s IS NULL) FROM batch
+	`, table, col, batchSize)
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		var updated, stillNull int64
+		if err := pool.QueryRow(ctx, query).Scan(&updated, &stillNull); err != nil {
+			log.Printf("Заполнение DEFAULT: %v", err)
+			return total, fmt.Errorf("ошибка заполнения значений по умолчанию: %w", err)
+		}
+		if updated == 0 {
+			break
+		}
+		total += updated - stillNull
+		if stillNull > 0 {
+			return total, fmt.Errorf("значение по умолчанию столбца %s вернуло NULL — заполнение остановлено", col)
+		}
+		if progress != nil {
+			progress(total)
+		}
+	}
+
+	fmt.Printf("В столбце %s.%s заполнено значений по умолчанию: %d\n", table, col, total)
+	return total, nil
+}
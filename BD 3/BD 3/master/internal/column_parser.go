@@ -0,0 +1,530 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ColumnSpecError ошибка разбора определения столбца с позицией (строка и столбец считаются с 1)
+type ColumnSpecError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ColumnSpecError) Error() string {
+	return fmt.Sprintf("строка %d, позиция %d: %s", e.Line, e.Column, e.Msg)
+}
+
+type specTokenKind int
+
+const (
+	tokWord     specTokenKind = iota // ключевое слово или идентификатор без кавычек
+	tokQuoted                        // идентификатор в двойных кавычках
+	tokString                        // строковый литерал в одинарных кавычках
+	tokNumber                        // число
+	tokPunct                         // ( ) [ ] , .
+	tokOperator                      // прочие символы (операторы, ::)
+	tokNewline                       // перевод строки (разделяет определения вне скобок)
+)
+
+type specToken struct {
+	kind   specTokenKind
+	text   string
+	line   int
+	column int
+	space  bool // перед токеном был пробел
+}
+
+// upper возвращает текст слова в верхнем регистре (для сравнения с ключевыми словами)
+func (t specToken) upper() string {
+	if t.kind != tokWord {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+func (t specToken) errorf(format string, args ...interface{}) error {
+	return &ColumnSpecError{Line: t.line, Column: t.column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokenizeColumnSpec разбивает текст на токены, пропуская комментарии "--"
+func tokenizeColumnSpec(text string) ([]specToken, error) {
+	runes := []rune(text)
+	var tokens []specToken
+	line, column := 1, 1
+	space := false
+
+	advance := func() rune {
+		r := runes[0]
+		runes = runes[1:]
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+		return r
+	}
+
+	isWordStart := func(r rune) bool { return unicode.IsLetter(r) || r == '_' }
+	isWordPart := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' }
+
+	for len(runes) > 0 {
+		r := runes[0]
+		tok := specToken{line: line, column: column, space: space}
+
+		switch {
+		case r == '\n':
+			advance()
+			tok.kind, tok.text = tokNewline, "\n"
+			tokens = append(tokens, tok)
+			space = true
+			continue
+
+		case unicode.IsSpace(r):
+			advance()
+			space = true
+			continue
+
+		case r == '-' && len(runes) > 1 && runes[1] == '-':
+			for len(runes) > 0 && runes[0] != '\n' {
+				advance()
+			}
+			continue
+
+		case r == '"' || r == '\'':
+			quote := r
+			var sb strings.Builder
+			sb.WriteRune(advance())
+			closed := false
+			for len(runes) > 0 {
+				c := advance()
+				sb.WriteRune(c)
+				if c == quote {
+					// Удвоенная кавычка — экранирование внутри
+					if len(runes) > 0 && runes[0] == quote {
+						sb.WriteRune(advance())
+						continue
+					}
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				if quote == '"' {
+					return nil, tok.errorf("незакрытый идентификатор в двойных кавычках")
+				}
+				return nil, tok.errorf("незакрытый строковый литерал")
+			}
+			tok.text = sb.String()
+			if quote == '"' {
+				if tok.text == `""` {
+					return nil, tok.errorf("пустой идентификатор в кавычках")
+				}
+				tok.kind = tokQuoted
+			} else {
+				tok.kind = tokString
+			}
+
+		case isWordStart(r):
+			var sb strings.Builder
+			for len(runes) > 0 && isWordPart(runes[0]) {
+				sb.WriteRune(advance())
+			}
+			tok.kind, tok.text = tokWord, sb.String()
+
+		case unicode.IsDigit(r):
+			var sb strings.Builder
+			for len(runes) > 0 && (unicode.IsDigit(runes[0]) || runes[0] == '.') {
+				sb.WriteRune(advance())
+			}
+			tok.kind, tok.text = tokNumber, sb.String()
+
+		case strings.ContainsRune("()[],.", r):
+			tok.kind, tok.text = tokPunct, string(advance())
+
+		default:
+			var sb strings.Builder
+			for len(runes) > 0 && strings.ContainsRune("+-*/<>=~!@#%^&|`?:;$", runes[0]) {
+				if runes[0] == '-' && len(runes) > 1 && runes[1] == '-' && sb.Len() > 0 {
+					break
+				}
+				sb.WriteRune(advance())
+			}
+			if sb.Len() == 0 {
+				return nil, tok.errorf("неожиданный символ %q", r)
+			}
+			tok.kind, tok.text = tokOperator, sb.String()
+		}
+
+		tokens = append(tokens, tok)
+		space = false
+	}
+	return tokens, nil
+}
+
+// joinSpecTokens собирает текст из токенов, сохраняя пробелы там, где они были во входном тексте
+func joinSpecTokens(tokens []specToken) string {
+	var sb strings.Builder
+	for i, tok := range tokens {
+		if i > 0 && tok.space {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(tok.text)
+	}
+	return sb.String()
+}
+
+// checkSpecBrackets проверяет парность круглых и квадратных скобок
+func checkSpecBrackets(tokens []specToken) error {
+	var open []specToken
+	for _, tok := range tokens {
+		if tok.kind != tokPunct {
+			continue
+		}
+		switch tok.text {
+		case "(", "[":
+			open = append(open, tok)
+		case ")", "]":
+			want := "("
+			if tok.text == "]" {
+				want = "["
+			}
+			if len(open) == 0 || open[len(open)-1].text != want {
+				return tok.errorf("лишняя закрывающая скобка %s", tok.text)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		last := open[len(open)-1]
+		return last.errorf("незакрытая скобка %s", last.text)
+	}
+	return nil
+}
+
+// trimSpecTerminator убирает завершающую точку с запятой (текст скопирован из CREATE TABLE)
+func trimSpecTerminator(tokens []specToken) []specToken {
+	for len(tokens) > 0 {
+		last := tokens[len(tokens)-1]
+		if last.kind != tokNewline && !(last.kind == tokOperator && last.text == ";") {
+			break
+		}
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// continuationKeywords — ключевые слова, с которых строка продолжает определение столбца
+var continuationKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "REFERENCES": true, "GENERATED": true, "COLLATE": true,
+}
+
+// continuesDefinition определяет, что строка продолжает предыдущее определение:
+// она начинается с отступом больше, чем у первой строки, или с ограничения столбца.
+// PRIMARY KEY (...) и UNIQUE (...) со списком столбцов — ограничения таблицы
+func continuesDefinition(line []specToken, baseColumn int) bool {
+	if len(line) == 0 {
+		return false
+	}
+	first := line[0]
+	if first.column > baseColumn || continuationKeywords[first.upper()] {
+		return true
+	}
+	switch first.upper() {
+	case "PRIMARY":
+		return !(len(line) > 2 && line[1].upper() == "KEY" && line[2].text == "(")
+	case "UNIQUE":
+		return !(len(line) > 1 && line[1].text == "(")
+	}
+	return false
+}
+
+// splitSpecDefinitions делит токены на определения по запятым вне скобок; если запятых
+// нет (построчный формат), разделителем служит перевод строки, кроме строк-продолжений
+func splitSpecDefinitions(tokens []specToken) [][]specToken {
+	tokens = trimSpecTerminator(tokens)
+	depth := 0
+	byComma := false
+	for _, tok := range tokens {
+		if tok.kind == tokPunct {
+			switch tok.text {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				depth--
+			case ",":
+				byComma = byComma || depth == 0
+			}
+		}
+	}
+
+	var definitions [][]specToken
+	var current []specToken
+	flush := func() {
+		if len(current) > 0 {
+			definitions = append(definitions, current)
+		}
+		current = nil
+	}
+	baseColumn := 0
+	for i, tok := range tokens {
+		if tok.kind == tokPunct {
+			switch tok.text {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				depth--
+			}
+		}
+		if tok.kind == tokNewline {
+			// Перевод строки внутри определения считаем пробелом
+			if depth == 0 && !byComma && len(current) > 0 && !continuesDefinition(nextSpecLine(tokens[i+1:]), baseColumn) {
+				flush()
+			}
+			continue
+		}
+		if depth == 0 && tok.kind == tokPunct && tok.text == "," {
+			flush()
+			continue
+		}
+		if baseColumn == 0 {
+			baseColumn = tok.column
+		}
+		current = append(current, tok)
+	}
+	flush()
+	return definitions
+}
+
+// nextSpecLine возвращает токены следующей непустой строки
+func nextSpecLine(tokens []specToken) []specToken {
+	for len(tokens) > 0 && tokens[0].kind == tokNewline {
+		tokens = tokens[1:]
+	}
+	for i, tok := range tokens {
+		if tok.kind == tokNewline {
+			return tokens[:i]
+		}
+	}
+	return tokens
+}
+
+var columnConstraintKeywords = map[string]bool{
+	"CONSTRAINT": true, "NOT": true, "NULL": true, "PRIMARY": true, "UNIQUE": true,
+	"DEFAULT": true, "CHECK": true, "REFERENCES": true, "GENERATED": true, "COLLATE": true,
+}
+
+// isTableConstraintStart определяет, что определение — ограничение уровня таблицы, а не столбец
+func isTableConstraintStart(tokens []specToken) bool {
+	switch tokens[0].upper() {
+	case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+		return true
+	case "EXCLUDE":
+		return len(tokens) > 1 && (tokens[1].upper() == "USING" || tokens[1].text == "(")
+	}
+	return false
+}
+
+// parseColumnSpec разбирает одно определение столбца: имя, тип и ограничения
+func parseColumnSpec(tokens []specToken) (ColumnDefinition, error) {
+	nameTok := tokens[0]
+	if nameTok.kind != tokWord && nameTok.kind != tokQuoted {
+		return ColumnDefinition{}, nameTok.errorf("ожидалось имя столбца, найдено %q", nameTok.text)
+	}
+	if columnConstraintKeywords[nameTok.upper()] {
+		return ColumnDefinition{}, nameTok.errorf("ожидалось имя столбца, найдено ключевое слово %s", nameTok.text)
+	}
+
+	rest := tokens[1:]
+	if len(rest) == 0 {
+		return ColumnDefinition{}, nameTok.errorf("не указан тип столбца %s", nameTok.text)
+	}
+
+	// Тип продолжается до первого ключевого слова ограничения вне скобок
+	depth := 0
+	typeEnd := len(rest)
+	for i, tok := range rest {
+		if tok.kind == tokPunct && (tok.text == "(" || tok.text == "[") {
+			depth++
+		} else if tok.kind == tokPunct && (tok.text == ")" || tok.text == "]") {
+			depth--
+		} else if depth == 0 && columnConstraintKeywords[tok.upper()] {
+			typeEnd = i
+			break
+		}
+	}
+
+	typeTokens := rest[:typeEnd]
+	if len(typeTokens) == 0 {
+		return ColumnDefinition{}, rest[0].errorf("не указан тип столбца %s", nameTok.text)
+	}
+	if first := typeTokens[0]; first.kind != tokWord && first.kind != tokQuoted {
+		return ColumnDefinition{}, first.errorf("ожидался тип столбца, найдено %q", first.text)
+	}
+	for _, tok := range typeTokens {
+		if tok.kind == tokString || tok.kind == tokOperator {
+			return ColumnDefinition{}, tok.errorf("недопустимый элемент в типе столбца: %s", tok.text)
+		}
+	}
+
+	constraintTokens := rest[typeEnd:]
+	for i, tok := range constraintTokens {
+		if (tok.upper() == "DEFAULT" || tok.upper() == "CHECK" || tok.upper() == "REFERENCES") && i == len(constraintTokens)-1 {
+			return ColumnDefinition{}, tok.errorf("после %s ожидается продолжение", tok.upper())
+		}
+	}
+
+	col := ColumnDefinition{
+		Name: nameTok.text,
+		Type: joinSpecTokens(typeTokens),
+	}
+	applyColumnClauses(&col, constraintTokens)
+	return col, nil
+}
+
+// splitConstraintClauses делит токены ограничений столбца на предложения по ключевым словам вне скобок
+func splitConstraintClauses(tokens []specToken) [][]specToken {
+	var clauses [][]specToken
+	depth := 0
+	for i, tok := range tokens {
+		if tok.kind == tokNewline {
+			continue
+		}
+		if tok.kind == tokPunct && (tok.text == "(" || tok.text == "[") {
+			depth++
+		} else if tok.kind == tokPunct && (tok.text == ")" || tok.text == "]") {
+			depth--
+		}
+
+		starts := len(clauses) == 0 || depth == 0 && columnConstraintKeywords[tok.upper()]
+		// NULL после NOT — часть NOT NULL, DEFAULT после BY — часть GENERATED BY DEFAULT
+		if i > 0 && (tok.upper() == "NULL" && tokens[i-1].upper() == "NOT" || tok.upper() == "DEFAULT" && tokens[i-1].upper() == "BY") {
+			starts = false
+		}
+		// После "CONSTRAINT имя" ограничение продолжается
+		if last := len(clauses) - 1; starts && last >= 0 && len(clauses[last]) == 2 && clauses[last][0].upper() == "CONSTRAINT" {
+			starts = false
+		}
+
+		if starts {
+			clauses = append(clauses, nil)
+		}
+		clauses[len(clauses)-1] = append(clauses[len(clauses)-1], tok)
+	}
+	return clauses
+}
+
+// clauseWords возвращает ключевые слова предложения через пробел (для сравнения)
+func clauseWords(clause []specToken) string {
+	words := make([]string, len(clause))
+	for i, tok := range clause {
+		words[i] = tok.upper()
+	}
+	return strings.Join(words, " ")
+}
+
+// applyColumnClauses переносит NOT NULL, DEFAULT, COLLATE, GENERATED и IDENTITY в поля определения,
+// остальные предложения остаются в Constraints
+func applyColumnClauses(col *ColumnDefinition, tokens []specToken) {
+	var rest []string
+	for _, clause := range splitConstraintClauses(tokens) {
+		switch words := clauseWords(clause); {
+		case words == "NOT NULL":
+			col.NotNull = true
+		case words == "NULL":
+		case clause[0].upper() == "DEFAULT":
+			col.Default = joinSpecTokens(clause[1:])
+		case clause[0].upper() == "COLLATE" && len(clause) == 2:
+			col.Collation = clause[1].text
+		case words == "GENERATED ALWAYS AS IDENTITY":
+			col.Identity = IdentityAlways
+		case words == "GENERATED BY DEFAULT AS IDENTITY":
+			col.Identity = IdentityByDefault
+		case len(clause) > 5 && strings.HasPrefix(words, "GENERATED ALWAYS AS ") &&
+			clause[3].text == "(" && clause[len(clause)-2].text == ")" && clause[len(clause)-1].upper() == "STORED":
+			col.Generated = joinSpecTokens(clause[4 : len(clause)-2])
+		default:
+			rest = append(rest, joinSpecTokens(clause))
+		}
+	}
+	col.Constraints = strings.Join(rest, " ")
+}
+
+// ParseTableDefinition разбирает текст с определениями столбцов и ограничений таблицы.
+// Определения разделяются запятыми или переводами строк вне скобок, поэтому подходит
+// как построчный формат "имя тип ограничения", так и тело CREATE TABLE. В построчном формате
+// строка с отступом или начинающаяся с NOT NULL, DEFAULT и т.п. продолжает предыдущее определение.
+// Пример: ParseTableDefinition(`price NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (price >= 0)`)
+func ParseTableDefinition(text string) ([]ColumnDefinition, []string, error) {
+	tokens, err := tokenizeColumnSpec(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkSpecBrackets(tokens); err != nil {
+		return nil, nil, err
+	}
+
+	var columns []ColumnDefinition
+	var tableConstraints []string
+	for _, definition := range splitSpecDefinitions(tokens) {
+		if isTableConstraintStart(definition) {
+			tableConstraints = append(tableConstraints, joinSpecTokens(definition))
+			continue
+		}
+		col, err := parseColumnSpec(definition)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns = append(columns, col)
+	}
+	return columns, tableConstraints, nil
+}
+
+// ParseColumnDefinitions разбирает определения столбцов; ограничения уровня таблицы считаются ошибкой
+// Пример: ParseColumnDefinitions("\"Имя\" character varying(50) NOT NULL")
+func ParseColumnDefinitions(text string) ([]ColumnDefinition, error) {
+	tokens, err := tokenizeColumnSpec(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSpecBrackets(tokens); err != nil {
+		return nil, err
+	}
+
+	var columns []ColumnDefinition
+	for _, definition := range splitSpecDefinitions(tokens) {
+		if isTableConstraintStart(definition) {
+			return nil, definition[0].errorf("ограничение уровня таблицы не является определением столбца")
+		}
+		col, err := parseColumnSpec(definition)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// SplitColumnConstraints делит текст ограничений столбца на отдельные предложения:
+// "UNIQUE CHECK (x > 0) REFERENCES t(id)" → ["UNIQUE", "CHECK (x > 0)", "REFERENCES t(id)"].
+// Префикс CONSTRAINT имя остаётся в начале следующего за ним предложения.
+func SplitColumnConstraints(constraints string) ([]string, error) {
+	tokens, err := tokenizeColumnSpec(constraints)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSpecBrackets(tokens); err != nil {
+		return nil, err
+	}
+
+	clauses := splitConstraintClauses(tokens)
+
+	result := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		result = append(result, joinSpecTokens(clause))
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTableDefinition(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		columns     []ColumnDefinition
+		constraints []string
+	}{
+		{
+			name: "числовой тип с ограничениями",
+			text: `price NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (price >= 0)`,
+			columns: []ColumnDefinition{
+				{Name: "price", Type: "NUMERIC(10, 2)", NotNull: true, Default: "0", Constraints: "CHECK (price >= 0)"},
+			},
+		},
+		{
+			name: "идентификатор в кавычках и составной тип",
+			text: `"Имя" character varying(50)`,
+			columns: []ColumnDefinition{
+				{Name: `"Имя"`, Type: "character varying(50)"},
+			},
+		},
+		{
+			name: "массив",
+			text: "tags text[] DEFAULT '{}'",
+			columns: []ColumnDefinition{
+				{Name: "tags", Type: "text[]", Default: "'{}'"},
+			},
+		},
+		{
+			name: "построчный формат",
+			text: "id integer\nname text NOT NULL",
+			columns: []ColumnDefinition{
+				{Name: "id", Type: "integer"},
+				{Name: "name", Type: "text", NotNull: true},
+			},
+		},
+		{
+			name: "продолжение определения на следующей строке",
+			text: "a int\n  NOT NULL",
+			columns: []ColumnDefinition{
+				{Name: "a", Type: "int", NotNull: true},
+			},
+		},
+		{
+			name: "строка начинается с ограничения столбца",
+			text: "a int\nDEFAULT 1\nb text",
+			columns: []ColumnDefinition{
+				{Name: "a", Type: "int", Default: "1"},
+				{Name: "b", Type: "text"},
+			},
+		},
+		{
+			name: "тело CREATE TABLE через запятые на нескольких строках",
+			text: "id serial,\nname text\n  NOT NULL,\nPRIMARY KEY (id)\n;",
+			columns: []ColumnDefinition{
+				{Name: "id", Type: "serial"},
+				{Name: "name", Type: "text", NotNull: true},
+			},
+			constraints: []string{"PRIMARY KEY (id)"},
+		},
+		{
+			name: "завершающая точка с запятой",
+			text: "id int PRIMARY KEY,\nname text;",
+			columns: []ColumnDefinition{
+				{Name: "id", Type: "int", Constraints: "PRIMARY KEY"},
+				{Name: "name", Type: "text"},
+			},
+		},
+		{
+			name: "ограничение таблицы в построчном формате",
+			text: "a int\nb int\nUNIQUE (a, b)",
+			columns: []ColumnDefinition{
+				{Name: "a", Type: "int"},
+				{Name: "b", Type: "int"},
+			},
+			constraints: []string{"UNIQUE (a, b)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, constraints, err := ParseTableDefinition(tt.text)
+			if err != nil {
+				t.Fatalf("ParseTableDefinition(%q): %v", tt.text, err)
+			}
+			if !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("столбцы:\n got  %#v\n want %#v", columns, tt.columns)
+			}
+			if !reflect.DeepEqual(constraints, tt.constraints) {
+				t.Errorf("ограничения:\n got  %#v\n want %#v", constraints, tt.constraints)
+			}
+		})
+	}
+}
+
+func TestParseTableDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"нет типа", "id"},
+		{"незакрытая скобка", "price NUMERIC(10, 2"},
+		{"незакрытая кавычка", `"Имя text`},
+		{"строка в типе", "a 'text'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseTableDefinition(tt.text); err == nil {
+				t.Errorf("ParseTableDefinition(%q): ожидалась ошибка", tt.text)
+			}
+		})
+	}
+}
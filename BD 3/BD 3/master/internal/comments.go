@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Типы объектов для COMMENT ON
+const (
+	CommentTable            = "TABLE"
+	CommentColumn           = "COLUMN"
+	CommentType             = "TYPE"
+	CommentView             = "VIEW"
+	CommentMaterializedView = "MATERIALIZED VIEW"
+	CommentConstraint       = "CONSTRAINT"
+)
+
+// CommentObjectTypes типы объектов в порядке отображения
+var CommentObjectTypes = []string{CommentTable, CommentColumn, CommentView, CommentMaterializedView, CommentType, CommentConstraint}
+
+// commentTarget формирует объект для COMMENT ON.
+// Для COLUMN и CONSTRAINT parent — имя таблицы.
+func commentTarget(objectType, objectName, parent string) (string, error) {
+	if err := validateSQLIdent(objectName); err != nil {
+		return "", err
+	}
+
+	switch strings.ToUpper(objectType) {
+	case CommentTable, CommentType, CommentView, CommentMaterializedView:
+		return fmt.Sprintf("%s %s", strings.ToUpper(objectType), objectName), nil
+	case CommentColumn:
+		if err := validateSQLIdent(parent); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("COLUMN %s.%s", parent, objectName), nil
+	case CommentConstraint:
+		if err := validateSQLIdent(parent); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("CONSTRAINT %s ON %s", objectName, parent), nil
+	default:
+		return "", fmt.Errorf("неподдерживаемый тип объекта: %s", objectType)
+	}
+}
+
+// SetComment устанавливает комментарий объекта (пустая строка удаляет комментарий)
+// Пример: SetComment(ctx, pool, CommentColumn, "price", "products", "Цена в рублях")
+func SetComment(ctx context.Context, pool *pgxpool.Pool, objectType, objectName, parent, comment string) error {
+	target, err := commentTarget(objectType, objectName, parent)
+	if err != nil {
+		return err
+	}
+
+	value := "NULL"
+	if strings.TrimSpace(comment) != "" {
+		value = quoteLiteral(comment)
+	}
+
+	query := fmt.Sprintf("COMMENT ON %s IS %s", target, value)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Установка комментария: %v", err)
+		return fmt.Errorf("ошибка установки комментария для %s: %w", target, err)
+	}
+
+	fmt.Printf("Комментарий для %s сохранён\n", target)
+	return nil
+}
+
+// GetComment возвращает комментарий объекта (пустая строка, если его нет)
+func GetComment(ctx context.Context, pool *pgxpool.Pool, objectType, objectName, parent string) (string, error) {
+	var query string
+	args := []interface{}{objectName}
+
+	switch strings.ToUpper(objectType) {
+	case CommentTable, CommentView, CommentMaterializedView:
+		query = `
+		SELECT COALESCE(obj_description(c.oid, 'pg_class'), '')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1
+		`
+	case CommentType:
+		query = `
+		SELECT COALESCE(obj_description(t.oid, 'pg_type'), '')
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = 'public' AND t.typname = $1
+		`
+	case CommentColumn:
+		query = `
+		SELECT COALESCE(col_description(c.oid, a.attnum), '')
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND a.attname = $1 AND c.relname = $2
+		`
+		args = append(args, parent)
+	case CommentConstraint:
+		query = `
+		SELECT COALESCE(obj_description(con.oid, 'pg_constraint'), '')
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND con.conname = $1 AND c.relname = $2
+		`
+		args = append(args, parent)
+	default:
+		return "", fmt.Errorf("неподдерживаемый тип объекта: %s", objectType)
+	}
+
+	var comment string
+	if err := pool.QueryRow(ctx, query, args...).Scan(&comment); err != nil {
+		return "", fmt.Errorf("ошибка получения комментария %s: %w", objectName, err)
+	}
+	return comment, nil
+}
+
+// GetColumnComments возвращает комментарии столбцов таблицы: map[столбец]комментарий
+func GetColumnComments(ctx context.Context, pool *pgxpool.Pool, table string) (map[string]string, error) {
+	query := `
+	SELECT a.attname, col_description(c.oid, a.attnum)
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = 'public' AND c.relname = $1
+	AND a.attnum > 0 AND NOT a.attisdropped
+	AND col_description(c.oid, a.attnum) IS NOT NULL
+	`
+	rows, err := pool.Query(ctx, query, table)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения комментариев столбцов: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make(map[string]string)
+	for rows.Next() {
+		var column, comment string
+		if err := rows.Scan(&column, &comment); err != nil {
+			return nil, fmt.Errorf("ошибка чтения комментария: %w", err)
+		}
+		comments[column] = comment
+	}
+	return comments, rows.Err()
+}
+
+// ListCommentObjects возвращает объекты указанного типа для выбора в UI.
+// Для COLUMN и CONSTRAINT возвращаются объекты таблицы parent.
+func ListCommentObjects(ctx context.Context, pool *pgxpool.Pool, objectType, parent string) ([]string, error) {
+	var query string
+	var args []interface{}
+
+	switch strings.ToUpper(objectType) {
+	case CommentTable:
+		query = `SELECT tablename FROM pg_tables WHERE schemaname = 'public' ORDER BY 1`
+	case CommentView:
+		query = `SELECT viewname FROM pg_views WHERE schemaname = 'public' ORDER BY 1`
+	case CommentMaterializedView:
+		query = `SELECT matviewname FROM pg_matviews WHERE schemaname = 'public' ORDER BY 1`
+	case CommentType:
+		query = `
+		SELECT t.typname FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = 'public' AND t.typtype IN ('e', 'c', 'd', 'r')
+		AND (t.typrelid = 0 OR (SELECT c.relkind FROM pg_class c WHERE c.oid = t.typrelid) = 'c')
+		ORDER BY 1`
+	case CommentColumn:
+		query = `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = $1
+		ORDER BY ordinal_position`
+		args = append(args, parent)
+	case CommentConstraint:
+		query = `
+		SELECT con.conname FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1
+		ORDER BY 1`
+		args = append(args, parent)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый тип объекта: %s", objectType)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения объектов: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения объекта: %w", err)
+		}
+		objects = append(objects, name)
+	}
+	return objects, rows.Err()
+}
+
+// ListAllComments возвращает все комментарии схемы public в виде таблицы
+func ListAllComments(ctx context.Context, pool *pgxpool.Pool) ([][]string, error) {
+	query := `
+	SELECT CASE c.relkind
+			WHEN 'v' THEN 'VIEW'
+			WHEN 'm' THEN 'MATERIALIZED VIEW'
+			ELSE 'TABLE'
+		END AS "Тип", c.relname AS "Объект", '' AS "Таблица", d.description AS "Комментарий"
+	FROM pg_description d
+	JOIN pg_class c ON c.oid = d.objoid AND d.classoid = 'pg_class'::regclass AND d.objsubid = 0
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = 'public'
+	UNION ALL
+	SELECT 'COLUMN', a.attname, c.relname, d.description
+	FROM pg_description d
+	JOIN pg_class c ON c.oid = d.objoid AND d.classoid = 'pg_class'::regclass
+	JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = d.objsubid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = 'public' AND d.objsubid > 0
+	UNION ALL
+	SELECT 'TYPE', t.typname, '', d.description
+	FROM pg_description d
+	JOIN pg_type t ON t.oid = d.objoid AND d.classoid = 'pg_type'::regclass
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	WHERE n.nspname = 'public'
+	UNION ALL
+	SELECT 'CONSTRAINT', con.conname, c.relname, d.description
+	FROM pg_description d
+	JOIN pg_constraint con ON con.oid = d.objoid AND d.classoid = 'pg_constraint'::regclass
+	JOIN pg_class c ON c.oid = con.conrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = 'public'
+	ORDER BY 1, 3, 2
+	`
+	return queryAsStrings(ctx, pool, query)
+}
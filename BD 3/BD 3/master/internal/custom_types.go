@@ -41,11 +41,17 @@ func CreateEnumType(ctx context.Context, pool *pgxpool.Pool, typeName string, va
 	return nil
 }
 
-// CreateCompositeType создаёт составной (composite) тип
-// Пример: CreateCompositeType(ctx, pool, "address_type",
+// CompositeField поле (атрибут) составного типа
+type CompositeField struct {
+	Name string
+	Type string
+}
+
+// CreateCompositeType создаёт составной (composite) тип; поля создаются в переданном порядке
+// Пример: CreateCompositeType(ctx, pool, "address_type", []CompositeField{
 //
-//	map[string]string{"street": "VARCHAR(255)", "city": "VARCHAR(100)", "postal_code": "VARCHAR(10)"})
-func CreateCompositeType(ctx context.Context, pool *pgxpool.Pool, typeName string, fields map[string]string) error {
+//	{Name: "street", Type: "VARCHAR(255)"}, {Name: "city", Type: "VARCHAR(100)"}, {Name: "postal_code", Type: "VARCHAR(10)"}})
+func CreateCompositeType(ctx context.Context, pool *pgxpool.Pool, typeName string, fields []CompositeField) error {
 	if len(fields) == 0 {
 		return fmt.Errorf("список полей составного типа не может быть пустым")
 	}
@@ -57,11 +63,19 @@ func CreateCompositeType(ctx context.Context, pool *pgxpool.Pool, typeName strin
 
 	// Составляем определение полей
 	var fieldDefinitions []string
-	for fieldName, fieldType := range fields {
-		if err := validateSQLIdent(fieldName); err != nil {
-			return fmt.Errorf("недопустимое имя поля '%s': %w", fieldName, err)
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if err := validateSQLIdent(field.Name); err != nil {
+			return fmt.Errorf("недопустимое имя поля '%s': %w", field.Name, err)
+		}
+		if strings.TrimSpace(field.Type) == "" {
+			return fmt.Errorf("не указан тип поля %s", field.Name)
 		}
-		fieldDefinitions = append(fieldDefinitions, fmt.Sprintf("%s %s", fieldName, fieldType))
+		if seen[field.Name] {
+			return fmt.Errorf("поле %s указано дважды", field.Name)
+		}
+		seen[field.Name] = true
+		fieldDefinitions = append(fieldDefinitions, fmt.Sprintf("%s %s", field.Name, field.Type))
 	}
 
 	query := fmt.Sprintf("CREATE TYPE %s AS (%s)", typeName, strings.Join(fieldDefinitions, ", "))
@@ -76,14 +90,77 @@ func CreateCompositeType(ctx context.Context, pool *pgxpool.Pool, typeName strin
 	return nil
 }
 
+// alterCompositeType выполняет ALTER TYPE для атрибута составного типа.
+// cascade — изменить также типизированные таблицы на основе типа, иначе RESTRICT
+func alterCompositeType(ctx context.Context, pool *pgxpool.Pool, typeName, action string, cascade bool, description string) error {
+	if err := validateSQLIdent(typeName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER TYPE %s %s %s", typeName, action, dropBehavior(cascade))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Изменение составного типа: %v", err)
+		return fmt.Errorf("ошибка изменения типа %s (%s): %w", typeName, description, err)
+	}
+
+	fmt.Printf("Составной тип '%s': %s\n", typeName, description)
+	return nil
+}
+
+// AddCompositeAttribute добавляет поле в конец составного типа
+func AddCompositeAttribute(ctx context.Context, pool *pgxpool.Pool, typeName string, field CompositeField, cascade bool) error {
+	if err := validateSQLIdent(field.Name); err != nil {
+		return fmt.Errorf("недопустимое имя поля '%s': %w", field.Name, err)
+	}
+	if strings.TrimSpace(field.Type) == "" {
+		return fmt.Errorf("не указан тип поля %s", field.Name)
+	}
+	action := fmt.Sprintf("ADD ATTRIBUTE %s %s", field.Name, field.Type)
+	return alterCompositeType(ctx, pool, typeName, action, cascade, "добавлено поле "+field.Name)
+}
+
+// DropCompositeAttribute удаляет поле составного типа
+func DropCompositeAttribute(ctx context.Context, pool *pgxpool.Pool, typeName, fieldName string, cascade bool) error {
+	if err := validateSQLIdent(fieldName); err != nil {
+		return err
+	}
+	action := "DROP ATTRIBUTE IF EXISTS " + fieldName
+	return alterCompositeType(ctx, pool, typeName, action, cascade, "удалено поле "+fieldName)
+}
+
+// RenameCompositeAttribute переименовывает поле составного типа
+func RenameCompositeAttribute(ctx context.Context, pool *pgxpool.Pool, typeName, oldName, newName string, cascade bool) error {
+	if err := validateSQLIdent(oldName); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(newName); err != nil {
+		return err
+	}
+	action := fmt.Sprintf("RENAME ATTRIBUTE %s TO %s", oldName, newName)
+	return alterCompositeType(ctx, pool, typeName, action, cascade, fmt.Sprintf("поле %s переименовано в %s", oldName, newName))
+}
+
+// AlterCompositeAttributeType изменяет тип поля составного типа
+func AlterCompositeAttributeType(ctx context.Context, pool *pgxpool.Pool, typeName, fieldName, newType string, cascade bool) error {
+	if err := validateSQLIdent(fieldName); err != nil {
+		return err
+	}
+	if newType = strings.TrimSpace(newType); newType == "" {
+		return fmt.Errorf("новый тип поля не может быть пустым")
+	}
+	action := fmt.Sprintf("ALTER ATTRIBUTE %s TYPE %s", fieldName, newType)
+	return alterCompositeType(ctx, pool, typeName, action, cascade, fmt.Sprintf("тип поля %s изменён на %s", fieldName, newType))
+}
+
 // DropEnumType удаляет ENUM тип
-// Пример: DropEnumType(ctx, pool, "status_enum")
-func DropEnumType(ctx context.Context, pool *pgxpool.Pool, typeName string) error {
+// cascade — удалить также зависимые объекты (столбцы, домены, функции), иначе RESTRICT
+// Пример: DropEnumType(ctx, pool, "status_enum", false)
+func DropEnumType(ctx context.Context, pool *pgxpool.Pool, typeName string, cascade bool) error {
 	if err := validateSQLIdent(typeName); err != nil {
 		return err
 	}
 
-	query := fmt.Sprintf("DROP TYPE IF EXISTS %s CASCADE", typeName)
+	query := fmt.Sprintf("DROP TYPE IF EXISTS %s %s", typeName, dropBehavior(cascade))
 
 	_, err := pool.Exec(ctx, query)
 	if err != nil {
@@ -96,7 +173,7 @@ func DropEnumType(ctx context.Context, pool *pgxpool.Pool, typeName string) erro
 }
 
 // GetCustomTypes получает список всех пользовательских типов
-// Возвращает: []map[string]string с полями {typeName, typeKind, definition}
+// Возвращает: []map[string]interface{} с полями {type_name, type_kind, description (*string)}
 func GetCustomTypes(ctx context.Context, pool *pgxpool.Pool) ([]map[string]interface{}, error) {
 	query := `
 	SELECT
@@ -104,13 +181,16 @@ func GetCustomTypes(ctx context.Context, pool *pgxpool.Pool) ([]map[string]inter
 		CASE t.typtype
 			WHEN 'e' THEN 'ENUM'
 			WHEN 'c' THEN 'COMPOSITE'
+			WHEN 'd' THEN 'DOMAIN'
+			WHEN 'r' THEN 'RANGE'
 			WHEN 'b' THEN 'BASE'
 			ELSE 'OTHER'
-		END as type_kind
+		END as type_kind,
+		obj_description(t.oid, 'pg_type') as description
 	FROM pg_type t
 	JOIN pg_namespace n ON n.oid = t.typnamespace
 	WHERE n.nspname = 'public'
-	AND t.typtype IN ('e', 'c')
+	AND t.typtype IN ('e', 'c', 'd', 'r')
 	ORDER BY t.typname
 `
 
@@ -124,15 +204,17 @@ func GetCustomTypes(ctx context.Context, pool *pgxpool.Pool) ([]map[string]inter
 
 	for rows.Next() {
 		var typeName, typeKind string
+		var description *string
 
-		err := rows.Scan(&typeName, &typeKind)
+		err := rows.Scan(&typeName, &typeKind, &description)
 		if err != nil {
 			return nil, fmt.Errorf("ошибка чтения типа: %w", err)
 		}
 
 		typeInfo := map[string]interface{}{
-			"type_name": typeName,
-			"type_kind": typeKind,
+			"type_name":   typeName,
+			"type_kind":   typeKind,
+			"description": description,
 		}
 
 		types = append(types, typeInfo)
@@ -145,6 +227,31 @@ func GetCustomTypes(ctx context.Context, pool *pgxpool.Pool) ([]map[string]inter
 	return types, nil
 }
 
+// BuiltinColumnTypes часто используемые встроенные типы PostgreSQL для выбора типа столбца
+var BuiltinColumnTypes = []string{
+	"SERIAL", "BIGSERIAL", "SMALLINT", "INTEGER", "BIGINT",
+	"NUMERIC(10, 2)", "REAL", "DOUBLE PRECISION", "MONEY",
+	"VARCHAR(255)", "CHAR(1)", "TEXT",
+	"BOOLEAN",
+	"DATE", "TIME", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "INTERVAL",
+	"UUID", "JSON", "JSONB", "BYTEA", "INET",
+}
+
+// GetColumnTypeOptions возвращает список типов для выбора типа столбца:
+// встроенные типы и пользовательские типы (ENUM, составные, домены, диапазоны) схемы public
+func GetColumnTypeOptions(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	options := append([]string(nil), BuiltinColumnTypes...)
+
+	types, err := GetCustomTypes(ctx, pool)
+	if err != nil {
+		return options, err
+	}
+	for _, t := range types {
+		options = append(options, t["type_name"].(string))
+	}
+	return options, nil
+}
+
 // GetEnumValues получает все значения для конкретного ENUM типа
 // Пример: GetEnumValues(ctx, pool, "status_enum")
 func GetEnumValues(ctx context.Context, pool *pgxpool.Pool, enumTypeName string) ([]string, error) {
@@ -179,10 +286,9 @@ func GetEnumValues(ctx context.Context, pool *pgxpool.Pool, enumTypeName string)
 	return values, rows.Err()
 }
 
-// GetCompositeTypeFields получает все поля для составного типа
+// GetCompositeTypeFields получает поля составного типа в порядке их объявления
 // Пример: GetCompositeTypeFields(ctx, pool, "address_type")
-// Возвращает: map[fieldName]fieldType
-func GetCompositeTypeFields(ctx context.Context, pool *pgxpool.Pool, compositeTypeName string) (map[string]string, error) {
+func GetCompositeTypeFields(ctx context.Context, pool *pgxpool.Pool, compositeTypeName string) ([]CompositeField, error) {
 	if err := validateSQLIdent(compositeTypeName); err != nil {
 		return nil, err
 	}
@@ -195,6 +301,7 @@ func GetCompositeTypeFields(ctx context.Context, pool *pgxpool.Pool, compositeTy
 	JOIN pg_type t ON a.attrelid = t.typrelid
 	WHERE t.typname = '%s'
 	AND a.attnum > 0
+	AND NOT a.attisdropped
 	ORDER BY a.attnum
 	`, compositeTypeName)
 
@@ -204,22 +311,30 @@ func GetCompositeTypeFields(ctx context.Context, pool *pgxpool.Pool, compositeTy
 	}
 	defer rows.Close()
 
-	fields := make(map[string]string)
+	var fields []CompositeField
 
 	for rows.Next() {
-		var fieldName, fieldType string
-		if err := rows.Scan(&fieldName, &fieldType); err != nil {
+		var field CompositeField
+		if err := rows.Scan(&field.Name, &field.Type); err != nil {
 			return nil, fmt.Errorf("ошибка чтения поля: %w", err)
 		}
-		fields[fieldName] = fieldType
+		fields = append(fields, field)
 	}
 
 	return fields, rows.Err()
 }
 
+// EnumValuePosition положение нового значения ENUM относительно существующего.
+// Пустой Neighbor — добавить в конец
+type EnumValuePosition struct {
+	Neighbor string // существующее значение
+	After    bool   // true — AFTER Neighbor, false — BEFORE Neighbor
+}
+
 // AddEnumValue добавляет новое значение к существующему ENUM типу
-// Пример: AddEnumValue(ctx, pool, "status_enum", "archived", "before_value")
-func AddEnumValue(ctx context.Context, pool *pgxpool.Pool, enumTypeName, newValue, beforeValue string) error {
+// ifNotExists — не считать ошибкой, если значение уже есть
+// Пример: AddEnumValue(ctx, pool, "status_enum", "archived", EnumValuePosition{Neighbor: "inactive", After: true}, true)
+func AddEnumValue(ctx context.Context, pool *pgxpool.Pool, enumTypeName, newValue string, position EnumValuePosition, ifNotExists bool) error {
 	if err := validateSQLIdent(enumTypeName); err != nil {
 		return err
 	}
@@ -228,13 +343,21 @@ func AddEnumValue(ctx context.Context, pool *pgxpool.Pool, enumTypeName, newValu
 		return fmt.Errorf("недопустимое значение ENUM: %w", err)
 	}
 
-	query := fmt.Sprintf("ALTER TYPE %s ADD VALUE '%s'", enumTypeName, newValue)
+	query := fmt.Sprintf("ALTER TYPE %s ADD VALUE", enumTypeName)
+	if ifNotExists {
+		query += " IF NOT EXISTS"
+	}
+	query += " " + quoteLiteral(newValue)
 
-	if beforeValue != "" {
-		if err := validateSQLIdent(beforeValue); err != nil {
-			return fmt.Errorf("недопустимое значение beforeValue: %w", err)
+	if position.Neighbor != "" {
+		if err := validateSQLIdent(position.Neighbor); err != nil {
+			return fmt.Errorf("недопустимое соседнее значение: %w", err)
+		}
+		if position.After {
+			query += " AFTER " + quoteLiteral(position.Neighbor)
+		} else {
+			query += " BEFORE " + quoteLiteral(position.Neighbor)
 		}
-		query += fmt.Sprintf(" BEFORE '%s'", beforeValue)
 	}
 
 	_, err := pool.Exec(ctx, query)
@@ -247,12 +370,36 @@ func AddEnumValue(ctx context.Context, pool *pgxpool.Pool, enumTypeName, newValu
 	return nil
 }
 
+// RenameEnumValue переименовывает значение ENUM; строки с этим значением меняются автоматически
+func RenameEnumValue(ctx context.Context, pool *pgxpool.Pool, enumTypeName, oldValue, newValue string) error {
+	if err := validateSQLIdent(enumTypeName); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(oldValue); err != nil {
+		return fmt.Errorf("недопустимое значение ENUM: %w", err)
+	}
+	if err := validateSQLIdent(newValue); err != nil {
+		return fmt.Errorf("недопустимое значение ENUM: %w", err)
+	}
+
+	query := fmt.Sprintf("ALTER TYPE %s RENAME VALUE %s TO %s", enumTypeName, quoteLiteral(oldValue), quoteLiteral(newValue))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Ошибка переименования значения ENUM: %v", err)
+		return fmt.Errorf("ошибка переименования значения '%s' в ENUM '%s': %w", oldValue, enumTypeName, err)
+	}
+
+	fmt.Printf("Значение '%s' ENUM '%s' переименовано в '%s'\n", oldValue, enumTypeName, newValue)
+	return nil
+}
+
 // TypeInfo получает информацию о типе
 type TypeInfo struct {
 	Name        string
-	Kind        string            // ENUM, COMPOSITE, BASE
-	Values      []string          // для ENUM
-	Fields      map[string]string // для COMPOSITE
+	Kind        string               // ENUM, COMPOSITE, DOMAIN, RANGE, BASE
+	Values      []string             // для ENUM
+	Fields      []CompositeField     // для COMPOSITE, в порядке объявления
+	Domain      *DomainDefinition    // для DOMAIN
+	Range       *RangeTypeDefinition // для RANGE
 	Description string
 }
 
@@ -283,6 +430,9 @@ func GetTypeInfo(ctx context.Context, pool *pgxpool.Pool, typeName string) (*Typ
 		Name: typeName,
 		Kind: foundType["type_kind"].(string),
 	}
+	if descPtr, ok := foundType["description"].(*string); ok && descPtr != nil {
+		info.Description = *descPtr
+	}
 
 	if info.Kind == "ENUM" {
 		values, err := GetEnumValues(ctx, pool, typeName)
@@ -300,5 +450,21 @@ func GetTypeInfo(ctx context.Context, pool *pgxpool.Pool, typeName string) (*Typ
 		info.Fields = fields
 	}
 
+	if info.Kind == "DOMAIN" {
+		domain, err := GetDomainInfo(ctx, pool, typeName)
+		if err != nil {
+			return nil, err
+		}
+		info.Domain = domain
+	}
+
+	if info.Kind == "RANGE" {
+		rangeType, err := GetRangeTypeInfo(ctx, pool, typeName)
+		if err != nil {
+			return nil, err
+		}
+		info.Range = rangeType
+	}
+
 	return info, nil
 }
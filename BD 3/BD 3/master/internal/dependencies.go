@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Виды объектов, для которых строится дерево зависимостей
+const (
+	DependencyTable            = "TABLE"
+	DependencyView             = "VIEW"
+	DependencyMaterializedView = "MATERIALIZED VIEW"
+	DependencyType             = "TYPE"
+	DependencyExtension        = "EXTENSION"
+)
+
+// DependentObject объект, который будет удалён вместе с исходным при DROP ... CASCADE
+type DependentObject struct {
+	Key         string // classid:objid:objsubid
+	ParentKey   string // ключ объекта, от которого он зависит ("" — от исходного объекта)
+	Level       int
+	Type        string // table, view, table column, function, ...
+	Description string // pg_describe_object
+	Automatic   bool   // deptype = 'a': удаляется автоматически даже при RESTRICT
+}
+
+// dropBehavior возвращает RESTRICT или CASCADE
+func dropBehavior(cascade bool) string {
+	if cascade {
+		return "CASCADE"
+	}
+	return "RESTRICT"
+}
+
+// dependencyRootQuery запросы поиска OID исходного объекта
+var dependencyRootQuery = map[string]string{
+	DependencyTable: `SELECT 'pg_class'::regclass::oid, c.oid FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind IN ('r', 'p')`,
+	DependencyView: `SELECT 'pg_class'::regclass::oid, c.oid FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind = 'v'`,
+	DependencyMaterializedView: `SELECT 'pg_class'::regclass::oid, c.oid FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind = 'm'`,
+	DependencyType: `SELECT 'pg_type'::regclass::oid, t.oid FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = 'public' AND t.typname = $1`,
+	DependencyExtension: `SELECT 'pg_extension'::regclass::oid, e.oid FROM pg_extension e
+		WHERE e.extname = $1`,
+}
+
+// GetDependentObjects обходит pg_depend и возвращает все объекты,
+// которые будут удалены вместе с исходным при DROP ... CASCADE.
+// Правила представлений (pg_rewrite) заменяются самими представлениями,
+// внутренние зависимости (deptype = 'i') и объекты расширения (deptype = 'e') обходятся, но не показываются.
+func GetDependentObjects(ctx context.Context, pool *pgxpool.Pool, kind, name string) ([]DependentObject, error) {
+	validate := validateSQLIdent
+	if kind == DependencyExtension {
+		validate = validateExtensionName
+	}
+	if err := validate(name); err != nil {
+		return nil, err
+	}
+	rootQuery, ok := dependencyRootQuery[kind]
+	if !ok {
+		return nil, fmt.Errorf("неподдерживаемый вид объекта: %s", kind)
+	}
+
+	var classID, objID uint32
+	if err := pool.QueryRow(ctx, rootQuery, name).Scan(&classID, &objID); err != nil {
+		return nil, fmt.Errorf("объект %s '%s' не найден: %w", kind, name, err)
+	}
+
+	query := `
+	WITH RECURSIVE deps AS (
+		SELECT $1::oid AS classid, $2::oid AS objid, 0 AS objsubid, 0 AS level,
+			'n'::"char" AS deptype, ''::text AS vparent, ARRAY[$2::oid] AS path
+		UNION ALL
+		SELECT o.classid, o.objid, o.objsubid, deps.level + 1, d.deptype,
+			CASE
+				WHEN deps.level = 0 THEN ''
+				WHEN deps.deptype IN ('i', 'e') THEN deps.vparent
+				ELSE deps.classid || ':' || deps.objid || ':' || deps.objsubid
+			END,
+			deps.path || o.objid
+		FROM deps
+		JOIN pg_depend d ON d.refclassid = deps.classid AND d.refobjid = deps.objid
+			AND (deps.objsubid = 0 OR d.refobjsubid = deps.objsubid)
+		CROSS JOIN LATERAL (
+			SELECT
+				CASE WHEN d.classid = 'pg_rewrite'::regclass THEN 'pg_class'::regclass::oid ELSE d.classid END AS classid,
+				CASE WHEN d.classid = 'pg_rewrite'::regclass
+					THEN (SELECT r.ev_class FROM pg_rewrite r WHERE r.oid = d.objid)
+					ELSE d.objid END AS objid,
+				CASE WHEN d.classid = 'pg_rewrite'::regclass THEN 0 ELSE d.objsubid END AS objsubid
+		) o
+		WHERE d.deptype IN ('n', 'a', 'i', 'e')
+		AND NOT o.objid = ANY(deps.path)
+		AND deps.level < 20
+	)
+	SELECT key, vparent, level, obj_type, description, deptype = 'a'
+	FROM (
+		SELECT DISTINCT ON (classid, objid, objsubid)
+			classid || ':' || objid || ':' || objsubid AS key,
+			vparent, level, deptype,
+			(pg_identify_object(classid, objid, objsubid)).type AS obj_type,
+			pg_describe_object(classid, objid, objsubid) AS description
+		FROM deps
+		WHERE level > 0 AND deptype NOT IN ('i', 'e')
+		AND classid <> 'pg_attrdef'::regclass
+		ORDER BY classid, objid, objsubid, level
+	) found
+	ORDER BY level, description
+	`
+
+	rows, err := pool.Query(ctx, query, classID, objID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения зависимостей: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []DependentObject
+	for rows.Next() {
+		var obj DependentObject
+		if err := rows.Scan(&obj.Key, &obj.ParentKey, &obj.Level, &obj.Type, &obj.Description, &obj.Automatic); err != nil {
+			return nil, fmt.Errorf("ошибка чтения зависимости: %w", err)
+		}
+		objects = append(objects, obj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Родитель мог быть отброшен DISTINCT ON или фильтром — тогда привязываем к корню
+	known := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		known[obj.Key] = true
+	}
+	for i := range objects {
+		if !known[objects[i].ParentKey] {
+			objects[i].ParentKey = ""
+		}
+	}
+	return objects, nil
+}
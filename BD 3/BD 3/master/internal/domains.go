@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DomainConstraint именованное ограничение CHECK домена; в выражении значение обозначается VALUE
+type DomainConstraint struct {
+	Name       string
+	Expression string // например: VALUE > 0
+	Validated  bool   // false для ограничений, добавленных с NOT VALID
+}
+
+// DomainDefinition домен: базовый тип с DEFAULT, NOT NULL и ограничениями CHECK
+type DomainDefinition struct {
+	Name     string
+	BaseType string
+	Default  string
+	NotNull  bool
+	Checks   []DomainConstraint
+}
+
+// BuildCreateDomainSQL формирует запрос CREATE DOMAIN (используется и для предпросмотра)
+func BuildCreateDomainSQL(domain DomainDefinition) string {
+	query := fmt.Sprintf("CREATE DOMAIN %s AS %s", domain.Name, domain.BaseType)
+	if def := strings.TrimSpace(domain.Default); def != "" {
+		query += " DEFAULT " + def
+	}
+	if domain.NotNull {
+		query += " NOT NULL"
+	}
+	for _, check := range domain.Checks {
+		query += fmt.Sprintf("\n\tCONSTRAINT %s CHECK (%s)", check.Name, check.Expression)
+	}
+	return query
+}
+
+// validateDomainConstraint проверяет имя и выражение ограничения домена
+func validateDomainConstraint(check DomainConstraint) error {
+	if err := validateSQLIdent(check.Name); err != nil {
+		return fmt.Errorf("недопустимое имя ограничения '%s': %w", check.Name, err)
+	}
+	if strings.TrimSpace(check.Expression) == "" {
+		return fmt.Errorf("выражение ограничения %s не может быть пустым", check.Name)
+	}
+	return nil
+}
+
+// CreateDomain создаёт домен
+// Пример: CreateDomain(ctx, pool, DomainDefinition{Name: "positive_price", BaseType: "NUMERIC(10, 2)",
+//
+//	NotNull: true, Checks: []DomainConstraint{{Name: "price_positive", Expression: "VALUE > 0"}}})
+func CreateDomain(ctx context.Context, pool *pgxpool.Pool, domain DomainDefinition) error {
+	if err := validateSQLIdent(domain.Name); err != nil {
+		return err
+	}
+	if strings.TrimSpace(domain.BaseType) == "" {
+		return fmt.Errorf("базовый тип домена не может быть пустым")
+	}
+	seen := make(map[string]bool)
+	for _, check := range domain.Checks {
+		if err := validateDomainConstraint(check); err != nil {
+			return err
+		}
+		if seen[check.Name] {
+			return fmt.Errorf("ограничение %s указано дважды", check.Name)
+		}
+		seen[check.Name] = true
+	}
+
+	if _, err := pool.Exec(ctx, BuildCreateDomainSQL(domain)); err != nil {
+		log.Printf("Ошибка создания домена: %v", err)
+		return fmt.Errorf("ошибка создания домена %s: %w", domain.Name, err)
+	}
+
+	fmt.Printf("Домен '%s' успешно создан на основе %s\n", domain.Name, domain.BaseType)
+	return nil
+}
+
+// GetDomainInfo возвращает определение домена с его ограничениями
+func GetDomainInfo(ctx context.Context, pool *pgxpool.Pool, domainName string) (*DomainDefinition, error) {
+	if err := validateSQLIdent(domainName); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT format_type(t.typbasetype, t.typtypmod), COALESCE(t.typdefault, ''), t.typnotnull
+	FROM pg_type t
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	WHERE n.nspname = 'public' AND t.typtype = 'd' AND t.typname = $1
+	`
+
+	domain := &DomainDefinition{Name: domainName}
+	err := pool.QueryRow(ctx, query, domainName).Scan(&domain.BaseType, &domain.Default, &domain.NotNull)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("домен '%s' не найден", domainName)
+		}
+		return nil, fmt.Errorf("ошибка получения домена: %w", err)
+	}
+
+	constraintsQuery := `
+	SELECT c.conname, pg_get_constraintdef(c.oid), c.convalidated
+	FROM pg_constraint c
+	JOIN pg_type t ON t.oid = c.contypid
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	WHERE n.nspname = 'public' AND t.typname = $1 AND c.contype = 'c'
+	ORDER BY c.conname
+	`
+
+	rows, err := pool.Query(ctx, constraintsQuery, domainName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ограничений домена: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var check DomainConstraint
+		var definition string
+		if err := rows.Scan(&check.Name, &definition, &check.Validated); err != nil {
+			return nil, fmt.Errorf("ошибка чтения ограничения домена: %w", err)
+		}
+		// pg_get_constraintdef возвращает "CHECK ((VALUE > 0))" или "... NOT VALID"
+		definition = strings.TrimSuffix(definition, " NOT VALID")
+		definition = strings.TrimPrefix(definition, "CHECK ")
+		if strings.HasPrefix(definition, "(") && strings.HasSuffix(definition, ")") {
+			definition = definition[1 : len(definition)-1]
+		}
+		check.Expression = definition
+		domain.Checks = append(domain.Checks, check)
+	}
+
+	return domain, rows.Err()
+}
+
+// alterDomain выполняет ALTER DOMAIN с общей обработкой ошибок
+func alterDomain(ctx context.Context, pool *pgxpool.Pool, domainName, action, description string) error {
+	if err := validateSQLIdent(domainName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER DOMAIN %s %s", domainName, action)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Изменение домена: %v", err)
+		return fmt.Errorf("ошибка изменения домена %s (%s): %w", domainName, description, err)
+	}
+
+	fmt.Printf("Домен '%s': %s\n", domainName, description)
+	return nil
+}
+
+// AddDomainConstraint добавляет ограничение CHECK домену.
+// notValid — не проверять существующие данные (ограничение можно проверить позже)
+func AddDomainConstraint(ctx context.Context, pool *pgxpool.Pool, domainName string, check DomainConstraint, notValid bool) error {
+	if err := validateDomainConstraint(check); err != nil {
+		return err
+	}
+	action := fmt.Sprintf("ADD CONSTRAINT %s CHECK (%s)", check.Name, check.Expression)
+	if notValid {
+		action += " NOT VALID"
+	}
+	return alterDomain(ctx, pool, domainName, action, "добавлено ограничение "+check.Name)
+}
+
+// DropDomainConstraint удаляет ограничение домена
+func DropDomainConstraint(ctx context.Context, pool *pgxpool.Pool, domainName, constraintName string) error {
+	if err := validateSQLIdent(constraintName); err != nil {
+		return err
+	}
+	return alterDomain(ctx, pool, domainName, "DROP CONSTRAINT "+constraintName, "удалено ограничение "+constraintName)
+}
+
+// ValidateDomainConstraint проверяет существующие данные для ограничения, добавленного с NOT VALID
+func ValidateDomainConstraint(ctx context.Context, pool *pgxpool.Pool, domainName, constraintName string) error {
+	if err := validateSQLIdent(constraintName); err != nil {
+		return err
+	}
+	return alterDomain(ctx, pool, domainName, "VALIDATE CONSTRAINT "+constraintName, "проверено ограничение "+constraintName)
+}
+
+// SetDomainDefault устанавливает значение по умолчанию домена; пустое выражение удаляет DEFAULT
+func SetDomainDefault(ctx context.Context, pool *pgxpool.Pool, domainName, expression string) error {
+	if expression = strings.TrimSpace(expression); expression == "" {
+		return alterDomain(ctx, pool, domainName, "DROP DEFAULT", "удалено DEFAULT")
+	}
+	return alterDomain(ctx, pool, domainName, "SET DEFAULT "+expression, "установлено DEFAULT "+expression)
+}
+
+// SetDomainNotNull устанавливает или снимает NOT NULL домена
+func SetDomainNotNull(ctx context.Context, pool *pgxpool.Pool, domainName string, notNull bool) error {
+	if notNull {
+		return alterDomain(ctx, pool, domainName, "SET NOT NULL", "установлено NOT NULL")
+	}
+	return alterDomain(ctx, pool, domainName, "DROP NOT NULL", "снято NOT NULL")
+}
+
+// DropDomain удаляет домен
+// cascade — удалить также зависимые столбцы, иначе RESTRICT
+func DropDomain(ctx context.Context, pool *pgxpool.Pool, domainName string, cascade bool) error {
+	if err := validateSQLIdent(domainName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DROP DOMAIN IF EXISTS %s %s", domainName, dropBehavior(cascade))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Ошибка удаления домена: %v", err)
+		return fmt.Errorf("ошибка удаления домена %s: %w", domainName, err)
+	}
+
+	fmt.Printf("Домен '%s' успешно удален\n", domainName)
+	return nil
+}
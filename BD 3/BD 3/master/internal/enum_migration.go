@@ -0,0 +1,370 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EnumColumn столбец таблицы, использующий ENUM тип напрямую или как массив
+type EnumColumn struct {
+	Table   string
+	Column  string
+	IsArray bool
+	Default string // выражение DEFAULT, пустое если не задано
+}
+
+// GetEnumColumns возвращает столбцы таблиц схемы public, имеющие тип enumTypeName или enumTypeName[].
+// Унаследованные столбцы секций не возвращаются — они изменяются вместе с родительской таблицей
+func GetEnumColumns(ctx context.Context, pool *pgxpool.Pool, enumTypeName string) ([]EnumColumn, error) {
+	if err := validateSQLIdent(enumTypeName); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT c.relname, a.attname, a.atttypid = t.typarray, COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_type t ON a.atttypid IN (t.oid, t.typarray)
+	JOIN pg_namespace tn ON tn.oid = t.typnamespace
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE tn.nspname = 'public' AND t.typname = $1
+	AND n.nspname = 'public' AND c.relkind IN ('r', 'p')
+	AND a.attnum > 0 AND NOT a.attisdropped AND a.attinhcount = 0
+	ORDER BY c.relname, a.attnum
+	`
+
+	rows, err := pool.Query(ctx, query, enumTypeName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения столбцов типа: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []EnumColumn
+	for rows.Next() {
+		var col EnumColumn
+		if err := rows.Scan(&col.Table, &col.Column, &col.IsArray, &col.Default); err != nil {
+			return nil, fmt.Errorf("ошибка чтения столбца: %w", err)
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+// CountEnumValueRows возвращает количество строк с каждым значением ENUM в столбце;
+// для массивов считаются строки, содержащие значение хотя бы один раз
+func CountEnumValueRows(ctx context.Context, pool *pgxpool.Pool, col EnumColumn) (map[string]int64, error) {
+	if err := validateSQLIdent(col.Table); err != nil {
+		return nil, err
+	}
+	if err := validateSQLIdent(col.Column); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %[2]s::text, COUNT(*) FROM %[1]s WHERE %[2]s IS NOT NULL GROUP BY 1", col.Table, col.Column)
+	if col.IsArray {
+		// ctid уникален только внутри одной секции, поэтому строку определяет пара (tableoid, ctid)
+		query = fmt.Sprintf(`SELECT e::text, COUNT(DISTINCT (t.tableoid, t.ctid)) FROM %[1]s t, unnest(t.%[2]s) e
+		WHERE e IS NOT NULL GROUP BY 1`, col.Table, col.Column)
+	}
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта значений %s.%s: %w", col.Table, col.Column, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var value string
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, fmt.Errorf("ошибка чтения количества: %w", err)
+		}
+		counts[value] = count
+	}
+	return counts, rows.Err()
+}
+
+// enumBlockingDependencies возвращает описания объектов, которые используют ENUM тип,
+// но не могут быть перенесены на новый тип автоматически (домены, функции, представления, составные типы)
+func enumBlockingDependencies(ctx context.Context, pool *pgxpool.Pool, enumTypeName string) ([]string, error) {
+	query := `
+	SELECT DISTINCT pg_describe_object(d.classid, d.objid, d.objsubid)
+	FROM pg_depend d
+	JOIN pg_type t ON d.refobjid IN (t.oid, t.typarray) AND d.refclassid = 'pg_type'::regclass
+	JOIN pg_namespace tn ON tn.oid = t.typnamespace
+	WHERE tn.nspname = 'public' AND t.typname = $1
+	AND d.deptype = 'n'
+	AND d.classid <> 'pg_attrdef'::regclass
+	AND NOT (d.classid = 'pg_class'::regclass AND EXISTS (
+		SELECT 1 FROM pg_class c WHERE c.oid = d.objid AND c.relkind IN ('r', 'p', 'i', 'I')
+	))
+	ORDER BY 1
+	`
+
+	rows, err := pool.Query(ctx, query, enumTypeName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки зависимостей типа: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []string
+	for rows.Next() {
+		var object string
+		if err := rows.Scan(&object); err != nil {
+			return nil, fmt.Errorf("ошибка чтения зависимости: %w", err)
+		}
+		objects = append(objects, object)
+	}
+	return objects, rows.Err()
+}
+
+// enumMappingSQL формирует выражение, переводящее текстовое значение старого ENUM в новый тип
+func enumMappingSQL(value string, mapping map[string]string, newTypeName string) string {
+	if len(mapping) == 0 {
+		return fmt.Sprintf("(%s)::text::%s", value, newTypeName)
+	}
+	expr := fmt.Sprintf("CASE (%s)::text", value)
+	for from, to := range mapping {
+		target := "NULL"
+		if to != "" {
+			target = quoteLiteral(to)
+		}
+		expr += fmt.Sprintf(" WHEN %s THEN %s", quoteLiteral(from), target)
+	}
+	expr += fmt.Sprintf(" ELSE (%s)::text END::%s", value, newTypeName)
+	return expr
+}
+
+// enumArrayMappingSQL формирует выражение для столбца-массива ENUM. Подзапросы в USING
+// у ALTER COLUMN TYPE запрещены, поэтому замены выполняются вложенными array_replace
+func enumArrayMappingSQL(value string, mapping map[string]string, newTypeName string) string {
+	froms := make([]string, 0, len(mapping))
+	for from := range mapping {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
+	expr := fmt.Sprintf("(%s)::text[]", value)
+	for _, from := range froms {
+		target := "NULL::text"
+		if to := mapping[from]; to != "" {
+			target = quoteLiteral(to)
+		}
+		expr = fmt.Sprintf("array_replace(%s, %s, %s)", expr, quoteLiteral(from), target)
+	}
+	return fmt.Sprintf("%s::%s[]", expr, newTypeName)
+}
+
+// enumArrayLiteralRe находит литералы массивов вида '{a,b}':: в выражении DEFAULT
+var enumArrayLiteralRe = regexp.MustCompile(`'\{([^']*)\}'::`)
+
+// replaceEnumDefaultValues заменяет удалённые значения в выражении DEFAULT: как скаляры 'value'::type
+// и как элементы литералов массивов '{a,b}'::type[]
+func replaceEnumDefaultValues(def string, mapping map[string]string) string {
+	for from, to := range mapping {
+		target := "NULL"
+		if to != "" {
+			target = quoteLiteral(to)
+		}
+		def = strings.ReplaceAll(def, quoteLiteral(from)+"::", target+"::")
+	}
+
+	return enumArrayLiteralRe.ReplaceAllStringFunc(def, func(literal string) string {
+		body := enumArrayLiteralRe.FindStringSubmatch(literal)[1]
+		if body == "" {
+			return literal
+		}
+		elements := strings.Split(body, ",")
+		for i, element := range elements {
+			to, ok := mapping[strings.Trim(element, `"`)]
+			if !ok {
+				continue
+			}
+			if to == "" {
+				elements[i] = "NULL"
+			} else {
+				elements[i] = `"` + to + `"`
+			}
+		}
+		return "'{" + strings.Join(elements, ",") + "}'::"
+	})
+}
+
+// RecreateEnumType заменяет ENUM тип новым набором значений в одной транзакции:
+// создаёт тип-замену, переводит все столбцы таблиц на него (mapping задаёт замену
+// удаляемых значений, пустая строка — NULL), удаляет старый тип и переименовывает новый.
+// Так выполняются удаление и переупорядочивание значений, которых нет в ALTER TYPE.
+// Возвращает количество перенесённых столбцов.
+func RecreateEnumType(ctx context.Context, pool *pgxpool.Pool, enumTypeName string, newValues []string, mapping map[string]string) (int, error) {
+	if err := validateSQLIdent(enumTypeName); err != nil {
+		return 0, err
+	}
+	if len(newValues) == 0 {
+		return 0, fmt.Errorf("список значений ENUM не может быть пустым")
+	}
+
+	kept := make(map[string]bool)
+	var literals []string
+	for _, value := range newValues {
+		if err := validateSQLIdent(value); err != nil {
+			return 0, fmt.Errorf("недопустимое значение ENUM '%s': %w", value, err)
+		}
+		if kept[value] {
+			return 0, fmt.Errorf("значение '%s' указано дважды", value)
+		}
+		kept[value] = true
+		literals = append(literals, quoteLiteral(value))
+	}
+
+	oldValues, err := GetEnumValues(ctx, pool, enumTypeName)
+	if err != nil {
+		return 0, err
+	}
+	if len(oldValues) == 0 {
+		return 0, fmt.Errorf("ENUM тип '%s' не найден", enumTypeName)
+	}
+	for _, value := range oldValues {
+		if kept[value] {
+			continue
+		}
+		target, ok := mapping[value]
+		if !ok {
+			return 0, fmt.Errorf("для удаляемого значения '%s' не указана замена", value)
+		}
+		if target != "" && !kept[target] {
+			return 0, fmt.Errorf("значение-замена '%s' отсутствует в новом списке значений", target)
+		}
+	}
+
+	blocking, err := enumBlockingDependencies(ctx, pool, enumTypeName)
+	if err != nil {
+		return 0, err
+	}
+	if len(blocking) > 0 {
+		return 0, fmt.Errorf("тип %s используют объекты, которые нельзя перенести автоматически: %s",
+			enumTypeName, strings.Join(blocking, "; "))
+	}
+
+	columns, err := GetEnumColumns(ctx, pool, enumTypeName)
+	if err != nil {
+		return 0, err
+	}
+
+	// Только удаляемые значения требуют замены; остальные переводятся по тексту
+	removed := make(map[string]string)
+	for from, to := range mapping {
+		if !kept[from] {
+			removed[from] = to
+		}
+	}
+
+	tempName := enumTypeName + "__new"
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("CREATE TYPE %s AS ENUM (%s)", tempName, strings.Join(literals, ", "))); err != nil {
+		log.Printf("Пересоздание ENUM: %v", err)
+		return 0, fmt.Errorf("ошибка создания типа-замены %s: %w", tempName, err)
+	}
+
+	for _, col := range columns {
+		// DEFAULT ссылается на старый тип и мешает изменению — восстанавливается после переименования
+		if col.Default != "" {
+			if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", col.Table, col.Column)); err != nil {
+				return 0, fmt.Errorf("ошибка удаления DEFAULT %s.%s: %w", col.Table, col.Column, err)
+			}
+		}
+
+		using := enumMappingSQL(col.Column, removed, tempName)
+		newType := tempName
+		if col.IsArray {
+			using = enumArrayMappingSQL(col.Column, removed, tempName)
+			newType += "[]"
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s", col.Table, col.Column, newType, using)
+		if _, err := tx.Exec(ctx, query); err != nil {
+			log.Printf("Пересоздание ENUM: %v", err)
+			return 0, fmt.Errorf("ошибка переноса столбца %s.%s на новый тип: %w", col.Table, col.Column, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DROP TYPE %s", enumTypeName)); err != nil {
+		return 0, fmt.Errorf("ошибка удаления старого типа %s: %w", enumTypeName, err)
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TYPE %s RENAME TO %s", tempName, enumTypeName)); err != nil {
+		return 0, fmt.Errorf("ошибка переименования типа-замены: %w", err)
+	}
+
+	for _, col := range columns {
+		if col.Default == "" {
+			continue
+		}
+		// В выражении DEFAULT значения записаны как 'value'::type или '{a,b}'::type[] — заменяем удалённые
+		def := replaceEnumDefaultValues(col.Default, removed)
+		query := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", col.Table, col.Column, def)
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return 0, fmt.Errorf("ошибка восстановления DEFAULT %s.%s: %w", col.Table, col.Column, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("Пересоздание ENUM: %v", err)
+		return 0, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+
+	fmt.Printf("ENUM тип '%s' пересоздан: %d значений, перенесено столбцов: %d\n", enumTypeName, len(newValues), len(columns))
+	return len(columns), nil
+}
+
+// RemoveEnumValue удаляет значение ENUM через пересоздание типа.
+// Строки с удаляемым значением получают replacement (пустая строка — NULL)
+// Пример: RemoveEnumValue(ctx, pool, "status_enum", "pending", "active")
+func RemoveEnumValue(ctx context.Context, pool *pgxpool.Pool, enumTypeName, value, replacement string) (int, error) {
+	values, err := GetEnumValues(ctx, pool, enumTypeName)
+	if err != nil {
+		return 0, err
+	}
+
+	var remaining []string
+	found := false
+	for _, v := range values {
+		if v == value {
+			found = true
+			continue
+		}
+		remaining = append(remaining, v)
+	}
+	if !found {
+		return 0, fmt.Errorf("значение '%s' отсутствует в ENUM '%s'", value, enumTypeName)
+	}
+	if len(remaining) == 0 {
+		return 0, fmt.Errorf("нельзя удалить единственное значение ENUM — удалите тип целиком")
+	}
+
+	return RecreateEnumType(ctx, pool, enumTypeName, remaining, map[string]string{value: replacement})
+}
+
+// ReorderEnumValues задаёт новый порядок значений ENUM через пересоздание типа
+func ReorderEnumValues(ctx context.Context, pool *pgxpool.Pool, enumTypeName string, orderedValues []string) (int, error) {
+	values, err := GetEnumValues(ctx, pool, enumTypeName)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) != len(orderedValues) {
+		return 0, fmt.Errorf("новый порядок должен содержать все %d значений ENUM", len(values))
+	}
+	return RecreateEnumType(ctx, pool, enumTypeName, orderedValues, nil)
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ===== Возможности, зависящие от расширений PostgreSQL =====
+
+// CrosstabAggregates агрегатные функции для значений сводной таблицы
+var CrosstabAggregates = []string{"SUM", "COUNT", "AVG", "MIN", "MAX"}
+
+// Crosstab строит сводную таблицу функцией crosstab (расширение tablefunc):
+// строки — значения rowCol, столбцы — значения categoryCol, ячейки — aggregate(valueCol).
+// Первая строка результата — заголовки
+// Пример: Crosstab(ctx, pool, "orders", "customer_id", "status", "amount", "SUM")
+func Crosstab(ctx context.Context, pool *pgxpool.Pool, table, rowCol, categoryCol, valueCol, aggregate string) ([][]string, error) {
+	for _, ident := range []string{table, rowCol, categoryCol, valueCol} {
+		if err := validateSQLIdent(ident); err != nil {
+			return nil, err
+		}
+	}
+	aggregate = strings.ToUpper(strings.TrimSpace(aggregate))
+	supported := false
+	for _, agg := range CrosstabAggregates {
+		if agg == aggregate {
+			supported = true
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("неподдерживаемая агрегатная функция: %s", aggregate)
+	}
+
+	// crosstab требует заранее перечислить выходные столбцы — берём их из значений категории
+	categoriesQuery := fmt.Sprintf("SELECT DISTINCT %[2]s::text FROM %[1]s WHERE %[2]s IS NOT NULL ORDER BY 1", table, categoryCol)
+	rows, err := pool.Query(ctx, categoriesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения категорий: %w", err)
+	}
+	var categories []string
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("ошибка чтения категории: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("в столбце %s нет значений для категорий", categoryCol)
+	}
+
+	columnDefs := []string{"row_name text"}
+	for _, category := range categories {
+		columnDefs = append(columnDefs, fmt.Sprintf(`"%s" text`, strings.ReplaceAll(category, `"`, `""`)))
+	}
+
+	source := fmt.Sprintf("SELECT %[2]s::text, %[3]s::text, %[5]s(%[4]s)::text FROM %[1]s GROUP BY 1, 2 ORDER BY 1, 2",
+		table, rowCol, categoryCol, valueCol, aggregate)
+	query := fmt.Sprintf("SELECT * FROM crosstab(%s, %s) AS ct(%s)",
+		quoteLiteral(source), quoteLiteral(categoriesQuery), strings.Join(columnDefs, ", "))
+
+	result, err := queryAsStrings(ctx, pool, query)
+	if err != nil {
+		log.Printf("Ошибка построения сводной таблицы: %v", err)
+		return nil, fmt.Errorf("ошибка построения сводной таблицы: %w", err)
+	}
+	result[0][0] = rowCol
+	return result, nil
+}
+
+// GetStatementStats возвращает самые затратные запросы из pg_stat_statements
+// (расширение должно быть загружено через shared_preload_libraries). Первая строка — заголовки
+func GetStatementStats(ctx context.Context, pool *pgxpool.Pool, limit int) ([][]string, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+	SELECT
+		left(regexp_replace(query, '\s+', ' ', 'g'), 200) AS "Запрос",
+		calls::text AS "Вызовов",
+		round(total_exec_time::numeric, 2)::text AS "Всего, мс",
+		round(mean_exec_time::numeric, 2)::text AS "Среднее, мс",
+		rows::text AS "Строк",
+		round(100.0 * shared_blks_hit / NULLIF(shared_blks_hit + shared_blks_read, 0), 1)::text AS "Попадания в кэш, %"
+	FROM pg_stat_statements
+	WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+	ORDER BY total_exec_time DESC
+	LIMIT $1
+	`
+
+	result, err := queryAsStrings(ctx, pool, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения pg_stat_statements (проверьте shared_preload_libraries): %w", err)
+	}
+	return result, nil
+}
+
+// ResetStatementStats сбрасывает накопленную статистику pg_stat_statements
+func ResetStatementStats(ctx context.Context, pool *pgxpool.Pool) error {
+	if _, err := pool.Exec(ctx, "SELECT pg_stat_statements_reset()"); err != nil {
+		log.Printf("Сброс статистики запросов: %v", err)
+		return fmt.Errorf("ошибка сброса статистики запросов: %w", err)
+	}
+	return nil
+}
+
+// DigestAlgorithms алгоритмы функции digest() расширения pgcrypto
+var DigestAlgorithms = []string{"sha256", "sha512", "sha1", "md5"}
+
+// PreviewColumnDigest показывает значения столбца рядом с их дайджестом (pgcrypto digest()).
+// Только чтение: данные таблицы не изменяются. Первая строка результата — заголовки
+// Пример: PreviewColumnDigest(ctx, pool, "users", "email", "sha256", 100)
+func PreviewColumnDigest(ctx context.Context, pool *pgxpool.Pool, table, col, algorithm string, limit int) ([][]string, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return nil, err
+	}
+	if err := validateSQLIdent(col); err != nil {
+		return nil, err
+	}
+	supported := false
+	for _, alg := range DigestAlgorithms {
+		if alg == algorithm {
+			supported = true
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("неизвестный алгоритм хеширования: %s", algorithm)
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := fmt.Sprintf(`SELECT %[2]s::text AS %[2]s, encode(digest(%[2]s::text, '%[3]s'), 'hex') AS "%[3]s"
+		FROM %[1]s WHERE %[2]s IS NOT NULL LIMIT $1`, table, col, algorithm)
+	result, err := queryAsStrings(ctx, pool, query, limit)
+	if err != nil {
+		log.Printf("Предпросмотр дайджеста: %v", err)
+		return nil, fmt.Errorf("ошибка вычисления дайджеста %s.%s: %w", table, col, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExtensionInfo расширение из pg_available_extensions
+type ExtensionInfo struct {
+	Name             string
+	DefaultVersion   string
+	InstalledVersion string // пусто, если расширение не установлено
+	Schema           string // схема установленного расширения
+	Relocatable      bool   // можно перенести в другую схему через ALTER EXTENSION SET SCHEMA
+	Comment          string
+}
+
+// Installed сообщает, установлено ли расширение в текущей базе
+func (e ExtensionInfo) Installed() bool {
+	return e.InstalledVersion != ""
+}
+
+// UpgradeAvailable сообщает, есть ли версия новее установленной
+func (e ExtensionInfo) UpgradeAvailable() bool {
+	return e.Installed() && e.DefaultVersion != "" && e.DefaultVersion != e.InstalledVersion
+}
+
+// ExtensionFeatures возможности приложения, которым нужны расширения PostgreSQL
+var ExtensionFeatures = map[string]string{
+	"pg_trgm":            "нечёткий поиск по сходству строк (оператор %)",
+	"tablefunc":          "сводные таблицы (crosstab)",
+	"pgcrypto":           "предпросмотр дайджеста значений столбца (digest)",
+	"pg_stat_statements": "статистика выполнения запросов",
+}
+
+// validateExtensionName проверяет имя расширения; в отличие от идентификаторов допускает дефис (uuid-ossp)
+func validateExtensionName(name string) error {
+	if !regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`).MatchString(name) {
+		return fmt.Errorf("недопустимое имя расширения: %s", name)
+	}
+	return nil
+}
+
+// quoteExtensionName заключает имя расширения в кавычки, если оно содержит дефис
+func quoteExtensionName(name string) string {
+	if strings.Contains(name, "-") {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// GetExtensions возвращает доступные на сервере расширения с установленными версиями
+func GetExtensions(ctx context.Context, pool *pgxpool.Pool) ([]ExtensionInfo, error) {
+	query := `
+	SELECT a.name, COALESCE(a.default_version, ''), COALESCE(a.installed_version, ''),
+		COALESCE(n.nspname, ''),
+		COALESCE(e.extrelocatable, v.relocatable, false),
+		COALESCE(a.comment, '')
+	FROM pg_available_extensions a
+	LEFT JOIN pg_extension e ON e.extname = a.name
+	LEFT JOIN pg_namespace n ON n.oid = e.extnamespace
+	LEFT JOIN pg_available_extension_versions v ON v.name = a.name AND v.version = a.default_version
+	ORDER BY a.installed_version IS NULL, a.name
+	`
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения расширений: %w", err)
+	}
+	defer rows.Close()
+
+	var extensions []ExtensionInfo
+	for rows.Next() {
+		var ext ExtensionInfo
+		if err := rows.Scan(&ext.Name, &ext.DefaultVersion, &ext.InstalledVersion, &ext.Schema,
+			&ext.Relocatable, &ext.Comment); err != nil {
+			return nil, fmt.Errorf("ошибка чтения расширения: %w", err)
+		}
+		extensions = append(extensions, ext)
+	}
+	return extensions, rows.Err()
+}
+
+// GetExtensionVersions возвращает версии расширения, доступные для установки
+func GetExtensionVersions(ctx context.Context, pool *pgxpool.Pool, name string) ([]string, error) {
+	if err := validateExtensionName(name); err != nil {
+		return nil, err
+	}
+
+	rows, err := pool.Query(ctx, "SELECT version FROM pg_available_extension_versions WHERE name = $1 ORDER BY version", name)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения версий расширения: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []string
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("ошибка чтения версии: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// GetSchemas возвращает пользовательские схемы базы данных
+func GetSchemas(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	query := `
+	SELECT nspname FROM pg_namespace
+	WHERE nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+	AND nspname NOT LIKE 'pg_temp_%' AND nspname NOT LIKE 'pg_toast_temp_%'
+	ORDER BY nspname
+	`
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения схем: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, fmt.Errorf("ошибка чтения схемы: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, rows.Err()
+}
+
+// GetExtensionStatus сообщает, установлено ли расширение и доступно ли оно на сервере
+func GetExtensionStatus(ctx context.Context, pool *pgxpool.Pool, name string) (installed, available bool, err error) {
+	if err := validateExtensionName(name); err != nil {
+		return false, false, err
+	}
+
+	query := `
+	SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = $1),
+		EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = $1)
+	`
+	if err := pool.QueryRow(ctx, query, name).Scan(&installed, &available); err != nil {
+		return false, false, fmt.Errorf("ошибка проверки расширения %s: %w", name, err)
+	}
+	return installed, available, nil
+}
+
+// InstallExtension устанавливает расширение (CREATE EXTENSION IF NOT EXISTS)
+// schema и version необязательны; cascade — установить также необходимые расширения
+// Пример: InstallExtension(ctx, pool, "pg_trgm", "public", "", false)
+func InstallExtension(ctx context.Context, pool *pgxpool.Pool, name, schema, version string, cascade bool) error {
+	if err := validateExtensionName(name); err != nil {
+		return err
+	}
+
+	query := "CREATE EXTENSION IF NOT EXISTS " + quoteExtensionName(name)
+	if schema = strings.TrimSpace(schema); schema != "" {
+		if err := validateSQLIdent(schema); err != nil {
+			return err
+		}
+		query += " SCHEMA " + schema
+	}
+	if version = strings.TrimSpace(version); version != "" {
+		query += " VERSION " + quoteLiteral(version)
+	}
+	if cascade {
+		query += " CASCADE"
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Ошибка установки расширения: %v", err)
+		return fmt.Errorf("ошибка установки расширения %s: %w", name, err)
+	}
+
+	fmt.Printf("Расширение '%s' установлено\n", name)
+	return nil
+}
+
+// UpgradeExtension обновляет расширение до версии version (пусто — до версии по умолчанию)
+func UpgradeExtension(ctx context.Context, pool *pgxpool.Pool, name, version string) error {
+	if err := validateExtensionName(name); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER EXTENSION %s UPDATE", quoteExtensionName(name))
+	if version = strings.TrimSpace(version); version != "" {
+		query += " TO " + quoteLiteral(version)
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Ошибка обновления расширения: %v", err)
+		return fmt.Errorf("ошибка обновления расширения %s: %w", name, err)
+	}
+
+	fmt.Printf("Расширение '%s' обновлено\n", name)
+	return nil
+}
+
+// SetExtensionSchema переносит объекты расширения в другую схему
+func SetExtensionSchema(ctx context.Context, pool *pgxpool.Pool, name, schema string) error {
+	if err := validateExtensionName(name); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(schema); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s", quoteExtensionName(name), schema)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Ошибка переноса расширения: %v", err)
+		return fmt.Errorf("ошибка переноса расширения %s в схему %s: %w", name, schema, err)
+	}
+
+	fmt.Printf("Расширение '%s' перенесено в схему %s\n", name, schema)
+	return nil
+}
+
+// DropExtension удаляет расширение
+// cascade — удалить также объекты, зависящие от расширения (например, индексы с его классами операторов)
+func DropExtension(ctx context.Context, pool *pgxpool.Pool, name string, cascade bool) error {
+	if err := validateExtensionName(name); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DROP EXTENSION IF EXISTS %s %s", quoteExtensionName(name), dropBehavior(cascade))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Ошибка удаления расширения: %v", err)
+		return fmt.Errorf("ошибка удаления расширения %s: %w", name, err)
+	}
+
+	fmt.Printf("Расширение '%s' удалено\n", name)
+	return nil
+}
@@ -49,13 +49,13 @@ func CreateOrReplaceView(ctx context.Context, pool *pgxpool.Pool, viewName strin
 	return nil
 }
 
-// DropView drops an existing view
-func DropView(ctx context.Context, pool *pgxpool.Pool, viewName string) error {
+// DropView drops an existing view (cascade also drops dependent objects, otherwise RESTRICT)
+func DropView(ctx context.Context, pool *pgxpool.Pool, viewName string, cascade bool) error {
 	if err := validateSQLIdent(viewName); err != nil {
 		return err
 	}
 
-	query := fmt.Sprintf("DROP VIEW IF EXISTS %s CASCADE", viewName)
+	query := fmt.Sprintf("DROP VIEW IF EXISTS %s %s", viewName, dropBehavior(cascade))
 	_, err := pool.Exec(ctx, query)
 	if err != nil {
 		log.Printf("Error dropping view: %v", err)
@@ -154,13 +154,13 @@ func RefreshMaterializedView(ctx context.Context, pool *pgxpool.Pool, mvName str
 	return nil
 }
 
-// DropMaterializedView drops a materialized view
-func DropMaterializedView(ctx context.Context, pool *pgxpool.Pool, mvName string) error {
+// DropMaterializedView drops a materialized view (cascade also drops dependent objects, otherwise RESTRICT)
+func DropMaterializedView(ctx context.Context, pool *pgxpool.Pool, mvName string, cascade bool) error {
 	if err := validateSQLIdent(mvName); err != nil {
 		return err
 	}
 
-	query := fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s CASCADE", mvName)
+	query := fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s %s", mvName, dropBehavior(cascade))
 	_, err := pool.Exec(ctx, query)
 	if err != nil {
 		log.Printf("Error dropping materialized view: %v", err)
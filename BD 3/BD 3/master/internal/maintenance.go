@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TableMaintenanceStats размеры и статистика обслуживания таблицы
+type TableMaintenanceStats struct {
+	Table           string
+	TableSize       string // основная часть (heap)
+	IndexesSize     string
+	ToastSize       string
+	TotalSize       string
+	LiveTuples      int64
+	DeadTuples      int64
+	DeadRatio       float64 // доля мёртвых строк, %
+	LastVacuum      string
+	LastAutovacuum  string
+	LastAnalyze     string
+	LastAutoanalyze string
+	VacuumCount     int64
+	AnalyzeCount    int64
+}
+
+// MaintenanceProgress текущий прогресс операции обслуживания из pg_stat_progress_*
+type MaintenanceProgress struct {
+	Command string // VACUUM, VACUUM FULL, ANALYZE, REINDEX
+	Phase   string
+	Done    int64 // обработано блоков
+	Total   int64 // всего блоков
+}
+
+// Fraction возвращает долю выполнения от 0 до 1
+func (mp MaintenanceProgress) Fraction() float64 {
+	if mp.Total <= 0 {
+		return 0
+	}
+	return float64(mp.Done) / float64(mp.Total)
+}
+
+// maintenanceStatsQuery общая часть запроса статистики обслуживания
+const maintenanceStatsQuery = `
+	SELECT
+		s.relname,
+		pg_size_pretty(pg_relation_size(s.relid)),
+		pg_size_pretty(pg_indexes_size(s.relid)),
+		pg_size_pretty(COALESCE(pg_total_relation_size(c.reltoastrelid), 0)),
+		pg_size_pretty(pg_total_relation_size(s.relid)),
+		s.n_live_tup,
+		s.n_dead_tup,
+		CASE WHEN s.n_live_tup + s.n_dead_tup > 0
+			THEN round(100.0 * s.n_dead_tup / (s.n_live_tup + s.n_dead_tup), 2)::float8
+			ELSE 0 END,
+		COALESCE(to_char(s.last_vacuum, 'YYYY-MM-DD HH24:MI:SS'), '—'),
+		COALESCE(to_char(s.last_autovacuum, 'YYYY-MM-DD HH24:MI:SS'), '—'),
+		COALESCE(to_char(s.last_analyze, 'YYYY-MM-DD HH24:MI:SS'), '—'),
+		COALESCE(to_char(s.last_autoanalyze, 'YYYY-MM-DD HH24:MI:SS'), '—'),
+		s.vacuum_count + s.autovacuum_count,
+		s.analyze_count + s.autoanalyze_count
+	FROM pg_stat_user_tables s
+	JOIN pg_class c ON c.oid = s.relid
+	WHERE s.schemaname = 'public'
+	`
+
+// scanMaintenanceStats читает строку maintenanceStatsQuery
+func scanMaintenanceStats(row pgx.Row) (TableMaintenanceStats, error) {
+	var st TableMaintenanceStats
+	err := row.Scan(&st.Table, &st.TableSize, &st.IndexesSize, &st.ToastSize, &st.TotalSize,
+		&st.LiveTuples, &st.DeadTuples, &st.DeadRatio,
+		&st.LastVacuum, &st.LastAutovacuum, &st.LastAnalyze, &st.LastAutoanalyze,
+		&st.VacuumCount, &st.AnalyzeCount)
+	return st, err
+}
+
+// GetTableMaintenanceStats возвращает размеры и статистику обслуживания таблицы
+func GetTableMaintenanceStats(ctx context.Context, pool *pgxpool.Pool, table string) (TableMaintenanceStats, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return TableMaintenanceStats{}, err
+	}
+
+	st, err := scanMaintenanceStats(pool.QueryRow(ctx, maintenanceStatsQuery+" AND s.relname = $1", table))
+	if err != nil {
+		return st, fmt.Errorf("ошибка получения статистики таблицы %s: %w", table, err)
+	}
+	return st, nil
+}
+
+// ListTablesMaintenanceStats возвращает статистику всех таблиц, начиная с наиболее «раздутых»
+func ListTablesMaintenanceStats(ctx context.Context, pool *pgxpool.Pool) ([]TableMaintenanceStats, error) {
+	rows, err := pool.Query(ctx, maintenanceStatsQuery+" ORDER BY s.n_dead_tup DESC, s.relname")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статистики таблиц: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []TableMaintenanceStats
+	for rows.Next() {
+		st, err := scanMaintenanceStats(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения статистики: %w", err)
+		}
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
+
+// VacuumTable выполняет VACUUM таблицы
+// full — VACUUM FULL (переписывает таблицу, блокирует её), analyze — дополнительно собирает статистику
+func VacuumTable(ctx context.Context, pool *pgxpool.Pool, table string, full, analyze bool) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+
+	var options []string
+	if full {
+		options = append(options, "FULL")
+	}
+	if analyze {
+		options = append(options, "ANALYZE")
+	}
+
+	query := "VACUUM " + table
+	if len(options) > 0 {
+		query = fmt.Sprintf("VACUUM (%s) %s", strings.Join(options, ", "), table)
+	}
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("VACUUM: %v", err)
+		return fmt.Errorf("ошибка VACUUM для %s: %w", table, err)
+	}
+
+	fmt.Printf("%s выполнен\n", query)
+	return nil
+}
+
+// AnalyzeTable собирает статистику планировщика для таблицы
+func AnalyzeTable(ctx context.Context, pool *pgxpool.Pool, table string) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, fmt.Sprintf("ANALYZE %s", table)); err != nil {
+		log.Printf("ANALYZE: %v", err)
+		return fmt.Errorf("ошибка ANALYZE для %s: %w", table, err)
+	}
+
+	fmt.Printf("ANALYZE для '%s' выполнен\n", table)
+	return nil
+}
+
+// ReindexTable перестраивает все индексы таблицы
+// concurrently — без блокировки записи (нельзя выполнять внутри транзакции)
+func ReindexTable(ctx context.Context, pool *pgxpool.Pool, table string, concurrently bool) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+
+	query := "REINDEX TABLE "
+	if concurrently {
+		query += "CONCURRENTLY "
+	}
+	query += table
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("REINDEX: %v", err)
+		return fmt.Errorf("ошибка REINDEX для %s: %w", table, err)
+	}
+
+	fmt.Printf("REINDEX для '%s' выполнен\n", table)
+	return nil
+}
+
+// GetMaintenanceProgress возвращает прогресс выполняющейся над таблицей операции.
+// Второе значение false, если операция не найдена (ещё не началась или уже завершилась).
+func GetMaintenanceProgress(ctx context.Context, pool *pgxpool.Pool, table string) (MaintenanceProgress, bool, error) {
+	query := `
+	WITH target AS (
+		SELECT c.oid FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1
+	)
+	SELECT 'VACUUM', phase, heap_blks_scanned, heap_blks_total
+	FROM pg_stat_progress_vacuum
+	WHERE relid = (SELECT oid FROM target)
+	UNION ALL
+	SELECT 'VACUUM FULL', phase, heap_blks_scanned, heap_blks_total
+	FROM pg_stat_progress_cluster
+	WHERE relid = (SELECT oid FROM target)
+	UNION ALL
+	SELECT 'ANALYZE', phase, sample_blks_scanned, sample_blks_total
+	FROM pg_stat_progress_analyze
+	WHERE relid = (SELECT oid FROM target)
+	UNION ALL
+	SELECT 'REINDEX', phase, blocks_done, blocks_total
+	FROM pg_stat_progress_create_index
+	WHERE relid = (SELECT oid FROM target)
+	LIMIT 1
+	`
+
+	var progress MaintenanceProgress
+	err := pool.QueryRow(ctx, query, table).Scan(&progress.Command, &progress.Phase, &progress.Done, &progress.Total)
+	if err == pgx.ErrNoRows {
+		return progress, false, nil
+	}
+	if err != nil {
+		return progress, false, fmt.Errorf("ошибка получения прогресса: %w", err)
+	}
+	return progress, true, nil
+}
@@ -0,0 +1,564 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ Scheduled MATERIALIZED VIEW refresh ============
+
+// RefreshSpec computes the next run time of a refresh schedule
+type RefreshSpec interface {
+	Next(after time.Time) time.Time // zero time if there is no next run
+}
+
+// minRefreshInterval guards against schedules that would keep the MV permanently refreshing
+const minRefreshInterval = 10 * time.Second
+
+type intervalSpec struct {
+	every time.Duration
+}
+
+func (s intervalSpec) Next(after time.Time) time.Time {
+	return after.Add(s.every)
+}
+
+// cronSpec is a standard 5-field cron expression: minute hour day-of-month month day-of-week
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64 // bit i set = value i allowed
+	domAny, dowAny                bool
+}
+
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// parseCronField parses one cron field (*, 5, 1-5, */15, 1-30/5, lists separated by commas)
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max // "5/15" means from 5 to the end with step 15
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range %d-%d in %q", min, max, part)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseCron parses a 5-field cron expression or one of the @hourly/@daily/@weekly/@monthly aliases
+func parseCron(expr string) (*cronSpec, error) {
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields (minute hour day month weekday): %q", expr)
+	}
+
+	spec := &cronSpec{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	targets := []struct {
+		bits     *uint64
+		min, max int
+	}{
+		{&spec.minute, 0, 59},
+		{&spec.hour, 0, 23},
+		{&spec.dom, 1, 31},
+		{&spec.month, 1, 12},
+		{&spec.dow, 0, 7},
+	}
+	for i, t := range targets {
+		bits, err := parseCronField(fields[i], t.min, t.max)
+		if err != nil {
+			return nil, fmt.Errorf("cron field %d: %w", i+1, err)
+		}
+		*t.bits = bits
+	}
+	if spec.dow&(1<<7) != 0 { // 7 is Sunday as well
+		spec.dow |= 1
+	}
+	return spec, nil
+}
+
+func (s *cronSpec) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	// As in cron: if both day fields are restricted, either one matching is enough
+	if s.domAny || s.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+func (s *cronSpec) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// Enough steps to cover several years of skipping months, days and hours
+	for i := 0; i < 100000; i++ {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ParseRefreshSpec parses a refresh schedule: either an interval ("30m", "1h30m")
+// or a cron expression ("*/15 * * * *", "0 3 * * 1-5", "@daily")
+func ParseRefreshSpec(spec string) (RefreshSpec, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("schedule cannot be empty")
+	}
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d < minRefreshInterval {
+			return nil, fmt.Errorf("refresh interval must be at least %s", minRefreshInterval)
+		}
+		return intervalSpec{every: d}, nil
+	}
+	cron, err := parseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	if cron.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never fires", spec)
+	}
+	return cron, nil
+}
+
+// RefreshSchedule is a refresh schedule of one materialized view
+type RefreshSchedule struct {
+	MVName       string `json:"mv_name"`
+	Spec         string `json:"spec"` // interval or cron expression, see ParseRefreshSpec
+	Concurrently bool   `json:"concurrently"`
+	Enabled      bool   `json:"enabled"`
+}
+
+// RefreshHistoryEntry is one refresh attempt
+type RefreshHistoryEntry struct {
+	MVName    string        `json:"mv_name"`
+	Started   time.Time     `json:"started"`
+	Duration  time.Duration `json:"duration"`
+	Success   bool          `json:"success"`
+	Skipped   bool          `json:"skipped"` // another refresh of the same MV held the advisory lock
+	Scheduled bool          `json:"scheduled"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Status returns a short human-readable result of the attempt
+func (e RefreshHistoryEntry) Status() string {
+	switch {
+	case e.Skipped:
+		return "skipped (already running)"
+	case e.Success:
+		return "success"
+	default:
+		return "error"
+	}
+}
+
+// maxRefreshHistory limits how many history entries are kept in the local file
+const maxRefreshHistory = 1000
+
+// mvRefreshData is the JSON layout of the local schedule/history file
+type mvRefreshData struct {
+	Schedules []RefreshSchedule     `json:"schedules"`
+	History   []RefreshHistoryEntry `json:"history"`
+}
+
+// MVRefreshStore keeps refresh schedules and history in a local JSON file
+type MVRefreshStore struct {
+	path string // empty: in-memory only
+	mu   sync.Mutex
+	data mvRefreshData
+}
+
+// DefaultMVRefreshStorePath returns the location of the schedule/history file in the user config directory
+func DefaultMVRefreshStorePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "PostgreSQL-UI-Client", "mv_refresh.json"), nil
+}
+
+// LoadMVRefreshStore reads the schedule/history file; a missing file gives an empty store.
+// An empty path creates a store that is not persisted
+func LoadMVRefreshStore(path string) (*MVRefreshStore, error) {
+	store := &MVRefreshStore{path: path}
+	if path == "" {
+		return store, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(content, &store.data); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// saveLocked writes the store to disk; the caller holds mu
+func (s *MVRefreshStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.path), err)
+	}
+	// Write to a temporary file first so a crash never leaves a truncated file behind
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *MVRefreshStore) schedules() []RefreshSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RefreshSchedule(nil), s.data.Schedules...)
+}
+
+func (s *MVRefreshStore) putSchedule(schedule RefreshSchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.data.Schedules {
+		if existing.MVName == schedule.MVName {
+			s.data.Schedules[i] = schedule
+			return s.saveLocked()
+		}
+	}
+	s.data.Schedules = append(s.data.Schedules, schedule)
+	sort.Slice(s.data.Schedules, func(i, j int) bool { return s.data.Schedules[i].MVName < s.data.Schedules[j].MVName })
+	return s.saveLocked()
+}
+
+func (s *MVRefreshStore) removeSchedule(mvName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.data.Schedules {
+		if existing.MVName == mvName {
+			s.data.Schedules = append(s.data.Schedules[:i], s.data.Schedules[i+1:]...)
+			return s.saveLocked()
+		}
+	}
+	return nil
+}
+
+func (s *MVRefreshStore) addHistory(entry RefreshHistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.History = append(s.data.History, entry)
+	if extra := len(s.data.History) - maxRefreshHistory; extra > 0 {
+		s.data.History = append([]RefreshHistoryEntry(nil), s.data.History[extra:]...)
+	}
+	return s.saveLocked()
+}
+
+// history returns the attempts for mvName (all MVs if empty), newest first
+func (s *MVRefreshStore) history(mvName string) []RefreshHistoryEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []RefreshHistoryEntry
+	for i := len(s.data.History) - 1; i >= 0; i-- {
+		if mvName == "" || s.data.History[i].MVName == mvName {
+			result = append(result, s.data.History[i])
+		}
+	}
+	return result
+}
+
+// RefreshMaterializedViewLocked refreshes a materialized view while holding a session advisory lock
+// keyed by the MV name. If another session (this app or another instance) is already refreshing
+// the same MV, it returns ran=false without waiting
+func RefreshMaterializedViewLocked(ctx context.Context, pool *pgxpool.Pool, mvName string, concurrently bool) (ran bool, err error) {
+	if err := validateSQLIdent(mvName); err != nil {
+		return false, err
+	}
+
+	// Advisory locks belong to the session, so lock, refresh and unlock on one connection
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	const lockQuery = "SELECT pg_try_advisory_lock(hashtext('mv_refresh'), hashtext($1))"
+	var locked bool
+	if err := conn.QueryRow(ctx, lockQuery, mvName).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !locked {
+		fmt.Printf("MATERIALIZED VIEW '%s' is already being refreshed, skipping\n", mvName)
+		return false, nil
+	}
+	defer func() {
+		if _, unlockErr := conn.Exec(context.Background(),
+			"SELECT pg_advisory_unlock(hashtext('mv_refresh'), hashtext($1))", mvName); unlockErr != nil {
+			log.Printf("Error releasing advisory lock for %s: %v", mvName, unlockErr)
+		}
+	}()
+
+	query := "REFRESH MATERIALIZED VIEW " + mvName
+	if concurrently {
+		query = "REFRESH MATERIALIZED VIEW CONCURRENTLY " + mvName
+	}
+	if _, err := conn.Exec(ctx, query); err != nil {
+		log.Printf("Error refreshing materialized view: %v", err)
+		return true, fmt.Errorf("failed to refresh materialized view: %w", err)
+	}
+	fmt.Printf("MATERIALIZED VIEW '%s' refreshed successfully!\n", mvName)
+	return true, nil
+}
+
+// MVRefreshScheduler runs materialized view refreshes on their schedules and records every attempt
+type MVRefreshScheduler struct {
+	pool  *pgxpool.Pool
+	store *MVRefreshStore
+
+	mu        sync.Mutex
+	ctx       context.Context
+	cancels   map[string]context.CancelFunc
+	nextRuns  map[string]time.Time
+	onRefresh func(RefreshHistoryEntry)
+}
+
+// NewMVRefreshScheduler creates a scheduler over the given store; call Start to run the schedules
+func NewMVRefreshScheduler(pool *pgxpool.Pool, store *MVRefreshStore) *MVRefreshScheduler {
+	return &MVRefreshScheduler{
+		pool:     pool,
+		store:    store,
+		ctx:      context.Background(),
+		cancels:  make(map[string]context.CancelFunc),
+		nextRuns: make(map[string]time.Time),
+	}
+}
+
+// SetOnRefresh sets a callback invoked after every refresh attempt (from a background goroutine)
+func (s *MVRefreshScheduler) SetOnRefresh(callback func(RefreshHistoryEntry)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRefresh = callback
+}
+
+// Start launches all enabled schedules; they stop when ctx is cancelled or Stop is called
+func (s *MVRefreshScheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx = ctx
+	for _, schedule := range s.store.schedules() {
+		s.startLocked(schedule)
+	}
+}
+
+// Stop stops all schedules
+func (s *MVRefreshScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, cancel := range s.cancels {
+		cancel()
+		delete(s.cancels, name)
+		delete(s.nextRuns, name)
+	}
+}
+
+// startLocked (re)starts the goroutine of one schedule; the caller holds mu
+func (s *MVRefreshScheduler) startLocked(schedule RefreshSchedule) {
+	if cancel, ok := s.cancels[schedule.MVName]; ok {
+		cancel()
+		delete(s.cancels, schedule.MVName)
+		delete(s.nextRuns, schedule.MVName)
+	}
+	if !schedule.Enabled {
+		return
+	}
+	spec, err := ParseRefreshSpec(schedule.Spec)
+	if err != nil {
+		log.Printf("Invalid refresh schedule for %s: %v", schedule.MVName, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.cancels[schedule.MVName] = cancel
+	s.nextRuns[schedule.MVName] = spec.Next(time.Now())
+
+	go func() {
+		for {
+			next := spec.Next(time.Now())
+			if next.IsZero() {
+				return
+			}
+			s.mu.Lock()
+			if ctx.Err() != nil {
+				s.mu.Unlock()
+				return
+			}
+			s.nextRuns[schedule.MVName] = next
+			s.mu.Unlock()
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				// Not waited for: a refresh still running at the next tick is skipped by the advisory lock
+				go s.run(ctx, schedule.MVName, schedule.Concurrently, true)
+			}
+		}
+	}()
+}
+
+// SetSchedule validates, saves and (re)starts the schedule of a materialized view
+func (s *MVRefreshScheduler) SetSchedule(schedule RefreshSchedule) error {
+	if err := validateSQLIdent(schedule.MVName); err != nil {
+		return err
+	}
+	schedule.Spec = strings.TrimSpace(schedule.Spec)
+	if _, err := ParseRefreshSpec(schedule.Spec); err != nil {
+		return err
+	}
+	if err := s.store.putSchedule(schedule); err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startLocked(schedule)
+	return nil
+}
+
+// RemoveSchedule stops and deletes the schedule of a materialized view
+func (s *MVRefreshScheduler) RemoveSchedule(mvName string) error {
+	s.mu.Lock()
+	if cancel, ok := s.cancels[mvName]; ok {
+		cancel()
+		delete(s.cancels, mvName)
+		delete(s.nextRuns, mvName)
+	}
+	s.mu.Unlock()
+
+	if err := s.store.removeSchedule(mvName); err != nil {
+		return fmt.Errorf("failed to save schedules: %w", err)
+	}
+	return nil
+}
+
+// Schedules returns all saved schedules
+func (s *MVRefreshScheduler) Schedules() []RefreshSchedule {
+	return s.store.schedules()
+}
+
+// NextRun returns the next planned refresh of mvName (zero if it is not scheduled)
+func (s *MVRefreshScheduler) NextRun(mvName string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nextRuns[mvName]
+}
+
+// RunNow refreshes a materialized view immediately and records the attempt in the history
+func (s *MVRefreshScheduler) RunNow(ctx context.Context, mvName string, concurrently bool) RefreshHistoryEntry {
+	return s.run(ctx, mvName, concurrently, false)
+}
+
+func (s *MVRefreshScheduler) run(ctx context.Context, mvName string, concurrently, scheduled bool) RefreshHistoryEntry {
+	entry := RefreshHistoryEntry{MVName: mvName, Started: time.Now(), Scheduled: scheduled}
+	ran, err := RefreshMaterializedViewLocked(ctx, s.pool, mvName, concurrently)
+	entry.Duration = time.Since(entry.Started)
+	entry.Skipped = !ran && err == nil
+	entry.Success = ran && err == nil
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if err := s.store.addHistory(entry); err != nil {
+		log.Printf("Error saving refresh history: %v", err)
+	}
+
+	s.mu.Lock()
+	callback := s.onRefresh
+	s.mu.Unlock()
+	if callback != nil {
+		callback(entry)
+	}
+	return entry
+}
+
+// History returns the recorded refresh attempts of mvName (all MVs if empty), newest first
+func (s *MVRefreshScheduler) History(mvName string) []RefreshHistoryEntry {
+	return s.store.history(mvName)
+}
+
+// LastRefreshed returns the time of the last successful refresh of every MV in the history
+func (s *MVRefreshScheduler) LastRefreshed() map[string]time.Time {
+	last := make(map[string]time.Time)
+	for _, entry := range s.store.history("") {
+		if entry.Success && entry.Started.After(last[entry.MVName]) {
+			last[entry.MVName] = entry.Started
+		}
+	}
+	return last
+}
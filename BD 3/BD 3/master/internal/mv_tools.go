@@ -0,0 +1,283 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ MATERIALIZED VIEW storage, indexes and refresh pre-flight ============
+
+// MaterializedViewOptions are the optional clauses of CREATE MATERIALIZED VIEW
+type MaterializedViewOptions struct {
+	NoData bool // WITH NO DATA: create empty and unscannable until the first REFRESH
+}
+
+// CreateMaterializedViewWithOptions creates a materialized view, optionally WITH NO DATA
+func CreateMaterializedViewWithOptions(ctx context.Context, pool *pgxpool.Pool, mvName, selectQuery string, opts MaterializedViewOptions) error {
+	if err := validateSQLIdent(mvName); err != nil {
+		return err
+	}
+	selectQuery = strings.TrimSuffix(strings.TrimSpace(selectQuery), ";")
+	if selectQuery == "" {
+		return fmt.Errorf("SELECT query cannot be empty")
+	}
+
+	query := fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s", mvName, selectQuery)
+	if opts.NoData {
+		query += " WITH NO DATA"
+	}
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Error creating materialized view: %v", err)
+		return fmt.Errorf("failed to create materialized view: %w", err)
+	}
+	fmt.Printf("MATERIALIZED VIEW '%s' created successfully!\n", mvName)
+	return nil
+}
+
+// MaterializedViewInfo describes the storage state of a materialized view
+type MaterializedViewInfo struct {
+	Name           string
+	Populated      bool   // false after WITH NO DATA until the first REFRESH
+	TotalSize      int64  // bytes, including indexes and TOAST
+	Size           string // pg_size_pretty of TotalSize
+	RowEstimate    int64  // pg_class.reltuples, -1 if never analyzed
+	IndexCount     int
+	HasUniqueIndex bool // an index usable by REFRESH CONCURRENTLY exists
+}
+
+// concurrentRefreshIndexCondition matches unique indexes REFRESH CONCURRENTLY can use:
+// valid, not partial and built on plain columns only (no expressions)
+const concurrentRefreshIndexCondition = `i.indisunique AND i.indisvalid AND i.indpred IS NULL AND NOT (0 = ANY(i.indkey::int2[]))`
+
+// GetMaterializedViewsInfo returns materialized views of the public schema with size and populated state
+func GetMaterializedViewsInfo(ctx context.Context, pool *pgxpool.Pool) ([]MaterializedViewInfo, error) {
+	query := `
+		SELECT c.relname, c.relispopulated,
+			pg_total_relation_size(c.oid), pg_size_pretty(pg_total_relation_size(c.oid)),
+			c.reltuples::bigint,
+			(SELECT count(*) FROM pg_index i WHERE i.indrelid = c.oid),
+			EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND ` + concurrentRefreshIndexCondition + `)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relkind = 'm'
+		ORDER BY c.relname
+	`
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list materialized views: %w", err)
+	}
+	defer rows.Close()
+
+	var mvs []MaterializedViewInfo
+	for rows.Next() {
+		var mv MaterializedViewInfo
+		if err := rows.Scan(&mv.Name, &mv.Populated, &mv.TotalSize, &mv.Size, &mv.RowEstimate,
+			&mv.IndexCount, &mv.HasUniqueIndex); err != nil {
+			return nil, fmt.Errorf("failed to read materialized view: %w", err)
+		}
+		mvs = append(mvs, mv)
+	}
+	return mvs, rows.Err()
+}
+
+// GetMaterializedViewColumns returns the column names of a materialized view in order
+// (information_schema.columns does not list materialized views)
+func GetMaterializedViewColumns(ctx context.Context, pool *pgxpool.Pool, mvName string) ([]string, error) {
+	if err := validateSQLIdent(mvName); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT a.attname
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind = 'm'
+			AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum
+	`
+	rows, err := pool.Query(ctx, query, mvName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get materialized view columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, fmt.Errorf("failed to read column: %w", err)
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+// MVIndexInfo is an index of a materialized view
+type MVIndexInfo struct {
+	Name                  string
+	Definition            string
+	Size                  string
+	Unique                bool
+	UsableForConcurrently bool
+}
+
+// GetMaterializedViewIndexes returns the indexes of a materialized view
+func GetMaterializedViewIndexes(ctx context.Context, pool *pgxpool.Pool, mvName string) ([]MVIndexInfo, error) {
+	if err := validateSQLIdent(mvName); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ic.relname, pg_get_indexdef(i.indexrelid), pg_size_pretty(pg_relation_size(i.indexrelid)),
+			i.indisunique, ` + concurrentRefreshIndexCondition + `
+		FROM pg_index i
+		JOIN pg_class ic ON ic.oid = i.indexrelid
+		JOIN pg_class c ON c.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind = 'm'
+		ORDER BY ic.relname
+	`
+	rows, err := pool.Query(ctx, query, mvName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get materialized view indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []MVIndexInfo
+	for rows.Next() {
+		var idx MVIndexInfo
+		if err := rows.Scan(&idx.Name, &idx.Definition, &idx.Size, &idx.Unique, &idx.UsableForConcurrently); err != nil {
+			return nil, fmt.Errorf("failed to read index: %w", err)
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, rows.Err()
+}
+
+// CreateMaterializedViewIndex creates an index on columns of a materialized view.
+// An empty indexName is generated from the MV and column names
+// Example: CreateMaterializedViewIndex(ctx, pool, "sales_summary", "", []string{"region_id"}, true)
+func CreateMaterializedViewIndex(ctx context.Context, pool *pgxpool.Pool, mvName, indexName string, columns []string, unique bool) error {
+	if err := validateSQLIdent(mvName); err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("select at least one column for the index")
+	}
+	for _, col := range columns {
+		if err := validateSQLIdent(col); err != nil {
+			return err
+		}
+	}
+	if indexName = strings.TrimSpace(indexName); indexName == "" {
+		suffix := "idx"
+		if unique {
+			suffix = "uidx"
+		}
+		indexName = fmt.Sprintf("%s_%s_%s", mvName, strings.Join(columns, "_"), suffix)
+		if len(indexName) > 63 {
+			indexName = indexName[:63]
+		}
+	}
+	if err := validateSQLIdent(indexName); err != nil {
+		return err
+	}
+
+	uniqueStr := ""
+	if unique {
+		uniqueStr = "UNIQUE "
+	}
+	query := fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", uniqueStr, indexName, mvName, strings.Join(columns, ", "))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Error creating index on materialized view: %v", err)
+		return fmt.Errorf("failed to create index %s: %w", indexName, err)
+	}
+	fmt.Printf("INDEX '%s' created on MATERIALIZED VIEW '%s'\n", indexName, mvName)
+	return nil
+}
+
+// DropMaterializedViewIndex drops an index of a materialized view
+func DropMaterializedViewIndex(ctx context.Context, pool *pgxpool.Pool, indexName string) error {
+	if err := validateSQLIdent(indexName); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, fmt.Sprintf("DROP INDEX IF EXISTS %s", indexName)); err != nil {
+		log.Printf("Error dropping index: %v", err)
+		return fmt.Errorf("failed to drop index %s: %w", indexName, err)
+	}
+	fmt.Printf("INDEX '%s' dropped successfully!\n", indexName)
+	return nil
+}
+
+// ConcurrentRefreshCheck is the result of the REFRESH ... CONCURRENTLY pre-flight check
+type ConcurrentRefreshCheck struct {
+	Populated        bool     // CONCURRENTLY cannot be used on a never-populated MV
+	HasUniqueIndex   bool     // a unique index on plain columns without WHERE exists
+	CandidateColumns []string // columns whose current values are unique and NOT NULL
+}
+
+// Ready reports whether REFRESH MATERIALIZED VIEW CONCURRENTLY will be accepted
+func (c ConcurrentRefreshCheck) Ready() bool {
+	return c.Populated && c.HasUniqueIndex
+}
+
+// CheckConcurrentRefresh checks whether a materialized view can be refreshed CONCURRENTLY and,
+// if it lacks a suitable unique index, suggests columns a unique index could be built on
+func CheckConcurrentRefresh(ctx context.Context, pool *pgxpool.Pool, mvName string) (*ConcurrentRefreshCheck, error) {
+	if err := validateSQLIdent(mvName); err != nil {
+		return nil, err
+	}
+
+	check := &ConcurrentRefreshCheck{}
+	query := `
+		SELECT c.relispopulated,
+			EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND ` + concurrentRefreshIndexCondition + `)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind = 'm'
+	`
+	if err := pool.QueryRow(ctx, query, mvName).Scan(&check.Populated, &check.HasUniqueIndex); err != nil {
+		return nil, fmt.Errorf("materialized view '%s' not found: %w", mvName, err)
+	}
+	// An unpopulated MV cannot be scanned, so there is no data to look for unique columns in
+	if check.HasUniqueIndex || !check.Populated {
+		return check, nil
+	}
+
+	columns, err := GetMaterializedViewColumns(ctx, pool, mvName)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return check, nil
+	}
+
+	// One scan: for every column, is it free of NULLs and duplicates?
+	var exprs []string
+	for _, col := range columns {
+		exprs = append(exprs, fmt.Sprintf("count(%[1]s) = count(*) AND count(DISTINCT %[1]s) = count(*)", col))
+	}
+	results := make([]bool, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range results {
+		targets[i] = &results[i]
+	}
+	uniqueQuery := fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), mvName)
+	if err := pool.QueryRow(ctx, uniqueQuery).Scan(targets...); err != nil {
+		// Columns without an equality operator (json, point, ...) cannot be checked; skip suggestions
+		log.Printf("Unique column check for %s: %v", mvName, err)
+		return check, nil
+	}
+	for i, ok := range results {
+		if ok {
+			check.CandidateColumns = append(check.CandidateColumns, columns[i])
+		}
+	}
+	return check, nil
+}
@@ -11,37 +11,117 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Режимы IDENTITY столбца
+const (
+	IdentityAlways    = "ALWAYS"
+	IdentityByDefault = "BY DEFAULT"
+)
+
+// ColumnDefinition определение столбца для CREATE TABLE и ADD COLUMN.
+// Default, Generated, Identity, Collation и NotNull выводятся отдельными предложениями,
+// в Constraints остаются прочие ограничения (PRIMARY KEY, UNIQUE, CHECK, REFERENCES).
 type ColumnDefinition struct {
 	Name        string
 	Type        string
+	Collation   string // COLLATE "имя"
+	NotNull     bool
+	Default     string // выражение DEFAULT
+	Generated   string // выражение GENERATED ALWAYS AS (...) STORED
+	Identity    string // IdentityAlways или IdentityByDefault: GENERATED ... AS IDENTITY
 	Constraints string
 }
 
-func CreateTablesWithTypes(ctx context.Context, pool *pgxpool.Pool, tableName string, columns []ColumnDefinition) error {
-	if len(columns) == 0 {
-		return fmt.Errorf("список столбцов не может быть пустым")
+// validate проверяет совместимость DEFAULT, GENERATED и IDENTITY
+func (c ColumnDefinition) validate() error {
+	if c.Identity != "" && c.Identity != IdentityAlways && c.Identity != IdentityByDefault {
+		return fmt.Errorf("столбец %s: недопустимый режим IDENTITY: %s", c.Name, c.Identity)
+	}
+	if strings.TrimSpace(c.Generated) != "" && (strings.TrimSpace(c.Default) != "" || c.Identity != "") {
+		return fmt.Errorf("столбец %s: вычисляемый столбец не может иметь DEFAULT или IDENTITY", c.Name)
 	}
+	if c.Identity != "" && strings.TrimSpace(c.Default) != "" {
+		return fmt.Errorf("столбец %s: IDENTITY столбец не может иметь DEFAULT", c.Name)
+	}
+	return nil
+}
 
-	// Формируем SQL для создания столбцов
-	var columnDefinitions []string
+// validateColumnDefinitions проверяет все определения столбцов
+func validateColumnDefinitions(columns []ColumnDefinition) error {
 	for _, col := range columns {
-		// Базовое определение: имя и тип
-		colDef := fmt.Sprintf("%s %s", col.Name, col.Type)
+		if err := col.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnDefinitionSQL формирует SQL-определение столбца:
+// "имя тип [COLLATE] [GENERATED ...] [NOT NULL] [DEFAULT ...] ограничения"
+func columnDefinitionSQL(col ColumnDefinition) string {
+	// Базовое определение: имя и тип
+	parts := []string{col.Name, col.Type}
 
-		// Добавляем ограничения, если они указаны
-		if col.Constraints != "" {
-			colDef += " " + col.Constraints
+	if collation := strings.TrimSpace(col.Collation); collation != "" {
+		if !strings.HasPrefix(collation, `"`) {
+			collation = `"` + collation + `"`
 		}
+		parts = append(parts, "COLLATE "+collation)
+	}
+	if generated := strings.TrimSpace(col.Generated); generated != "" {
+		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) STORED", generated))
+	}
+	if col.Identity != "" {
+		parts = append(parts, fmt.Sprintf("GENERATED %s AS IDENTITY", col.Identity))
+	}
+	if col.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if def := strings.TrimSpace(col.Default); def != "" {
+		parts = append(parts, "DEFAULT "+def)
+	}
 
-		columnDefinitions = append(columnDefinitions, colDef)
+	// Добавляем ограничения, если они указаны
+	if col.Constraints != "" {
+		parts = append(parts, col.Constraints)
 	}
 
-	// Создаём SQL запрос CREATE TABLE
-	sql := fmt.Sprintf(
+	return strings.Join(parts, " ")
+}
+
+// buildColumnDefinitions формирует SQL-определения столбцов
+func buildColumnDefinitions(columns []ColumnDefinition) []string {
+	var columnDefinitions []string
+	for _, col := range columns {
+		columnDefinitions = append(columnDefinitions, columnDefinitionSQL(col))
+	}
+	return columnDefinitions
+}
+
+// BuildCreateTableSQL формирует запрос CREATE TABLE из определений столбцов
+// и ограничений уровня таблицы (используется и для предпросмотра DDL)
+func BuildCreateTableSQL(tableName string, columns []ColumnDefinition, tableConstraints []string) string {
+	allDefinitions := buildColumnDefinitions(columns)
+
+	// Добавляем ограничения уровня таблицы (например, FOREIGN KEY, CHECK, UNIQUE)
+	allDefinitions = append(allDefinitions, tableConstraints...)
+
+	return fmt.Sprintf(
 		"CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)",
 		tableName,
-		strings.Join(columnDefinitions, ",\n\t"),
+		strings.Join(allDefinitions, ",\n\t"),
 	)
+}
+
+func CreateTablesWithTypes(ctx context.Context, pool *pgxpool.Pool, tableName string, columns []ColumnDefinition) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("список столбцов не может быть пустым")
+	}
+	if err := validateColumnDefinitions(columns); err != nil {
+		return err
+	}
+
+	// Создаём SQL запрос CREATE TABLE
+	sql := BuildCreateTableSQL(tableName, columns, nil)
 
 	// Выполняем запрос
 	if _, err := pool.Exec(ctx, sql); err != nil {
@@ -56,29 +136,12 @@ func CreateTablesWithTypesAdvanced(ctx context.Context, pool *pgxpool.Pool, tabl
 	if len(columns) == 0 {
 		return fmt.Errorf("список столбцов не может быть пустым")
 	}
-
-	// Формируем SQL для создания столбцов
-	var columnDefinitions []string
-	for _, col := range columns {
-		colDef := fmt.Sprintf("%s %s", col.Name, col.Type)
-		if col.Constraints != "" {
-			colDef += " " + col.Constraints
-		}
-		columnDefinitions = append(columnDefinitions, colDef)
+	if err := validateColumnDefinitions(columns); err != nil {
+		return err
 	}
 
-	// Добавляем ограничения уровня таблицы (например, FOREIGN KEY, CHECK, UNIQUE)
-	allDefinitions := columnDefinitions
-	if len(tableConstraints) > 0 {
-		allDefinitions = append(allDefinitions, tableConstraints...)
-	}
-
-	// Создаём SQL запрос
-	sql := fmt.Sprintf(
-		"CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)",
-		tableName,
-		strings.Join(allDefinitions, ",\n\t"),
-	)
+	// Создаём SQL запрос со столбцами и ограничениями уровня таблицы
+	sql := BuildCreateTableSQL(tableName, columns, tableConstraints)
 
 	// Выполняем запрос
 	if _, err := pool.Exec(ctx, sql); err != nil {
@@ -146,18 +209,27 @@ func validateSQLIdent(name string) error {
 	return nil
 }
 
-// Добавление столбца
-func AddColumn(ctx context.Context, pool *pgxpool.Pool, table, col, typ, constraints string) error {
+// quoteLiteral экранирует строку как SQL-литерал: it's → 'it''s'
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// AddColumn добавляет столбец в таблицу
+// Пример: AddColumn(ctx, pool, "products", ColumnDefinition{Name: "total", Type: "NUMERIC", Generated: "price * quantity"})
+func AddColumn(ctx context.Context, pool *pgxpool.Pool, table string, col ColumnDefinition) error {
 	if err := validateSQLIdent(table); err != nil {
 		return err
 	}
-	if err := validateSQLIdent(col); err != nil {
+	if err := validateSQLIdent(col.Name); err != nil {
 		return err
 	}
-	if typ == "" {
+	if col.Type == "" {
 		return fmt.Errorf("типо столбца не может быть пустым")
 	}
-	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s %s", table, col, typ, constraints)
+	if err := col.validate(); err != nil {
+		return err
+	}
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, columnDefinitionSQL(col))
 	_, err := pool.Exec(ctx, query)
 	if err != nil {
 		log.Printf("Добавление столбца: %v", err)
@@ -166,6 +238,45 @@ func AddColumn(ctx context.Context, pool *pgxpool.Pool, table, col, typ, constra
 	return nil
 }
 
+// GetComputedColumns возвращает вычисляемые и IDENTITY столбцы таблицы:
+// имя столбца → "GENERATED", "IDENTITY ALWAYS" или "IDENTITY BY DEFAULT"
+func GetComputedColumns(ctx context.Context, pool *pgxpool.Pool, table string) (map[string]string, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT a.attname,
+		CASE
+			WHEN a.attgenerated = 's' THEN 'GENERATED'
+			WHEN a.attidentity = 'a' THEN 'IDENTITY ALWAYS'
+			ELSE 'IDENTITY BY DEFAULT'
+		END
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = 'public' AND c.relname = $1
+	AND a.attnum > 0 AND NOT a.attisdropped
+	AND (a.attgenerated <> '' OR a.attidentity <> '')
+	`
+
+	rows, err := pool.Query(ctx, query, table)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения вычисляемых столбцов: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]string)
+	for rows.Next() {
+		var name, kind string
+		if err := rows.Scan(&name, &kind); err != nil {
+			return nil, fmt.Errorf("ошибка чтения столбца: %w", err)
+		}
+		columns[name] = kind
+	}
+	return columns, rows.Err()
+}
+
 func DropColumn(ctx context.Context, pool *pgxpool.Pool, table, col string) error {
 	if err := validateSQLIdent(table); err != nil {
 		return err
@@ -182,7 +293,9 @@ func DropColumn(ctx context.Context, pool *pgxpool.Pool, table, col string) erro
 	return nil
 }
 
-func AlterColumnType(ctx context.Context, pool *pgxpool.Pool, table, col, newtyp string) error {
+// AlterColumnType изменяет тип столбца; using (может быть пустым) задаёт выражение преобразования
+// Пример: AlterColumnType(ctx, pool, "products", "price", "INTEGER", "round(price)::integer")
+func AlterColumnType(ctx context.Context, pool *pgxpool.Pool, table, col, newtyp, using string) error {
 	if err := validateSQLIdent(table); err != nil {
 		return err
 	}
@@ -193,6 +306,9 @@ func AlterColumnType(ctx context.Context, pool *pgxpool.Pool, table, col, newtyp
 		return fmt.Errorf("Новый тип столбца не может быть пустым")
 	}
 	query := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", table, col, newtyp)
+	if using = strings.TrimSpace(using); using != "" {
+		query += " USING " + using
+	}
 	_, err := pool.Exec(ctx, query)
 	if err != nil {
 		log.Printf("Изменение типа столбца: %v", err)
@@ -237,6 +353,22 @@ func RenameTable(ctx context.Context, pool *pgxpool.Pool, oldTbl, newTbl string)
 	return nil
 }
 
+// DropTable удаляет таблицу с RESTRICT или CASCADE
+func DropTable(ctx context.Context, pool *pgxpool.Pool, tableName string, cascade bool) error {
+	if err := validateSQLIdent(tableName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DROP TABLE IF EXISTS %s %s", tableName, dropBehavior(cascade))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Удаление таблицы: %v", err)
+		return fmt.Errorf("не удалось удалить таблицу: %w", err)
+	}
+
+	fmt.Printf("Таблица '%s' удалена (%s)\n", tableName, dropBehavior(cascade))
+	return nil
+}
+
 // Добавление проверки
 func AddCheck(ctx context.Context, pool *pgxpool.Pool, table, constraintName, expression string) error {
 	if err := validateSQLIdent(table); err != nil {
@@ -538,7 +670,7 @@ type CustomTypeInfo struct {
 	Name        string
 	Kind        string
 	Values      []string
-	Fields      map[string]string
+	Fields      []CompositeField
 	Description string
 }
 
@@ -547,7 +679,7 @@ func CreateEnumTypeUI(ctx context.Context, pool *pgxpool.Pool, typeName string,
 	return CreateEnumType(ctx, pool, typeName, values)
 }
 
-func CreateCompositeTypeUI(ctx context.Context, pool *pgxpool.Pool, typeName string, fields map[string]string) error {
+func CreateCompositeTypeUI(ctx context.Context, pool *pgxpool.Pool, typeName string, fields []CompositeField) error {
 	return CreateCompositeType(ctx, pool, typeName, fields)
 }
 
@@ -555,8 +687,8 @@ func GetCustomTypesUI(ctx context.Context, pool *pgxpool.Pool) ([]map[string]int
 	return GetCustomTypes(ctx, pool)
 }
 
-func DropEnumTypeUI(ctx context.Context, pool *pgxpool.Pool, typeName string) error {
-	return DropEnumType(ctx, pool, typeName)
+func DropEnumTypeUI(ctx context.Context, pool *pgxpool.Pool, typeName string, cascade bool) error {
+	return DropEnumType(ctx, pool, typeName, cascade)
 }
 
 func GetTypeInfoUI(ctx context.Context, pool *pgxpool.Pool, typeName string) (*TypeInfo, error) {
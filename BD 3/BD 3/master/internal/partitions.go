@@ -0,0 +1,366 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PartitionStrategy способ декларативного партиционирования
+type PartitionStrategy string
+
+const (
+	PartitionByRange PartitionStrategy = "RANGE"
+	PartitionByList  PartitionStrategy = "LIST"
+	PartitionByHash  PartitionStrategy = "HASH"
+)
+
+// DefaultPartitionBound граница для партиции по умолчанию (RANGE/LIST)
+const DefaultPartitionBound = "DEFAULT"
+
+// PartitionInfo описывает узел дерева партиций
+type PartitionInfo struct {
+	Name     string
+	Parent   string
+	Level    int
+	IsLeaf   bool
+	Bound    string // FOR VALUES ... или DEFAULT, пусто для корня
+	Strategy string // стратегия для партиционированных узлов (RANGE/LIST/HASH)
+	Key      string // ключ партиционирования для партиционированных узлов
+}
+
+// validatePartitionStrategy проверяет стратегию партиционирования
+func validatePartitionStrategy(strategy PartitionStrategy) error {
+	switch strategy {
+	case PartitionByRange, PartitionByList, PartitionByHash:
+		return nil
+	}
+	return fmt.Errorf("неизвестная стратегия партиционирования: %s", strategy)
+}
+
+// RangeBound формирует границу RANGE-партиции
+// Пример: RangeBound("'2025-01-01'", "'2025-02-01'") → FOR VALUES FROM ('2025-01-01') TO ('2025-02-01')
+func RangeBound(from, to string) string {
+	return fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", from, to)
+}
+
+// ListBound формирует границу LIST-партиции
+// Пример: ListBound("'ru'", "'by'") → FOR VALUES IN ('ru', 'by')
+func ListBound(values ...string) string {
+	return fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(values, ", "))
+}
+
+// HashBound формирует границу HASH-партиции
+// Пример: HashBound(4, 0) → FOR VALUES WITH (MODULUS 4, REMAINDER 0)
+func HashBound(modulus, remainder int) string {
+	return fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", modulus, remainder)
+}
+
+// validatePartitionBound проверяет, что граница задана в поддерживаемом виде
+func validatePartitionBound(bound string) error {
+	bound = strings.TrimSpace(bound)
+	if bound == "" {
+		return fmt.Errorf("граница партиции не может быть пустой")
+	}
+	upper := strings.ToUpper(bound)
+	if upper != DefaultPartitionBound && !strings.HasPrefix(upper, "FOR VALUES") {
+		return fmt.Errorf("граница партиции должна начинаться с FOR VALUES или быть DEFAULT: %s", bound)
+	}
+	return nil
+}
+
+// CreatePartitionedTable создаёт партиционированную таблицу
+// Пример: CreatePartitionedTable(ctx, pool, "events", columns, nil, PartitionByRange, "created_at")
+func CreatePartitionedTable(ctx context.Context, pool *pgxpool.Pool, tableName string, columns []ColumnDefinition,
+	tableConstraints []string, strategy PartitionStrategy, partitionKey string) error {
+	if err := validateSQLIdent(tableName); err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("список столбцов не может быть пустым")
+	}
+	if err := validateColumnDefinitions(columns); err != nil {
+		return err
+	}
+	if err := validatePartitionStrategy(strategy); err != nil {
+		return err
+	}
+	if strings.TrimSpace(partitionKey) == "" {
+		return fmt.Errorf("ключ партиционирования не может быть пустым")
+	}
+
+	allDefinitions := append(buildColumnDefinitions(columns), tableConstraints...)
+
+	query := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (\n\t%s\n) PARTITION BY %s (%s)",
+		tableName,
+		strings.Join(allDefinitions, ",\n\t"),
+		strategy,
+		partitionKey,
+	)
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Создание партиционированной таблицы: %v", err)
+		return fmt.Errorf("ошибка создания партиционированной таблицы %s: %w", tableName, err)
+	}
+
+	fmt.Printf("Партиционированная таблица '%s' создана (PARTITION BY %s (%s))\n", tableName, strategy, partitionKey)
+	return nil
+}
+
+// CreatePartition создаёт партицию родительской таблицы
+// Пример: CreatePartition(ctx, pool, "events", "events_2025_01", RangeBound("'2025-01-01'", "'2025-02-01'"))
+func CreatePartition(ctx context.Context, pool *pgxpool.Pool, parentTable, partitionName, bound string) error {
+	if err := validateSQLIdent(parentTable); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(partitionName); err != nil {
+		return err
+	}
+	if err := validatePartitionBound(bound); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("CREATE TABLE %s PARTITION OF %s %s", partitionName, parentTable, bound)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Создание партиции: %v", err)
+		return fmt.Errorf("ошибка создания партиции %s: %w", partitionName, err)
+	}
+
+	fmt.Printf("Партиция '%s' таблицы '%s' создана\n", partitionName, parentTable)
+	return nil
+}
+
+// AttachPartition подключает существующую таблицу как партицию
+func AttachPartition(ctx context.Context, pool *pgxpool.Pool, parentTable, tableName, bound string) error {
+	if err := validateSQLIdent(parentTable); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(tableName); err != nil {
+		return err
+	}
+	if err := validatePartitionBound(bound); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s %s", parentTable, tableName, bound)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Подключение партиции: %v", err)
+		return fmt.Errorf("не удалось подключить партицию %s: %w", tableName, err)
+	}
+	return nil
+}
+
+// DetachPartition отключает партицию от родительской таблицы
+// concurrently=true использует DETACH PARTITION ... CONCURRENTLY (PostgreSQL 14+)
+func DetachPartition(ctx context.Context, pool *pgxpool.Pool, parentTable, partitionName string, concurrently bool) error {
+	if err := validateSQLIdent(parentTable); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(partitionName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", parentTable, partitionName)
+	if concurrently {
+		query += " CONCURRENTLY"
+	}
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Отключение партиции: %v", err)
+		return fmt.Errorf("не удалось отключить партицию %s: %w", partitionName, err)
+	}
+	return nil
+}
+
+// GetPartitionTree возвращает дерево партиций таблицы (корень первым)
+func GetPartitionTree(ctx context.Context, pool *pgxpool.Pool, tableName string) ([]PartitionInfo, error) {
+	if err := validateSQLIdent(tableName); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT
+		c.relname,
+		COALESCE(p.relname, ''),
+		t.level,
+		t.isleaf,
+		COALESCE(pg_get_expr(c.relpartbound, c.oid), ''),
+		CASE pt.partstrat
+			WHEN 'r' THEN 'RANGE'
+			WHEN 'l' THEN 'LIST'
+			WHEN 'h' THEN 'HASH'
+			ELSE ''
+		END,
+		COALESCE(pg_get_partkeydef(c.oid), '')
+	FROM pg_partition_tree($1::regclass) t
+	JOIN pg_class c ON c.oid = t.relid
+	LEFT JOIN pg_class p ON p.oid = t.parentrelid
+	LEFT JOIN pg_partitioned_table pt ON pt.partrelid = c.oid
+	ORDER BY t.level, c.relname
+	`
+
+	rows, err := pool.Query(ctx, query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения дерева партиций: %w", err)
+	}
+	defer rows.Close()
+
+	var tree []PartitionInfo
+	for rows.Next() {
+		var info PartitionInfo
+		if err := rows.Scan(&info.Name, &info.Parent, &info.Level, &info.IsLeaf, &info.Bound, &info.Strategy, &info.Key); err != nil {
+			return nil, fmt.Errorf("ошибка чтения партиции: %w", err)
+		}
+		tree = append(tree, info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по партициям: %w", err)
+	}
+	if len(tree) == 0 {
+		return nil, fmt.Errorf("таблица '%s' не найдена", tableName)
+	}
+	return tree, nil
+}
+
+// ListPartitionedTables возвращает партиционированные таблицы схемы public
+func ListPartitionedTables(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	query := `
+	SELECT c.relname
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.relkind = 'p' AND n.nspname = 'public' AND NOT c.relispartition
+	ORDER BY c.relname
+	`
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения партиционированных таблиц: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения таблицы: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// monthlyPartitionName формирует имя месячной партиции: events_2025_01
+func monthlyPartitionName(parentTable string, month time.Time) string {
+	return fmt.Sprintf("%s_%04d_%02d", parentTable, month.Year(), int(month.Month()))
+}
+
+// rangeLowerPattern и rangeUpperPattern извлекают нижнюю и верхнюю границы из "FOR VALUES FROM ('...') TO ('...')";
+// для MINVALUE/MAXVALUE граница не извлекается и считается бесконечной
+const (
+	rangeLowerPattern = `FROM \('([^']*)'\)`
+	rangeUpperPattern = `TO \('([^']*)'\)`
+)
+
+// EnsureMonthlyPartitions создаёт недостающие месячные RANGE-партиции
+// начиная с месяца from на monthsAhead месяцев вперёд (включая текущий).
+// Месяцы, уже покрытые партицией (под любым именем), и месяцы, строки которых лежат в DEFAULT-партиции,
+// пропускаются до создания чего-либо и возвращаются в skipped с причиной.
+// Возвращает имена созданных партиций.
+func EnsureMonthlyPartitions(ctx context.Context, pool *pgxpool.Pool, parentTable string, from time.Time, monthsAhead int) (created, skipped []string, err error) {
+	if err := validateSQLIdent(parentTable); err != nil {
+		return nil, nil, err
+	}
+	if monthsAhead <= 0 {
+		return nil, nil, fmt.Errorf("количество месяцев должно быть положительным")
+	}
+
+	tree, err := GetPartitionTree(ctx, pool, parentTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tree[0].Strategy != string(PartitionByRange) {
+		return nil, nil, fmt.Errorf("таблица '%s' не партиционирована по RANGE", parentTable)
+	}
+
+	// Границы сравниваются в типе ключа, поэтому поддерживается ключ из одного столбца
+	var keyColumn, keyType, defaultPartition string
+	keyQuery := `
+	SELECT quote_ident(a.attname), format_type(a.atttypid, a.atttypmod),
+		COALESCE((SELECT quote_ident(d.relname) FROM pg_class d WHERE d.oid = pt.partdefid), '')
+	FROM pg_partitioned_table pt
+	JOIN pg_attribute a ON a.attrelid = pt.partrelid AND a.attnum = pt.partattrs[0]
+	WHERE pt.partrelid = $1::regclass AND pt.partnatts = 1
+	`
+	if err := pool.QueryRow(ctx, keyQuery, parentTable).Scan(&keyColumn, &keyType, &defaultPartition); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, fmt.Errorf("месячные партиции поддерживаются только для ключа из одного столбца")
+		}
+		return nil, nil, fmt.Errorf("ошибка получения ключа партиционирования: %w", err)
+	}
+
+	overlapQuery := fmt.Sprintf(`
+	SELECT c.relname
+	FROM pg_inherits i
+	JOIN pg_class c ON c.oid = i.inhrelid
+	CROSS JOIN LATERAL (SELECT pg_get_expr(c.relpartbound, c.oid) AS def) b
+	WHERE i.inhparent = $1::regclass AND b.def <> 'DEFAULT'
+		AND COALESCE(substring(b.def from $4)::%[1]s < $3::text::%[1]s, true)
+		AND COALESCE(substring(b.def from $5)::%[1]s > $2::text::%[1]s, true)
+	ORDER BY c.relname
+	LIMIT 1
+	`, keyType)
+	defaultQuery := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %[1]s WHERE %[2]s >= $1::text::%[3]s AND %[2]s < $2::text::%[3]s)",
+		defaultPartition, keyColumn, keyType)
+
+	type monthPartition struct {
+		name  string
+		bound string
+	}
+	var missing []monthPartition
+	start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < monthsAhead; i++ {
+		month := start.AddDate(0, i, 0)
+		name := monthlyPartitionName(parentTable, month)
+		lower, upper := month.Format("2006-01-02"), month.AddDate(0, 1, 0).Format("2006-01-02")
+
+		var covering string
+		err := pool.QueryRow(ctx, overlapQuery, parentTable, lower, upper, rangeLowerPattern, rangeUpperPattern).Scan(&covering)
+		if err == nil {
+			if covering != name {
+				skipped = append(skipped, fmt.Sprintf("%s: месяц уже покрыт партицией %s", name, covering))
+			}
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, fmt.Errorf("ошибка проверки границ партиций: %w", err)
+		}
+
+		if defaultPartition != "" {
+			var hasRows bool
+			if err := pool.QueryRow(ctx, defaultQuery, lower, upper).Scan(&hasRows); err != nil {
+				return nil, nil, fmt.Errorf("ошибка проверки DEFAULT-партиции: %w", err)
+			}
+			if hasRows {
+				skipped = append(skipped, fmt.Sprintf("%s: строки за этот месяц лежат в DEFAULT-партиции %s", name, defaultPartition))
+				continue
+			}
+		}
+
+		missing = append(missing, monthPartition{name: name, bound: RangeBound(quoteLiteral(lower), quoteLiteral(upper))})
+	}
+
+	for _, p := range missing {
+		if err := CreatePartition(ctx, pool, parentTable, p.name, p.bound); err != nil {
+			return created, skipped, err
+		}
+		created = append(created, p.name)
+	}
+
+	return created, skipped, nil
+}
@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PolicyCommands команды, к которым может относиться политика
+var PolicyCommands = []string{"ALL", "SELECT", "INSERT", "UPDATE", "DELETE"}
+
+// PolicyDefinition описание политики строкового доступа (CREATE POLICY)
+type PolicyDefinition struct {
+	Name       string
+	Table      string
+	Permissive bool     // PERMISSIVE (по умолчанию) или RESTRICTIVE
+	Command    string   // ALL, SELECT, INSERT, UPDATE, DELETE
+	Roles      []string // пусто — PUBLIC
+	Using      string   // выражение USING
+	WithCheck  string   // выражение WITH CHECK
+}
+
+// TableRLSStatus состояние RLS для таблицы
+type TableRLSStatus struct {
+	Table   string
+	Enabled bool
+	Forced  bool
+}
+
+// validatePolicyCommand проверяет команду политики
+func validatePolicyCommand(command string) (string, error) {
+	command = strings.ToUpper(strings.TrimSpace(command))
+	if command == "" {
+		return "ALL", nil
+	}
+	for _, c := range PolicyCommands {
+		if c == command {
+			return command, nil
+		}
+	}
+	return "", fmt.Errorf("недопустимая команда политики: %s", command)
+}
+
+// BuildPolicySQL формирует CREATE POLICY
+func BuildPolicySQL(def PolicyDefinition) (string, error) {
+	if err := validateSQLIdent(def.Name); err != nil {
+		return "", err
+	}
+	if err := validateSQLIdent(def.Table); err != nil {
+		return "", err
+	}
+	command, err := validatePolicyCommand(def.Command)
+	if err != nil {
+		return "", err
+	}
+
+	roles := []string{"PUBLIC"}
+	if len(def.Roles) > 0 {
+		roles = nil
+		for _, role := range def.Roles {
+			if err := validateGrantee(role); err != nil {
+				return "", err
+			}
+			roles = append(roles, role)
+		}
+	}
+
+	using := strings.TrimSpace(def.Using)
+	withCheck := strings.TrimSpace(def.WithCheck)
+
+	// INSERT допускает только WITH CHECK, SELECT и DELETE — только USING
+	if command == "INSERT" && using != "" {
+		return "", fmt.Errorf("для INSERT допускается только WITH CHECK")
+	}
+	if (command == "SELECT" || command == "DELETE") && withCheck != "" {
+		return "", fmt.Errorf("для %s допускается только USING", command)
+	}
+
+	kind := "PERMISSIVE"
+	if !def.Permissive {
+		kind = "RESTRICTIVE"
+	}
+
+	query := fmt.Sprintf("CREATE POLICY %s ON %s AS %s FOR %s TO %s",
+		def.Name, def.Table, kind, command, strings.Join(roles, ", "))
+	if using != "" {
+		query += fmt.Sprintf("\n    USING (%s)", using)
+	}
+	if withCheck != "" {
+		query += fmt.Sprintf("\n    WITH CHECK (%s)", withCheck)
+	}
+	return query, nil
+}
+
+// SetRowLevelSecurity включает/выключает RLS и принудительный RLS для владельца таблицы
+func SetRowLevelSecurity(ctx context.Context, pool *pgxpool.Pool, table string, enabled, forced bool) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+
+	enable := "DISABLE"
+	if enabled {
+		enable = "ENABLE"
+	}
+	force := "NO FORCE"
+	if forced {
+		force = "FORCE"
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s %s ROW LEVEL SECURITY, %s ROW LEVEL SECURITY", table, enable, force)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Настройка RLS: %v", err)
+		return fmt.Errorf("ошибка настройки RLS для %s: %w", table, err)
+	}
+
+	fmt.Printf("RLS для '%s': %s, %s\n", table, enable, force)
+	return nil
+}
+
+// GetRowLevelSecurity возвращает состояние RLS таблицы
+func GetRowLevelSecurity(ctx context.Context, pool *pgxpool.Pool, table string) (TableRLSStatus, error) {
+	status := TableRLSStatus{Table: table}
+	query := `
+	SELECT c.relrowsecurity, c.relforcerowsecurity
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind IN ('r', 'p')
+	`
+	if err := pool.QueryRow(ctx, query, table).Scan(&status.Enabled, &status.Forced); err != nil {
+		return status, fmt.Errorf("ошибка получения состояния RLS для %s: %w", table, err)
+	}
+	return status, nil
+}
+
+// CreatePolicy создаёт политику строкового доступа
+func CreatePolicy(ctx context.Context, pool *pgxpool.Pool, def PolicyDefinition) error {
+	query, err := BuildPolicySQL(def)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Создание политики: %v", err)
+		return fmt.Errorf("ошибка создания политики %s: %w", def.Name, err)
+	}
+
+	fmt.Printf("Политика '%s' на '%s' создана\n", def.Name, def.Table)
+	return nil
+}
+
+// ReplacePolicy пересоздаёт политику в одной транзакции.
+// ALTER POLICY не позволяет сменить команду и тип, поэтому политика удаляется и создаётся заново.
+func ReplacePolicy(ctx context.Context, pool *pgxpool.Pool, oldName string, def PolicyDefinition) error {
+	if err := validateSQLIdent(oldName); err != nil {
+		return err
+	}
+	createQuery, err := BuildPolicySQL(def)
+	if err != nil {
+		return err
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DROP POLICY %s ON %s", oldName, def.Table)); err != nil {
+		log.Printf("Изменение политики: %v", err)
+		return fmt.Errorf("ошибка удаления политики %s: %w", oldName, err)
+	}
+	if _, err := tx.Exec(ctx, createQuery); err != nil {
+		log.Printf("Изменение политики: %v", err)
+		return fmt.Errorf("ошибка создания политики %s: %w", def.Name, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+
+	fmt.Printf("Политика '%s' на '%s' изменена\n", def.Name, def.Table)
+	return nil
+}
+
+// DropPolicy удаляет политику
+func DropPolicy(ctx context.Context, pool *pgxpool.Pool, table, policyName string) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(policyName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s", policyName, table)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Удаление политики: %v", err)
+		return fmt.Errorf("не удалось удалить политику %s: %w", policyName, err)
+	}
+	return nil
+}
+
+// ListPolicies возвращает политики таблицы (пустое имя — все таблицы схемы public)
+func ListPolicies(ctx context.Context, pool *pgxpool.Pool, table string) ([]PolicyDefinition, error) {
+	query := `
+	SELECT policyname, tablename, permissive = 'PERMISSIVE', cmd,
+		roles::text[], COALESCE(qual, ''), COALESCE(with_check, '')
+	FROM pg_policies
+	WHERE schemaname = 'public' AND ($1 = '' OR tablename = $1)
+	ORDER BY tablename, policyname
+	`
+	rows, err := pool.Query(ctx, query, table)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения политик: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []PolicyDefinition
+	for rows.Next() {
+		var p PolicyDefinition
+		if err := rows.Scan(&p.Name, &p.Table, &p.Permissive, &p.Command, &p.Roles, &p.Using, &p.WithCheck); err != nil {
+			return nil, fmt.Errorf("ошибка чтения политики: %w", err)
+		}
+		if len(p.Roles) == 1 && strings.EqualFold(p.Roles[0], "public") {
+			p.Roles = nil
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// SelectAsRole выполняет SELECT из таблицы от имени роли (SET LOCAL ROLE)
+// и показывает, какие строки она увидит. Транзакция всегда откатывается.
+func SelectAsRole(ctx context.Context, pool *pgxpool.Pool, table, role string, limit int) ([][]string, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return nil, err
+	}
+	if err := validateSQLIdent(role); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL ROLE %s", role)); err != nil {
+		log.Printf("Переключение роли: %v", err)
+		return nil, fmt.Errorf("не удалось переключиться на роль %s: %w", role, err)
+	}
+
+	rows, err := tx.Query(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT %d", table, limit))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выборки от имени %s: %w", role, err)
+	}
+	return rowsAsStrings(rows)
+}
@@ -618,6 +618,15 @@ func (qb *QueryBuilder) WhereNotSimilarTo(column, pattern string) *QueryBuilder
 	return qb
 }
 
+// WhereTrigramSimilar добавляет условие нечёткого поиска по сходству триграмм (требует pg_trgm)
+// Пример: WhereTrigramSimilar("name", "ноутбк")
+// Порог сходства задаётся параметром pg_trgm.similarity_threshold (по умолчанию 0.3)
+func (qb *QueryBuilder) WhereTrigramSimilar(column, pattern string) *QueryBuilder {
+	condition := fmt.Sprintf("%s %% %s", column, quoteLiteral(pattern))
+	qb.conditions = append(qb.conditions, condition)
+	return qb
+}
+
 // ===== ТРЕБОВАНИЕ 5: CASE, COALESCE, NULLIF =====
 
 // CaseExpression структура для построения CASE выражений
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RangeTypeDefinition диапазонный тип (CREATE TYPE ... AS RANGE)
+type RangeTypeDefinition struct {
+	Name           string
+	Subtype        string // тип элементов диапазона, например: NUMERIC или TIME
+	SubtypeOpClass string // класс операторов B-дерева для упорядочивания; пусто — класс по умолчанию
+	Collation      string // правило сортировки для строковых подтипов
+	SubtypeDiff    string // функция разности двух значений подтипа (double precision), ускоряет GiST
+}
+
+// BuildCreateRangeTypeSQL формирует запрос CREATE TYPE ... AS RANGE (используется и для предпросмотра)
+func BuildCreateRangeTypeSQL(rangeType RangeTypeDefinition) string {
+	options := []string{"SUBTYPE = " + rangeType.Subtype}
+	if opclass := strings.TrimSpace(rangeType.SubtypeOpClass); opclass != "" {
+		options = append(options, "SUBTYPE_OPCLASS = "+opclass)
+	}
+	if collation := strings.TrimSpace(rangeType.Collation); collation != "" {
+		if !strings.HasPrefix(collation, `"`) {
+			collation = `"` + collation + `"`
+		}
+		options = append(options, "COLLATION = "+collation)
+	}
+	if diff := strings.TrimSpace(rangeType.SubtypeDiff); diff != "" {
+		options = append(options, "SUBTYPE_DIFF = "+diff)
+	}
+	return fmt.Sprintf("CREATE TYPE %s AS RANGE (%s)", rangeType.Name, strings.Join(options, ", "))
+}
+
+// CreateRangeType создаёт диапазонный тип
+// Пример: CreateRangeType(ctx, pool, RangeTypeDefinition{Name: "timerange", Subtype: "TIME", SubtypeDiff: "time_subtype_diff"})
+func CreateRangeType(ctx context.Context, pool *pgxpool.Pool, rangeType RangeTypeDefinition) error {
+	if err := validateSQLIdent(rangeType.Name); err != nil {
+		return err
+	}
+	if strings.TrimSpace(rangeType.Subtype) == "" {
+		return fmt.Errorf("подтип диапазона не может быть пустым")
+	}
+	if opclass := strings.TrimSpace(rangeType.SubtypeOpClass); opclass != "" {
+		if err := validateSQLIdent(opclass); err != nil {
+			return fmt.Errorf("недопустимый класс операторов: %w", err)
+		}
+	}
+	if diff := strings.TrimSpace(rangeType.SubtypeDiff); diff != "" {
+		if err := validateSQLIdent(diff); err != nil {
+			return fmt.Errorf("недопустимое имя функции SUBTYPE_DIFF: %w", err)
+		}
+	}
+
+	if _, err := pool.Exec(ctx, BuildCreateRangeTypeSQL(rangeType)); err != nil {
+		log.Printf("Ошибка создания диапазонного типа: %v", err)
+		return fmt.Errorf("ошибка создания диапазонного типа %s: %w", rangeType.Name, err)
+	}
+
+	fmt.Printf("Диапазонный тип '%s' успешно создан на основе %s\n", rangeType.Name, rangeType.Subtype)
+	return nil
+}
+
+// GetRangeTypeInfo возвращает определение диапазонного типа из pg_range
+func GetRangeTypeInfo(ctx context.Context, pool *pgxpool.Pool, typeName string) (*RangeTypeDefinition, error) {
+	if err := validateSQLIdent(typeName); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT
+		format_type(r.rngsubtype, NULL),
+		opc.opcname,
+		COALESCE(coll.collname, ''),
+		CASE WHEN r.rngsubdiff::oid = 0 THEN '' ELSE r.rngsubdiff::regproc::text END
+	FROM pg_range r
+	JOIN pg_type t ON t.oid = r.rngtypid
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	JOIN pg_opclass opc ON opc.oid = r.rngsubopc
+	LEFT JOIN pg_collation coll ON coll.oid = r.rngcollation
+	WHERE n.nspname = 'public' AND t.typname = $1
+	`
+
+	rangeType := &RangeTypeDefinition{Name: typeName}
+	err := pool.QueryRow(ctx, query, typeName).Scan(&rangeType.Subtype, &rangeType.SubtypeOpClass,
+		&rangeType.Collation, &rangeType.SubtypeDiff)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("диапазонный тип '%s' не найден", typeName)
+		}
+		return nil, fmt.Errorf("ошибка получения диапазонного типа: %w", err)
+	}
+	return rangeType, nil
+}
+
+// GetSubtypeOperatorClasses возвращает классы операторов B-дерева, применимые к подтипу диапазона;
+// класс по умолчанию идёт первым
+func GetSubtypeOperatorClasses(ctx context.Context, pool *pgxpool.Pool, subtype string) ([]string, error) {
+	subtype = strings.TrimSpace(subtype)
+	if subtype == "" {
+		return nil, nil
+	}
+
+	query := `
+	SELECT opc.opcname
+	FROM pg_opclass opc
+	JOIN pg_am am ON am.oid = opc.opcmethod
+	WHERE am.amname = 'btree'
+	AND opc.opcintype = $1::text::regtype::oid
+	ORDER BY opc.opcdefault DESC, opc.opcname
+	`
+
+	rows, err := pool.Query(ctx, query, subtype)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения классов операторов: %w", err)
+	}
+	defer rows.Close()
+
+	var classes []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения класса операторов: %w", err)
+		}
+		classes = append(classes, name)
+	}
+	return classes, rows.Err()
+}
@@ -0,0 +1,546 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Типы объектов для GRANT/REVOKE
+const (
+	ObjectTable    = "TABLE"
+	ObjectView     = "VIEW"
+	ObjectColumn   = "COLUMN"
+	ObjectSequence = "SEQUENCE"
+	ObjectSchema   = "SCHEMA"
+	ObjectType     = "TYPE"
+)
+
+// PrivilegeObjectTypes типы объектов в порядке отображения
+var PrivilegeObjectTypes = []string{ObjectTable, ObjectView, ObjectColumn, ObjectSequence, ObjectSchema, ObjectType}
+
+// privilegesByObject допустимые привилегии для каждого типа объекта
+var privilegesByObject = map[string][]string{
+	ObjectTable:    {"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"},
+	ObjectView:     {"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"},
+	ObjectColumn:   {"SELECT", "INSERT", "UPDATE", "REFERENCES"},
+	ObjectSequence: {"USAGE", "SELECT", "UPDATE"},
+	ObjectSchema:   {"USAGE", "CREATE"},
+	ObjectType:     {"USAGE"},
+}
+
+// RoleInfo информация о роли
+type RoleInfo struct {
+	Name       string
+	CanLogin   bool
+	Superuser  bool
+	CreateDB   bool
+	CreateRole bool
+	Inherit    bool
+	ConnLimit  int
+	ValidUntil string
+	MemberOf   []string
+}
+
+// RoleOptions параметры создания/изменения роли
+type RoleOptions struct {
+	Login           bool
+	Superuser       bool
+	CreateDB        bool
+	CreateRole      bool
+	Inherit         bool
+	Password        string // пусто — пароль не меняется
+	ConnectionLimit int    // -1 — без ограничения
+	ValidUntil      string // пусто — без срока действия (для ALTER ROLE снимает срок: VALID UNTIL 'infinity')
+}
+
+// PrivilegeEntry одна запись ACL
+type PrivilegeEntry struct {
+	Grantee   string // имя роли или PUBLIC
+	Grantor   string
+	Privilege string
+	Grantable bool
+	Column    string // для привилегий на столбцы
+}
+
+// ObjectPrivileges возвращает список допустимых привилегий для типа объекта
+func ObjectPrivileges(objectType string) []string {
+	return privilegesByObject[strings.ToUpper(objectType)]
+}
+
+// buildRoleOptions формирует список опций для CREATE/ALTER ROLE
+func buildRoleOptions(opts RoleOptions) []string {
+	flag := func(enabled bool, name string) string {
+		if enabled {
+			return name
+		}
+		return "NO" + name
+	}
+
+	options := []string{
+		flag(opts.Login, "LOGIN"),
+		flag(opts.Superuser, "SUPERUSER"),
+		flag(opts.CreateDB, "CREATEDB"),
+		flag(opts.CreateRole, "CREATEROLE"),
+		flag(opts.Inherit, "INHERIT"),
+		fmt.Sprintf("CONNECTION LIMIT %d", opts.ConnectionLimit),
+	}
+	if opts.Password != "" {
+		options = append(options, "PASSWORD "+quoteLiteral(opts.Password))
+	}
+	if opts.ValidUntil != "" {
+		options = append(options, "VALID UNTIL "+quoteLiteral(opts.ValidUntil))
+	}
+	return options
+}
+
+// buildRoleChanges формирует опции ALTER ROLE только для атрибутов, отличающихся от текущих:
+// изменение SUPERUSER требует прав суперпользователя, поэтому неизменные атрибуты не передаются
+func buildRoleChanges(current RoleInfo, opts RoleOptions) []string {
+	var options []string
+	flag := func(enabled, was bool, name string) {
+		if enabled == was {
+			return
+		}
+		if enabled {
+			options = append(options, name)
+		} else {
+			options = append(options, "NO"+name)
+		}
+	}
+
+	flag(opts.Login, current.CanLogin, "LOGIN")
+	flag(opts.Superuser, current.Superuser, "SUPERUSER")
+	flag(opts.CreateDB, current.CreateDB, "CREATEDB")
+	flag(opts.CreateRole, current.CreateRole, "CREATEROLE")
+	flag(opts.Inherit, current.Inherit, "INHERIT")
+	if opts.ConnectionLimit != current.ConnLimit {
+		options = append(options, fmt.Sprintf("CONNECTION LIMIT %d", opts.ConnectionLimit))
+	}
+	if opts.Password != "" {
+		options = append(options, "PASSWORD "+quoteLiteral(opts.Password))
+	}
+
+	currentValidUntil := current.ValidUntil
+	if currentValidUntil == "infinity" {
+		currentValidUntil = ""
+	}
+	validUntil := opts.ValidUntil
+	if validUntil == "infinity" {
+		validUntil = ""
+	}
+	if validUntil != currentValidUntil {
+		if validUntil == "" {
+			options = append(options, "VALID UNTIL 'infinity'")
+		} else {
+			options = append(options, "VALID UNTIL "+quoteLiteral(validUntil))
+		}
+	}
+	return options
+}
+
+// validateGrantee проверяет получателя привилегий (роль или PUBLIC)
+func validateGrantee(grantee string) error {
+	if strings.EqualFold(grantee, "PUBLIC") {
+		return nil
+	}
+	return validateSQLIdent(grantee)
+}
+
+// ListRoles возвращает роли кластера (без системных pg_*)
+func ListRoles(ctx context.Context, pool *pgxpool.Pool) ([]RoleInfo, error) {
+	query := `
+	SELECT
+		r.rolname,
+		r.rolcanlogin,
+		r.rolsuper,
+		r.rolcreatedb,
+		r.rolcreaterole,
+		r.rolinherit,
+		r.rolconnlimit,
+		COALESCE(r.rolvaliduntil::text, ''),
+		ARRAY(
+			SELECT g.rolname
+			FROM pg_auth_members m
+			JOIN pg_roles g ON g.oid = m.roleid
+			WHERE m.member = r.oid
+			ORDER BY g.rolname
+		)
+	FROM pg_roles r
+	WHERE r.rolname !~ '^pg_'
+	ORDER BY r.rolname
+	`
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ролей: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []RoleInfo
+	for rows.Next() {
+		var role RoleInfo
+		if err := rows.Scan(&role.Name, &role.CanLogin, &role.Superuser, &role.CreateDB, &role.CreateRole,
+			&role.Inherit, &role.ConnLimit, &role.ValidUntil, &role.MemberOf); err != nil {
+			return nil, fmt.Errorf("ошибка чтения роли: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// CreateRole создаёт роль и добавляет её в указанные группы
+func CreateRole(ctx context.Context, pool *pgxpool.Pool, roleName string, opts RoleOptions, memberOf []string) error {
+	if err := validateSQLIdent(roleName); err != nil {
+		return err
+	}
+	for _, group := range memberOf {
+		if err := validateSQLIdent(group); err != nil {
+			return err
+		}
+	}
+
+	query := fmt.Sprintf("CREATE ROLE %s WITH %s", roleName, strings.Join(buildRoleOptions(opts), " "))
+	if len(memberOf) > 0 {
+		query += " IN ROLE " + strings.Join(memberOf, ", ")
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Создание роли: %v", err)
+		return fmt.Errorf("ошибка создания роли %s: %w", roleName, err)
+	}
+
+	fmt.Printf("Роль '%s' создана\n", roleName)
+	return nil
+}
+
+// AlterRole изменяет атрибуты роли; передаются только атрибуты, отличающиеся от текущих.
+// Пустой ValidUntil снимает срок действия роли
+func AlterRole(ctx context.Context, pool *pgxpool.Pool, roleName string, opts RoleOptions) error {
+	if err := validateSQLIdent(roleName); err != nil {
+		return err
+	}
+
+	roles, err := ListRoles(ctx, pool)
+	if err != nil {
+		return err
+	}
+	var current *RoleInfo
+	for i := range roles {
+		if roles[i].Name == roleName {
+			current = &roles[i]
+		}
+	}
+	if current == nil {
+		return fmt.Errorf("роль %s не найдена", roleName)
+	}
+
+	changes := buildRoleChanges(*current, opts)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("ALTER ROLE %s WITH %s", roleName, strings.Join(changes, " "))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Изменение роли: %v", err)
+		return fmt.Errorf("ошибка изменения роли %s: %w", roleName, err)
+	}
+	return nil
+}
+
+// DropRole удаляет роль
+func DropRole(ctx context.Context, pool *pgxpool.Pool, roleName string) error {
+	if err := validateSQLIdent(roleName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DROP ROLE IF EXISTS %s", roleName)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Удаление роли: %v", err)
+		return fmt.Errorf("не удалось удалить роль %s: %w", roleName, err)
+	}
+	return nil
+}
+
+// GrantRoleMembership добавляет member в группу role
+func GrantRoleMembership(ctx context.Context, pool *pgxpool.Pool, role, member string) error {
+	if err := validateSQLIdent(role); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(member); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("GRANT %s TO %s", role, member)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Добавление в роль: %v", err)
+		return fmt.Errorf("не удалось добавить %s в роль %s: %w", member, role, err)
+	}
+	return nil
+}
+
+// RevokeRoleMembership исключает member из группы role
+func RevokeRoleMembership(ctx context.Context, pool *pgxpool.Pool, role, member string) error {
+	if err := validateSQLIdent(role); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(member); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("REVOKE %s FROM %s", role, member)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Исключение из роли: %v", err)
+		return fmt.Errorf("не удалось исключить %s из роли %s: %w", member, role, err)
+	}
+	return nil
+}
+
+// buildPrivilegeTarget формирует список привилегий и объект для GRANT/REVOKE
+// Пример: "SELECT (name, price), UPDATE (price)" и "TABLE products"
+func buildPrivilegeTarget(objectType, objectName string, columns, privileges []string) (string, string, error) {
+	objectType = strings.ToUpper(objectType)
+	allowed, ok := privilegesByObject[objectType]
+	if !ok {
+		return "", "", fmt.Errorf("неизвестный тип объекта: %s", objectType)
+	}
+	if err := validateSQLIdent(objectName); err != nil {
+		return "", "", err
+	}
+	if len(privileges) == 0 {
+		return "", "", fmt.Errorf("укажите хотя бы одну привилегию")
+	}
+
+	allowedSet := make(map[string]bool)
+	for _, p := range allowed {
+		allowedSet[p] = true
+	}
+
+	columnList := ""
+	if objectType == ObjectColumn {
+		if len(columns) == 0 {
+			return "", "", fmt.Errorf("укажите хотя бы один столбец")
+		}
+		for _, col := range columns {
+			if err := validateSQLIdent(col); err != nil {
+				return "", "", err
+			}
+		}
+		columnList = " (" + strings.Join(columns, ", ") + ")"
+	}
+
+	var privs []string
+	for _, p := range privileges {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if !allowedSet[p] {
+			return "", "", fmt.Errorf("привилегия %s недопустима для %s", p, objectType)
+		}
+		privs = append(privs, p+columnList)
+	}
+
+	// Представления и столбцы указываются через ключевое слово TABLE
+	keyword := objectType
+	if objectType == ObjectView || objectType == ObjectColumn {
+		keyword = ObjectTable
+	}
+
+	return strings.Join(privs, ", "), keyword + " " + objectName, nil
+}
+
+// GrantPrivileges выдаёт привилегии на объект
+// Пример: GrantPrivileges(ctx, pool, ObjectColumn, "products", []string{"price"}, []string{"SELECT"}, "analyst", false)
+func GrantPrivileges(ctx context.Context, pool *pgxpool.Pool, objectType, objectName string, columns, privileges []string,
+	grantee string, withGrantOption bool) error {
+	if err := validateGrantee(grantee); err != nil {
+		return err
+	}
+	privs, target, err := buildPrivilegeTarget(objectType, objectName, columns, privileges)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("GRANT %s ON %s TO %s", privs, target, grantee)
+	if withGrantOption {
+		query += " WITH GRANT OPTION"
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("GRANT: %v", err)
+		return fmt.Errorf("не удалось выдать привилегии: %w", err)
+	}
+	return nil
+}
+
+// RevokePrivileges отзывает привилегии на объект
+func RevokePrivileges(ctx context.Context, pool *pgxpool.Pool, objectType, objectName string, columns, privileges []string,
+	grantee string, cascade bool) error {
+	if err := validateGrantee(grantee); err != nil {
+		return err
+	}
+	privs, target, err := buildPrivilegeTarget(objectType, objectName, columns, privileges)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("REVOKE %s ON %s FROM %s", privs, target, grantee)
+	if cascade {
+		query += " CASCADE"
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("REVOKE: %v", err)
+		return fmt.Errorf("не удалось отозвать привилегии: %w", err)
+	}
+	return nil
+}
+
+// ListPrivilegeObjects возвращает объекты схемы public заданного типа
+// Для COLUMN возвращаются таблицы, столбцы выбираются отдельно
+func ListPrivilegeObjects(ctx context.Context, pool *pgxpool.Pool, objectType string) ([]string, error) {
+	var query string
+	switch strings.ToUpper(objectType) {
+	case ObjectTable, ObjectColumn:
+		query = `SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = 'public' AND c.relkind IN ('r', 'p') ORDER BY 1`
+	case ObjectView:
+		query = `SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = 'public' AND c.relkind IN ('v', 'm') ORDER BY 1`
+	case ObjectSequence:
+		query = `SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = 'public' AND c.relkind = 'S' ORDER BY 1`
+	case ObjectSchema:
+		query = `SELECT nspname FROM pg_namespace
+			WHERE nspname !~ '^pg_' AND nspname <> 'information_schema' ORDER BY 1`
+	case ObjectType:
+		query = `SELECT t.typname FROM pg_type t JOIN pg_namespace n ON n.oid = t.typnamespace
+			WHERE n.nspname = 'public' AND t.typtype IN ('e', 'c', 'd', 'r')
+			AND (t.typrelid = 0 OR (SELECT c.relkind FROM pg_class c WHERE c.oid = t.typrelid) = 'c')
+			ORDER BY 1`
+	default:
+		return nil, fmt.Errorf("неизвестный тип объекта: %s", objectType)
+	}
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения объектов: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения объекта: %w", err)
+		}
+		objects = append(objects, name)
+	}
+	return objects, rows.Err()
+}
+
+// GetObjectPrivileges читает ACL объекта (для COLUMN — ACL всех столбцов таблицы)
+// Если ACL не задан явно, используются привилегии по умолчанию (acldefault)
+func GetObjectPrivileges(ctx context.Context, pool *pgxpool.Pool, objectType, objectName string) ([]PrivilegeEntry, error) {
+	if err := validateSQLIdent(objectName); err != nil {
+		return nil, err
+	}
+
+	const aclColumns = `
+		COALESCE(gr.rolname, 'PUBLIC'),
+		COALESCE(gt.rolname, ''),
+		a.privilege_type,
+		a.is_grantable`
+	const aclJoins = `
+		LEFT JOIN pg_roles gr ON gr.oid = a.grantee
+		LEFT JOIN pg_roles gt ON gt.oid = a.grantor`
+
+	var query string
+	switch strings.ToUpper(objectType) {
+	case ObjectTable, ObjectView, ObjectSequence:
+		query = `SELECT` + aclColumns + `, ''
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		CROSS JOIN LATERAL aclexplode(COALESCE(c.relacl,
+			acldefault(CASE WHEN c.relkind = 'S' THEN 's' ELSE 'r' END::"char", c.relowner))) a` + aclJoins + `
+		WHERE n.nspname = 'public' AND c.relname = $1`
+	case ObjectColumn:
+		query = `SELECT` + aclColumns + `, att.attname
+		FROM pg_attribute att
+		JOIN pg_class c ON c.oid = att.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		CROSS JOIN LATERAL aclexplode(att.attacl) a` + aclJoins + `
+		WHERE n.nspname = 'public' AND c.relname = $1
+		AND att.attnum > 0 AND NOT att.attisdropped AND att.attacl IS NOT NULL`
+	case ObjectSchema:
+		query = `SELECT` + aclColumns + `, ''
+		FROM pg_namespace n
+		CROSS JOIN LATERAL aclexplode(COALESCE(n.nspacl, acldefault('n', n.nspowner))) a` + aclJoins + `
+		WHERE n.nspname = $1`
+	case ObjectType:
+		query = `SELECT` + aclColumns + `, ''
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		CROSS JOIN LATERAL aclexplode(COALESCE(t.typacl, acldefault('T', t.typowner))) a` + aclJoins + `
+		WHERE n.nspname = 'public' AND t.typname = $1`
+	default:
+		return nil, fmt.Errorf("неизвестный тип объекта: %s", objectType)
+	}
+
+	rows, err := pool.Query(ctx, query, objectName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ACL: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []PrivilegeEntry
+	for rows.Next() {
+		var e PrivilegeEntry
+		if err := rows.Scan(&e.Grantee, &e.Grantor, &e.Privilege, &e.Grantable, &e.Column); err != nil {
+			return nil, fmt.Errorf("ошибка чтения записи ACL: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// BuildPrivilegeMatrix строит матрицу «получатель × привилегия»
+// Ячейка: "✓" — привилегия есть, "✓*" — с правом передачи (GRANT OPTION), "" — нет.
+// Для COLUMN получатель указывается как "роль (столбец)"
+func BuildPrivilegeMatrix(objectType string, entries []PrivilegeEntry) [][]string {
+	privileges := ObjectPrivileges(objectType)
+	header := append([]string{"Роль"}, privileges...)
+
+	cells := make(map[string]map[string]string)
+	for _, e := range entries {
+		grantee := e.Grantee
+		if e.Column != "" {
+			grantee = fmt.Sprintf("%s (%s)", e.Grantee, e.Column)
+		}
+		if cells[grantee] == nil {
+			cells[grantee] = make(map[string]string)
+		}
+		mark := "✓"
+		if e.Grantable {
+			mark = "✓*"
+		}
+		cells[grantee][e.Privilege] = mark
+	}
+
+	grantees := make([]string, 0, len(cells))
+	for g := range cells {
+		grantees = append(grantees, g)
+	}
+	sort.Strings(grantees)
+
+	matrix := [][]string{header}
+	for _, g := range grantees {
+		row := []string{g}
+		for _, p := range privileges {
+			row = append(row, cells[g][p])
+		}
+		matrix = append(matrix, row)
+	}
+	return matrix
+}
@@ -0,0 +1,422 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RoutineArgument аргумент функции или процедуры
+type RoutineArgument struct {
+	Mode    string // IN, OUT, INOUT, VARIADIC
+	Name    string
+	Type    string
+	Default string
+}
+
+// RoutineDefinition описание создаваемой функции или процедуры
+type RoutineDefinition struct {
+	Kind       string // FUNCTION или PROCEDURE
+	Name       string
+	Arguments  []RoutineArgument
+	Returns    string // только для FUNCTION, например: integer, SETOF products, TABLE(id int, name text)
+	Language   string // plpgsql, sql
+	Volatility string // VOLATILE, STABLE, IMMUTABLE (только для FUNCTION)
+	Body       string
+}
+
+// RoutineInfo информация о существующей функции или процедуре
+type RoutineInfo struct {
+	OID       uint32
+	Name      string
+	Kind      string // FUNCTION или PROCEDURE
+	Arguments string // сигнатура (pg_get_function_identity_arguments)
+	Result    string // pg_get_function_result, пусто для процедур
+	Language  string
+}
+
+// Signature возвращает сигнатуру вида name(p_id integer, p_name text) для отображения
+func (ri RoutineInfo) Signature() string {
+	return fmt.Sprintf("%s(%s)", ri.Name, ri.Arguments)
+}
+
+// routineDDLPattern проверяет, что текст является определением функции или процедуры
+var routineDDLPattern = regexp.MustCompile(`(?is)^\s*CREATE\s+(OR\s+REPLACE\s+)?(FUNCTION|PROCEDURE)\s`)
+
+// normalizeArgumentMode приводит режим аргумента к верхнему регистру, по умолчанию IN
+func normalizeArgumentMode(mode string) (string, error) {
+	mode = strings.ToUpper(strings.TrimSpace(mode))
+	switch mode {
+	case "":
+		return "IN", nil
+	case "IN", "OUT", "INOUT", "VARIADIC":
+		return mode, nil
+	}
+	return "", fmt.Errorf("недопустимый режим аргумента: %s", mode)
+}
+
+// argumentDefaultPattern находит DEFAULT в строке аргумента без учёта регистра. Поиск идёт по
+// исходной строке: strings.ToUpper может изменить длину в байтах, и смещения не совпадут
+var argumentDefaultPattern = regexp.MustCompile(`(?i)\sDEFAULT\s`)
+
+// ParseRoutineArguments разбирает аргументы, по одному на строку
+// Формат строки: [IN|OUT|INOUT|VARIADIC] имя тип [DEFAULT значение]
+// Пример: "IN p_price numeric DEFAULT 0"
+func ParseRoutineArguments(text string) ([]RoutineArgument, error) {
+	var args []RoutineArgument
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var arg RoutineArgument
+		if loc := argumentDefaultPattern.FindStringIndex(line); loc != nil {
+			arg.Default = strings.TrimSpace(line[loc[1]:])
+			line = strings.TrimSpace(line[:loc[0]])
+		}
+
+		arg.Mode = "IN"
+		parts := strings.Fields(line)
+		if mode, err := normalizeArgumentMode(parts[0]); err == nil {
+			arg.Mode = mode
+			parts = parts[1:]
+		}
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("строка %d: ожидается 'имя тип': %s", i+1, line)
+		}
+		arg.Name = parts[0]
+		arg.Type = strings.Join(parts[1:], " ")
+
+		if err := validateSQLIdent(arg.Name); err != nil {
+			return nil, fmt.Errorf("строка %d: %w", i+1, err)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// BuildRoutineSQL формирует CREATE [OR REPLACE] FUNCTION/PROCEDURE
+func BuildRoutineSQL(def RoutineDefinition, replace bool) (string, error) {
+	kind := strings.ToUpper(strings.TrimSpace(def.Kind))
+	if kind != "FUNCTION" && kind != "PROCEDURE" {
+		return "", fmt.Errorf("недопустимый вид подпрограммы: %s", def.Kind)
+	}
+	if err := validateSQLIdent(def.Name); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(def.Body) == "" {
+		return "", fmt.Errorf("тело подпрограммы не может быть пустым")
+	}
+	if strings.Contains(def.Body, "$body$") {
+		return "", fmt.Errorf("тело не должно содержать разделитель $body$")
+	}
+
+	language := strings.TrimSpace(def.Language)
+	if language == "" {
+		language = "plpgsql"
+	}
+	if err := validateSQLIdent(language); err != nil {
+		return "", err
+	}
+
+	var argDefs []string
+	for _, arg := range def.Arguments {
+		mode, err := normalizeArgumentMode(arg.Mode)
+		if err != nil {
+			return "", err
+		}
+		if err := validateSQLIdent(arg.Name); err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(arg.Type) == "" {
+			return "", fmt.Errorf("не указан тип аргумента %s", arg.Name)
+		}
+		argDef := fmt.Sprintf("%s %s %s", mode, arg.Name, arg.Type)
+		if arg.Default != "" {
+			argDef += " DEFAULT " + arg.Default
+		}
+		argDefs = append(argDefs, argDef)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	if replace {
+		sb.WriteString("OR REPLACE ")
+	}
+	sb.WriteString(fmt.Sprintf("%s %s(%s)", kind, def.Name, strings.Join(argDefs, ", ")))
+
+	if kind == "FUNCTION" {
+		if strings.TrimSpace(def.Returns) == "" {
+			return "", fmt.Errorf("для функции необходимо указать возвращаемый тип")
+		}
+		sb.WriteString("\nRETURNS " + def.Returns)
+	}
+	sb.WriteString("\nLANGUAGE " + language)
+	if kind == "FUNCTION" && def.Volatility != "" {
+		volatility := strings.ToUpper(def.Volatility)
+		if volatility != "VOLATILE" && volatility != "STABLE" && volatility != "IMMUTABLE" {
+			return "", fmt.Errorf("недопустимая категория изменчивости: %s", def.Volatility)
+		}
+		sb.WriteString("\n" + volatility)
+	}
+	sb.WriteString("\nAS $body$\n" + def.Body + "\n$body$")
+
+	return sb.String(), nil
+}
+
+// CreateRoutine создаёт или заменяет функцию/процедуру
+func CreateRoutine(ctx context.Context, pool *pgxpool.Pool, def RoutineDefinition, replace bool) error {
+	query, err := BuildRoutineSQL(def, replace)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Создание подпрограммы: %v", err)
+		return fmt.Errorf("ошибка создания %s %s: %w", strings.ToLower(def.Kind), def.Name, err)
+	}
+
+	fmt.Printf("%s '%s' успешно создана\n", strings.ToUpper(def.Kind), def.Name)
+	return nil
+}
+
+// ExecuteRoutineDDL выполняет отредактированное определение функции/процедуры
+// (например, изменённый текст pg_get_functiondef)
+func ExecuteRoutineDDL(ctx context.Context, pool *pgxpool.Pool, ddl string) error {
+	if !routineDDLPattern.MatchString(ddl) {
+		return fmt.Errorf("ожидается CREATE [OR REPLACE] FUNCTION или PROCEDURE")
+	}
+
+	if _, err := pool.Exec(ctx, ddl); err != nil {
+		log.Printf("Изменение подпрограммы: %v", err)
+		return fmt.Errorf("ошибка сохранения определения: %w", err)
+	}
+	return nil
+}
+
+// DropRoutine удаляет функцию или процедуру по сигнатуре
+func DropRoutine(ctx context.Context, pool *pgxpool.Pool, routine RoutineInfo) error {
+	if err := validateSQLIdent(routine.Name); err != nil {
+		return err
+	}
+	kind := strings.ToUpper(routine.Kind)
+	if kind != "FUNCTION" && kind != "PROCEDURE" {
+		return fmt.Errorf("недопустимый вид подпрограммы: %s", routine.Kind)
+	}
+
+	// Сигнатуру берём из каталога, а не из пользовательского ввода
+	var regproc string
+	err := pool.QueryRow(ctx, "SELECT $1::oid::regprocedure::text", routine.OID).Scan(&regproc)
+	if err != nil || regproc == "" {
+		return fmt.Errorf("подпрограмма %s не найдена", routine.Signature())
+	}
+
+	query := fmt.Sprintf("DROP %s %s", kind, regproc)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Удаление подпрограммы: %v", err)
+		return fmt.Errorf("не удалось удалить %s: %w", routine.Signature(), err)
+	}
+	return nil
+}
+
+// ListRoutines возвращает функции и процедуры схемы public (без триггерных и агрегатных)
+func ListRoutines(ctx context.Context, pool *pgxpool.Pool) ([]RoutineInfo, error) {
+	query := `
+	SELECT
+		p.oid,
+		p.proname,
+		CASE p.prokind WHEN 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+		pg_get_function_identity_arguments(p.oid),
+		COALESCE(pg_get_function_result(p.oid), ''),
+		l.lanname
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	JOIN pg_language l ON l.oid = p.prolang
+	WHERE n.nspname = 'public'
+	AND p.prokind IN ('f', 'p')
+	AND p.prorettype <> 'trigger'::regtype
+	ORDER BY p.proname, 4
+	`
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения функций: %w", err)
+	}
+	defer rows.Close()
+
+	var routines []RoutineInfo
+	for rows.Next() {
+		var ri RoutineInfo
+		if err := rows.Scan(&ri.OID, &ri.Name, &ri.Kind, &ri.Arguments, &ri.Result, &ri.Language); err != nil {
+			return nil, fmt.Errorf("ошибка чтения функции: %w", err)
+		}
+		routines = append(routines, ri)
+	}
+	return routines, rows.Err()
+}
+
+// GetRoutineDefinition возвращает полное определение через pg_get_functiondef
+func GetRoutineDefinition(ctx context.Context, pool *pgxpool.Pool, routine RoutineInfo) (string, error) {
+	var definition string
+	err := pool.QueryRow(ctx, "SELECT pg_get_functiondef($1::oid)", routine.OID).Scan(&definition)
+	if err != nil {
+		log.Printf("Получение определения функции: %v", err)
+		return "", fmt.Errorf("не удалось получить определение %s: %w", routine.Signature(), err)
+	}
+	return definition, nil
+}
+
+// GetRoutineArguments возвращает аргументы подпрограммы в порядке объявления
+func GetRoutineArguments(ctx context.Context, pool *pgxpool.Pool, routine RoutineInfo) ([]RoutineArgument, error) {
+	query := `
+	SELECT
+		COALESCE(a.name, ''),
+		CASE COALESCE(a.mode, 'i')
+			WHEN 'i' THEN 'IN'
+			WHEN 'o' THEN 'OUT'
+			WHEN 'b' THEN 'INOUT'
+			WHEN 'v' THEN 'VARIADIC'
+			WHEN 't' THEN 'TABLE'
+		END,
+		format_type(a.type, NULL)
+	FROM pg_proc p,
+	LATERAL unnest(
+		COALESCE(p.proallargtypes, p.proargtypes::oid[]),
+		p.proargnames,
+		p.proargmodes
+	) WITH ORDINALITY AS a(type, name, mode, pos)
+	WHERE p.oid = $1::oid
+	ORDER BY a.pos
+	`
+	rows, err := pool.Query(ctx, query, routine.OID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения аргументов: %w", err)
+	}
+	defer rows.Close()
+
+	var args []RoutineArgument
+	for rows.Next() {
+		var arg RoutineArgument
+		if err := rows.Scan(&arg.Name, &arg.Mode, &arg.Type); err != nil {
+			return nil, fmt.Errorf("ошибка чтения аргумента: %w", err)
+		}
+		// Столбцы RETURNS TABLE не являются входными аргументами
+		if arg.Mode == "TABLE" {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args, rows.Err()
+}
+
+// IsInputArgument сообщает, передаётся ли аргумент при вызове
+func (ra RoutineArgument) IsInputArgument() bool {
+	return ra.Mode != "OUT"
+}
+
+// CallRoutine вызывает функцию (SELECT * FROM f(...)) или процедуру (CALL p(...))
+// values — значения входных аргументов в текстовом виде, "NULL" передаётся как NULL.
+// Возвращает результат в виде [][]string, где первая строка — заголовки
+func CallRoutine(ctx context.Context, pool *pgxpool.Pool, routine RoutineInfo, values []string) ([][]string, error) {
+	args, err := GetRoutineArguments(ctx, pool, routine)
+	if err != nil {
+		return nil, err
+	}
+
+	var regproc string
+	if err := pool.QueryRow(ctx, "SELECT $1::oid::regproc::text", routine.OID).Scan(&regproc); err != nil {
+		return nil, fmt.Errorf("подпрограмма %s не найдена: %w", routine.Signature(), err)
+	}
+
+	isProcedure := strings.ToUpper(routine.Kind) == "PROCEDURE"
+
+	var placeholders []string
+	var params []interface{}
+	valueIdx := 0
+	for _, arg := range args {
+		if !arg.IsInputArgument() {
+			// OUT-аргументы процедуры передаются как NULL, у функций они опускаются
+			if isProcedure {
+				placeholders = append(placeholders, "NULL")
+			}
+			continue
+		}
+		if valueIdx >= len(values) {
+			return nil, fmt.Errorf("не задано значение аргумента %s", arg.Name)
+		}
+
+		value := values[valueIdx]
+		valueIdx++
+		params = append(params, nullableArgument(value))
+		placeholder := fmt.Sprintf("$%d::text::%s", len(params), arg.Type)
+		if arg.Mode == "VARIADIC" {
+			// Массив передаётся целиком: без VARIADIC функция не находится по типам аргументов
+			placeholder = "VARIADIC " + placeholder
+		}
+		placeholders = append(placeholders, placeholder)
+	}
+
+	var query string
+	if isProcedure {
+		query = fmt.Sprintf("CALL %s(%s)", regproc, strings.Join(placeholders, ", "))
+	} else {
+		query = fmt.Sprintf("SELECT * FROM %s(%s)", regproc, strings.Join(placeholders, ", "))
+	}
+
+	return queryAsStrings(ctx, pool, query, params...)
+}
+
+// nullableArgument преобразует "NULL" в nil для передачи параметра
+func nullableArgument(value string) interface{} {
+	if strings.EqualFold(strings.TrimSpace(value), "NULL") {
+		return nil
+	}
+	return value
+}
+
+// queryAsStrings выполняет запрос и возвращает результат в виде [][]string,
+// первая строка — заголовки столбцов, NULL отображается как "NULL"
+func queryAsStrings(ctx context.Context, pool *pgxpool.Pool, query string, args ...interface{}) ([][]string, error) {
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	return rowsAsStrings(rows)
+}
+
+// rowsAsStrings читает все строки результата в [][]string (первая строка — заголовки)
+func rowsAsStrings(rows pgx.Rows) ([][]string, error) {
+	defer rows.Close()
+
+	var header []string
+	for _, fd := range rows.FieldDescriptions() {
+		header = append(header, string(fd.Name))
+	}
+	result := [][]string{header}
+
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения строки: %w", err)
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			if v == nil {
+				row[i] = "NULL"
+			} else {
+				row[i] = fmt.Sprintf("%v", v)
+			}
+		}
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,253 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaColumn столбец таблицы в модели схемы
+type SchemaColumn struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	PrimaryKey bool
+	ForeignKey bool
+	Unique     bool
+}
+
+// SchemaTable таблица в модели схемы
+type SchemaTable struct {
+	Name    string
+	Columns []SchemaColumn
+}
+
+// SchemaRelation внешний ключ: FromTable(FromColumns) → ToTable(ToColumns)
+type SchemaRelation struct {
+	Name        string
+	FromTable   string
+	FromColumns []string
+	ToTable     string
+	ToColumns   []string
+	Nullable    bool // хотя бы один столбец FK допускает NULL (связь 0..1)
+	Unique      bool // столбцы FK уникальны (связь 1:1)
+}
+
+// SchemaEnum ENUM тип в модели схемы
+type SchemaEnum struct {
+	Name   string
+	Values []string
+}
+
+// SchemaModel таблицы, связи и ENUM типы схемы public
+type SchemaModel struct {
+	Tables    []SchemaTable
+	Relations []SchemaRelation
+	Enums     []SchemaEnum
+}
+
+// Table возвращает таблицу модели по имени
+func (sm *SchemaModel) Table(name string) *SchemaTable {
+	for i := range sm.Tables {
+		if sm.Tables[i].Name == name {
+			return &sm.Tables[i]
+		}
+	}
+	return nil
+}
+
+// GetSchemaModel загружает таблицы, столбцы, внешние ключи и ENUM типы схемы public.
+// Если tables не пуст, загружаются только указанные таблицы, связи между ними
+// и ENUM типы, используемые их столбцами.
+func GetSchemaModel(ctx context.Context, pool *pgxpool.Pool, tables []string) (*SchemaModel, error) {
+	for _, table := range tables {
+		if err := validateSQLIdent(table); err != nil {
+			return nil, err
+		}
+	}
+
+	columnsQuery := `
+	SELECT
+		c.relname,
+		a.attname,
+		format_type(a.atttypid, a.atttypmod),
+		a.attnotnull,
+		EXISTS (SELECT 1 FROM pg_constraint p
+			WHERE p.conrelid = c.oid AND p.contype = 'p' AND a.attnum = ANY(p.conkey)),
+		EXISTS (SELECT 1 FROM pg_constraint f
+			WHERE f.conrelid = c.oid AND f.contype = 'f' AND a.attnum = ANY(f.conkey)),
+		EXISTS (SELECT 1 FROM pg_constraint u
+			WHERE u.conrelid = c.oid AND u.contype = 'u' AND u.conkey = ARRAY[a.attnum])
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+	WHERE n.nspname = 'public' AND c.relkind IN ('r', 'p')
+	AND NOT c.relispartition
+	AND (cardinality($1::text[]) = 0 OR c.relname = ANY($1))
+	ORDER BY c.relname, a.attnum
+	`
+
+	if tables == nil {
+		tables = []string{}
+	}
+
+	rows, err := pool.Query(ctx, columnsQuery, tables)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения структуры таблиц: %w", err)
+	}
+
+	model := &SchemaModel{}
+	for rows.Next() {
+		var tableName string
+		var col SchemaColumn
+		if err := rows.Scan(&tableName, &col.Name, &col.Type, &col.NotNull, &col.PrimaryKey, &col.ForeignKey, &col.Unique); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("ошибка чтения столбца: %w", err)
+		}
+		if len(model.Tables) == 0 || model.Tables[len(model.Tables)-1].Name != tableName {
+			model.Tables = append(model.Tables, SchemaTable{Name: tableName})
+		}
+		last := &model.Tables[len(model.Tables)-1]
+		last.Columns = append(last.Columns, col)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	relationsQuery := `
+	SELECT
+		con.conname,
+		src.relname,
+		ARRAY(SELECT a.attname::text FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum ORDER BY k.ord),
+		dst.relname,
+		ARRAY(SELECT a.attname::text FROM unnest(con.confkey) WITH ORDINALITY k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum ORDER BY k.ord),
+		EXISTS (SELECT 1 FROM pg_attribute a
+			WHERE a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey) AND NOT a.attnotnull),
+		EXISTS (SELECT 1 FROM pg_constraint u
+			WHERE u.conrelid = con.conrelid AND u.contype IN ('p', 'u')
+			AND u.conkey @> con.conkey AND u.conkey <@ con.conkey)
+	FROM pg_constraint con
+	JOIN pg_class src ON src.oid = con.conrelid
+	JOIN pg_class dst ON dst.oid = con.confrelid
+	JOIN pg_namespace n ON n.oid = src.relnamespace
+	WHERE con.contype = 'f' AND n.nspname = 'public'
+	AND NOT src.relispartition
+	ORDER BY src.relname, con.conname
+	`
+
+	rows, err = pool.Query(ctx, relationsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения внешних ключей: %w", err)
+	}
+
+	for rows.Next() {
+		var rel SchemaRelation
+		if err := rows.Scan(&rel.Name, &rel.FromTable, &rel.FromColumns, &rel.ToTable, &rel.ToColumns, &rel.Nullable, &rel.Unique); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("ошибка чтения внешнего ключа: %w", err)
+		}
+		// Связи с таблицами вне выборки не показываем
+		if model.Table(rel.FromTable) == nil || model.Table(rel.ToTable) == nil {
+			continue
+		}
+		model.Relations = append(model.Relations, rel)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	enumsQuery := `
+	SELECT t.typname, ARRAY(SELECT e.enumlabel::text FROM pg_enum e
+		WHERE e.enumtypid = t.oid ORDER BY e.enumsortorder)
+	FROM pg_type t
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	WHERE n.nspname = 'public' AND t.typtype = 'e'
+	ORDER BY t.typname
+	`
+	rows, err = pool.Query(ctx, enumsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ENUM типов: %w", err)
+	}
+	defer rows.Close()
+
+	used := make(map[string]bool)
+	for _, t := range model.Tables {
+		for _, col := range t.Columns {
+			used[strings.TrimSuffix(col.Type, "[]")] = true
+		}
+	}
+
+	for rows.Next() {
+		var enum SchemaEnum
+		if err := rows.Scan(&enum.Name, &enum.Values); err != nil {
+			return nil, fmt.Errorf("ошибка чтения ENUM типа: %w", err)
+		}
+		if len(tables) > 0 && !used[enum.Name] {
+			continue
+		}
+		model.Enums = append(model.Enums, enum)
+	}
+
+	return model, rows.Err()
+}
+
+// SchemaLayers раскладывает таблицы по слоям для диаграмм: слой таблицы — длина
+// самой длинной цепочки внешних ключей от неё, таблицы без ссылок попадают в слой 0.
+// Внутри слоя таблицы упорядочены по среднему положению связанных таблиц (меньше пересечений).
+func SchemaLayers(model *SchemaModel) [][]string {
+	level := make(map[string]int, len(model.Tables))
+	for range model.Tables {
+		changed := false
+		for _, rel := range model.Relations {
+			if rel.FromTable != rel.ToTable && level[rel.FromTable] < level[rel.ToTable]+1 {
+				level[rel.FromTable] = level[rel.ToTable] + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	var layers [][]string
+	for _, t := range model.Tables {
+		l := level[t.Name]
+		for len(layers) <= l {
+			layers = append(layers, nil)
+		}
+		layers[l] = append(layers[l], t.Name)
+	}
+
+	rank := make(map[string]float64)
+	for li, layer := range layers {
+		if li > 0 {
+			weight := make(map[string]float64, len(layer))
+			for _, name := range layer {
+				var sum, count float64
+				for _, rel := range model.Relations {
+					if r, ok := rank[rel.ToTable]; ok && rel.FromTable == name {
+						sum += r
+						count++
+					}
+				}
+				if count > 0 {
+					weight[name] = sum / count
+				}
+			}
+			sort.SliceStable(layer, func(i, j int) bool {
+				return weight[layer[i]] < weight[layer[j]]
+			})
+		}
+		for i, name := range layer {
+			rank[name] = float64(i)
+		}
+	}
+	return layers
+}
@@ -0,0 +1,486 @@
+package internal
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Форматы экспорта схемы
+const (
+	ExportDOT      = "Graphviz DOT"
+	ExportMermaid  = "Mermaid erDiagram"
+	ExportPlantUML = "PlantUML"
+	ExportSVG      = "SVG"
+)
+
+// SchemaExportFormats форматы экспорта в порядке отображения
+var SchemaExportFormats = []string{ExportDOT, ExportMermaid, ExportPlantUML, ExportSVG}
+
+// SchemaExportExtension возвращает расширение файла для формата экспорта
+func SchemaExportExtension(format string) string {
+	switch format {
+	case ExportDOT:
+		return ".dot"
+	case ExportMermaid:
+		return ".mmd"
+	case ExportPlantUML:
+		return ".puml"
+	case ExportSVG:
+		return ".svg"
+	}
+	return ".txt"
+}
+
+// ExportSchema формирует текст диаграммы схемы в указанном формате
+func ExportSchema(model *SchemaModel, format string) (string, error) {
+	switch format {
+	case ExportDOT:
+		return ExportSchemaDOT(model), nil
+	case ExportMermaid:
+		return ExportSchemaMermaid(model), nil
+	case ExportPlantUML:
+		return ExportSchemaPlantUML(model), nil
+	case ExportSVG:
+		return ExportSchemaSVG(model), nil
+	}
+	return "", fmt.Errorf("неизвестный формат экспорта: %s", format)
+}
+
+// columnKeys возвращает отметки ключей столбца: PK, FK, UK
+func columnKeys(col SchemaColumn) []string {
+	var keys []string
+	if col.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if col.ForeignKey {
+		keys = append(keys, "FK")
+	}
+	if col.Unique {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+// ExportSchemaDOT формирует описание схемы на языке Graphviz DOT
+func ExportSchemaDOT(model *SchemaModel) string {
+	var sb strings.Builder
+	sb.WriteString("digraph schema {\n")
+	sb.WriteString("  rankdir=RL;\n")
+	sb.WriteString("  node [shape=plain, fontname=\"Helvetica\", fontsize=10];\n")
+	sb.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n\n")
+
+	for _, t := range model.Tables {
+		fmt.Fprintf(&sb, "  %q [label=<\n", t.Name)
+		sb.WriteString("    <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
+		fmt.Fprintf(&sb, "      <tr><td colspan=\"3\" bgcolor=\"#dcd0ff\"><b>%s</b></td></tr>\n", html.EscapeString(t.Name))
+		for _, col := range t.Columns {
+			name := html.EscapeString(col.Name)
+			if col.NotNull {
+				name = "<b>" + name + "</b>"
+			}
+			fmt.Fprintf(&sb, "      <tr><td port=%q align=\"left\">%s</td><td align=\"left\">%s</td><td>%s</td></tr>\n",
+				col.Name, name, html.EscapeString(col.Type), strings.Join(columnKeys(col), ","))
+		}
+		sb.WriteString("    </table>\n  >];\n")
+	}
+
+	for _, e := range model.Enums {
+		fmt.Fprintf(&sb, "  %q [label=<\n", e.Name)
+		sb.WriteString("    <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
+		fmt.Fprintf(&sb, "      <tr><td bgcolor=\"#c8f0ec\"><i>«enum»</i> <b>%s</b></td></tr>\n", html.EscapeString(e.Name))
+		for _, v := range e.Values {
+			fmt.Fprintf(&sb, "      <tr><td align=\"left\">%s</td></tr>\n", html.EscapeString(v))
+		}
+		sb.WriteString("    </table>\n  >];\n")
+	}
+
+	if len(model.Relations) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, rel := range model.Relations {
+		arrowTail := "crow"
+		if rel.Unique {
+			arrowTail = "tee"
+		}
+		arrowHead := "tee"
+		if rel.Nullable {
+			arrowHead = "teeodot"
+		}
+		fmt.Fprintf(&sb, "  %q:%q -> %q:%q [dir=both, arrowtail=%s, arrowhead=%s, label=%q];\n",
+			rel.FromTable, rel.FromColumns[0], rel.ToTable, rel.ToColumns[0], arrowTail, arrowHead, rel.Name)
+	}
+
+	// Связи столбцов с ENUM типами
+	for _, t := range model.Tables {
+		for _, col := range t.Columns {
+			for _, e := range model.Enums {
+				if strings.TrimSuffix(col.Type, "[]") == e.Name {
+					fmt.Fprintf(&sb, "  %q:%q -> %q [style=dashed, arrowhead=empty];\n", t.Name, col.Name, e.Name)
+				}
+			}
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// diagramAliasPattern недопустимые символы в идентификаторах сущностей Mermaid и PlantUML
+var diagramAliasPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// diagramAliases сопоставляет таблицам и ENUM типам уникальные идентификаторы из латиницы,
+// цифр и _, начинающиеся с буквы; исходные имена выводятся отдельно в кавычках
+func diagramAliases(model *SchemaModel) map[string]string {
+	var names []string
+	for _, t := range model.Tables {
+		names = append(names, t.Name)
+	}
+	for _, e := range model.Enums {
+		names = append(names, e.Name)
+	}
+
+	aliases := make(map[string]string)
+	used := make(map[string]bool)
+	for _, name := range names {
+		if _, ok := aliases[name]; ok {
+			continue
+		}
+		alias := strings.Trim(diagramAliasPattern.ReplaceAllString(name, "_"), "_")
+		if alias == "" || !unicode.IsLetter(rune(alias[0])) {
+			alias = "e_" + alias
+		}
+		unique := alias
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s_%d", alias, i)
+		}
+		used[unique] = true
+		aliases[name] = unique
+	}
+	return aliases
+}
+
+// diagramQuote заключает имя в двойные кавычки; кавычки внутри экранировать нельзя, они заменяются
+func diagramQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
+
+// mermaidTokenPattern недопустимые символы в именах и типах Mermaid
+var mermaidTokenPattern = regexp.MustCompile(`[^A-Za-z0-9_\-\[\]()]+`)
+
+// mermaidToken приводит строку к допустимому токену Mermaid: имена атрибутов и типы начинаются с буквы или _
+func mermaidToken(value string) string {
+	token := strings.Trim(mermaidTokenPattern.ReplaceAllString(value, "_"), "_")
+	if token == "" || !unicode.IsLetter(rune(token[0])) {
+		return "_" + token
+	}
+	return token
+}
+
+// mermaidEntity формирует заголовок сущности: исходное имя выводится как псевдоним, если отличается
+func mermaidEntity(alias, name string) string {
+	if alias == name {
+		return alias
+	}
+	return alias + "[" + diagramQuote(name) + "]"
+}
+
+// ExportSchemaMermaid формирует диаграмму Mermaid erDiagram
+func ExportSchemaMermaid(model *SchemaModel) string {
+	aliases := diagramAliases(model)
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+
+	for _, t := range model.Tables {
+		fmt.Fprintf(&sb, "    %s {\n", mermaidEntity(aliases[t.Name], t.Name))
+		for _, col := range t.Columns {
+			name := mermaidToken(col.Name)
+			fmt.Fprintf(&sb, "        %s %s", mermaidToken(col.Type), name)
+			if keys := columnKeys(col); len(keys) > 0 {
+				sb.WriteString(" " + strings.Join(keys, ", "))
+			}
+			// Комментарий атрибута — единственное место для исходного имени
+			var comment []string
+			if name != col.Name {
+				comment = append(comment, col.Name)
+			}
+			if col.NotNull && !col.PrimaryKey {
+				comment = append(comment, "NOT NULL")
+			}
+			if len(comment) > 0 {
+				sb.WriteString(" " + diagramQuote(strings.Join(comment, ", ")))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("    }\n")
+	}
+
+	// В erDiagram нет перечислений — ENUM показываем сущностью со значениями
+	for _, e := range model.Enums {
+		fmt.Fprintf(&sb, "    %s {\n", mermaidEntity(aliases[e.Name], e.Name))
+		for _, v := range e.Values {
+			fmt.Fprintf(&sb, "        enum %s", mermaidToken(v))
+			if mermaidToken(v) != v {
+				sb.WriteString(" " + diagramQuote(v))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("    }\n")
+	}
+
+	for _, rel := range model.Relations {
+		left := "}o"
+		if rel.Unique {
+			left = "|o"
+		}
+		right := "||"
+		if rel.Nullable {
+			right = "o|"
+		}
+		fmt.Fprintf(&sb, "    %s %s--%s %s : %s\n",
+			aliases[rel.FromTable], left, right, aliases[rel.ToTable], diagramQuote(strings.Join(rel.FromColumns, ", ")))
+	}
+
+	for _, t := range model.Tables {
+		for _, col := range t.Columns {
+			for _, e := range model.Enums {
+				if strings.TrimSuffix(col.Type, "[]") == e.Name {
+					fmt.Fprintf(&sb, "    %s }o..|| %s : %s\n", aliases[t.Name], aliases[e.Name], diagramQuote(col.Name))
+				}
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// plantUMLEnumValuePattern значения ENUM, которые можно вывести без кавычек
+var plantUMLEnumValuePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ExportSchemaPlantUML формирует ER-диаграмму PlantUML
+func ExportSchemaPlantUML(model *SchemaModel) string {
+	aliases := diagramAliases(model)
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	sb.WriteString("hide circle\n")
+	sb.WriteString("skinparam linetype ortho\n\n")
+
+	for _, t := range model.Tables {
+		fmt.Fprintf(&sb, "entity %s as %s {\n", diagramQuote(t.Name), aliases[t.Name])
+		var keyColumns, otherColumns []SchemaColumn
+		for _, col := range t.Columns {
+			if col.PrimaryKey {
+				keyColumns = append(keyColumns, col)
+			} else {
+				otherColumns = append(otherColumns, col)
+			}
+		}
+		writeColumn := func(col SchemaColumn) {
+			prefix := "  "
+			if col.NotNull {
+				prefix = "  *"
+			}
+			fmt.Fprintf(&sb, "%s%s : %s", prefix, col.Name, col.Type)
+			for _, key := range columnKeys(col) {
+				fmt.Fprintf(&sb, " <<%s>>", key)
+			}
+			sb.WriteString("\n")
+		}
+		for _, col := range keyColumns {
+			writeColumn(col)
+		}
+		if len(keyColumns) > 0 {
+			sb.WriteString("  --\n")
+		}
+		for _, col := range otherColumns {
+			writeColumn(col)
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	for _, e := range model.Enums {
+		fmt.Fprintf(&sb, "enum %s as %s {\n", diagramQuote(e.Name), aliases[e.Name])
+		for _, v := range e.Values {
+			if !plantUMLEnumValuePattern.MatchString(v) {
+				v = diagramQuote(v)
+			}
+			fmt.Fprintf(&sb, "  %s\n", v)
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	for _, rel := range model.Relations {
+		left := "}o"
+		if rel.Unique {
+			left = "|o"
+		}
+		right := "||"
+		if rel.Nullable {
+			right = "o|"
+		}
+		fmt.Fprintf(&sb, "%s %s--%s %s : %s\n", aliases[rel.FromTable], left, right, aliases[rel.ToTable], strings.Join(rel.FromColumns, ", "))
+	}
+
+	for _, t := range model.Tables {
+		for _, col := range t.Columns {
+			for _, e := range model.Enums {
+				if strings.TrimSuffix(col.Type, "[]") == e.Name {
+					fmt.Fprintf(&sb, "%s ..> %s : %s\n", aliases[t.Name], aliases[e.Name], col.Name)
+				}
+			}
+		}
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+// Размеры элементов SVG
+const (
+	svgCharWidth    = 7.2
+	svgHeaderHeight = 26
+	svgRowHeight    = 18
+	svgPadding      = 8
+	svgLayerGap     = 110
+	svgBoxGap       = 36
+)
+
+// svgBox положение блока на SVG
+type svgBox struct {
+	x, y, w, h float64
+}
+
+// ExportSchemaSVG рисует схему в SVG с той же послойной раскладкой, что и ER-диаграмма
+func ExportSchemaSVG(model *SchemaModel) string {
+	label := func(col SchemaColumn) string {
+		text := col.Name + ": " + col.Type
+		if keys := columnKeys(col); len(keys) > 0 {
+			text = strings.Join(keys, ",") + " " + text
+		}
+		return text
+	}
+	width := func(title string, lines []string) float64 {
+		longest := len([]rune(title))
+		for _, l := range lines {
+			if n := len([]rune(l)); n > longest {
+				longest = n
+			}
+		}
+		return float64(longest)*svgCharWidth + 2*svgPadding
+	}
+
+	boxes := make(map[string]*svgBox)
+	lines := make(map[string][]string)
+	for _, t := range model.Tables {
+		for _, col := range t.Columns {
+			lines[t.Name] = append(lines[t.Name], label(col))
+		}
+		boxes[t.Name] = &svgBox{
+			w: width(t.Name, lines[t.Name]),
+			h: svgHeaderHeight + float64(len(t.Columns))*svgRowHeight + svgPadding/2,
+		}
+	}
+
+	x, totalW, totalH := 20.0, 0.0, 0.0
+	for _, layer := range SchemaLayers(model) {
+		y, maxW := 20.0, 0.0
+		for _, name := range layer {
+			b := boxes[name]
+			b.x, b.y = x, y
+			y += b.h + svgBoxGap
+			if b.w > maxW {
+				maxW = b.w
+			}
+		}
+		if y > totalH {
+			totalH = y
+		}
+		x += maxW + svgLayerGap
+	}
+	totalW = x
+
+	// ENUM типы — отдельной колонкой справа
+	enumBoxes := make(map[string]*svgBox)
+	y, maxW := 20.0, 0.0
+	for _, e := range model.Enums {
+		b := &svgBox{x: x, y: y, w: width("«enum» "+e.Name, e.Values),
+			h: svgHeaderHeight + float64(len(e.Values))*svgRowHeight + svgPadding/2}
+		enumBoxes[e.Name] = b
+		y += b.h + svgBoxGap
+		if b.w > maxW {
+			maxW = b.w
+		}
+	}
+	if len(model.Enums) > 0 {
+		totalW = x + maxW + 20
+		if y > totalH {
+			totalH = y
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"12\">\n", totalW, totalH)
+	sb.WriteString("  <rect width=\"100%\" height=\"100%\" fill=\"#ffffff\"/>\n")
+
+	columnY := func(table string, columns []string) float64 {
+		b := boxes[table]
+		if t := model.Table(table); t != nil && len(columns) > 0 {
+			for i, col := range t.Columns {
+				if col.Name == columns[0] {
+					return b.y + svgHeaderHeight + (float64(i)+0.5)*svgRowHeight
+				}
+			}
+		}
+		return b.y + svgHeaderHeight/2
+	}
+
+	// Связи
+	for _, rel := range model.Relations {
+		src, dst := boxes[rel.FromTable], boxes[rel.ToTable]
+		fromY, toY := columnY(rel.FromTable, rel.FromColumns), columnY(rel.ToTable, rel.ToColumns)
+		var path string
+		if rel.FromTable == rel.ToTable {
+			right := src.x + src.w
+			path = fmt.Sprintf("M %.1f %.1f H %.1f V %.1f H %.1f", right, fromY, right+24, toY, right)
+		} else {
+			fromX, toX := src.x, dst.x+dst.w
+			if src.x+src.w/2 < dst.x+dst.w/2 {
+				fromX, toX = src.x+src.w, dst.x
+			}
+			midX := (fromX + toX) / 2
+			path = fmt.Sprintf("M %.1f %.1f H %.1f V %.1f H %.1f", fromX, fromY, midX, toY, toX)
+		}
+		dash := ""
+		if rel.Nullable {
+			dash = " stroke-dasharray=\"6 3\""
+		}
+		fmt.Fprintf(&sb, "  <path d=\"%s\" fill=\"none\" stroke=\"#555555\" stroke-width=\"1.5\"%s><title>%s</title></path>\n",
+			path, dash, html.EscapeString(rel.Name))
+	}
+
+	writeBox := func(b *svgBox, title, headerColor string, rows []string) {
+		fmt.Fprintf(&sb, "  <g>\n    <rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" rx=\"4\" fill=\"#fafafa\" stroke=\"#6a4fb3\" stroke-width=\"1.5\"/>\n",
+			b.x, b.y, b.w, b.h)
+		fmt.Fprintf(&sb, "    <rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%d\" rx=\"4\" fill=\"%s\"/>\n",
+			b.x, b.y, b.w, svgHeaderHeight, headerColor)
+		fmt.Fprintf(&sb, "    <text x=\"%.1f\" y=\"%.1f\" font-weight=\"bold\">%s</text>\n",
+			b.x+svgPadding, b.y+17, html.EscapeString(title))
+		for i, row := range rows {
+			fmt.Fprintf(&sb, "    <text x=\"%.1f\" y=\"%.1f\">%s</text>\n",
+				b.x+svgPadding, b.y+svgHeaderHeight+float64(i)*svgRowHeight+13, html.EscapeString(row))
+		}
+		sb.WriteString("  </g>\n")
+	}
+
+	for _, t := range model.Tables {
+		writeBox(boxes[t.Name], t.Name, "#dcd0ff", lines[t.Name])
+	}
+	for _, e := range model.Enums {
+		writeBox(enumBoxes[e.Name], "«enum» "+e.Name, "#c8f0ec", e.Values)
+	}
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportSchemaMermaid(t *testing.T) {
+	tests := []struct {
+		name  string
+		model SchemaModel
+		want  []string
+	}{
+		{
+			name: "простые имена",
+			model: SchemaModel{
+				Tables: []SchemaTable{
+					{Name: "orders", Columns: []SchemaColumn{{Name: "id", Type: "integer", NotNull: true, PrimaryKey: true}}},
+				},
+			},
+			want: []string{"    orders {\n", "        integer id PK\n"},
+		},
+		{
+			name: "имя с пробелом и кириллицей",
+			model: SchemaModel{
+				Tables: []SchemaTable{
+					{Name: "order items", Columns: []SchemaColumn{{Name: "Имя", Type: "character varying(50)", NotNull: true}}},
+					{Name: "Товары"},
+				},
+				Relations: []SchemaRelation{{FromTable: "order items", FromColumns: []string{"product id"}, ToTable: "Товары"}},
+			},
+			want: []string{
+				"    order_items[\"order items\"] {\n",
+				"        character_varying(50) _ \"Имя, NOT NULL\"\n",
+				"    e_[\"Товары\"] {\n",
+				"    order_items }o--|| e_ : \"product id\"\n",
+			},
+		},
+		{
+			name: "имя начинается с цифры",
+			model: SchemaModel{
+				Tables: []SchemaTable{{Name: "2024_sales"}},
+				Enums:  []SchemaEnum{{Name: "status", Values: []string{"in progress", "done"}}},
+			},
+			want: []string{
+				"    e_2024_sales[\"2024_sales\"] {\n",
+				"        enum in_progress \"in progress\"\n",
+				"        enum done\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExportSchemaMermaid(&tt.model)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("нет строки %q в\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestExportSchemaPlantUML(t *testing.T) {
+	tests := []struct {
+		name  string
+		model SchemaModel
+		want  []string
+	}{
+		{
+			name: "простые имена",
+			model: SchemaModel{
+				Tables: []SchemaTable{{Name: "orders"}},
+				Enums:  []SchemaEnum{{Name: "status", Values: []string{"new", "done"}}},
+			},
+			want: []string{"entity \"orders\" as orders {\n", "enum \"status\" as status {\n  new\n  done\n}\n"},
+		},
+		{
+			name: "специальные символы",
+			model: SchemaModel{
+				Tables: []SchemaTable{
+					{Name: "order items", Columns: []SchemaColumn{{Name: "status", Type: "order status"}}},
+					{Name: "order-items"},
+				},
+				Enums:     []SchemaEnum{{Name: "order status", Values: []string{"in progress", "+1"}}},
+				Relations: []SchemaRelation{{FromTable: "order-items", FromColumns: []string{"id"}, ToTable: "order items"}},
+			},
+			want: []string{
+				"entity \"order items\" as order_items {\n",
+				"entity \"order-items\" as order_items_2 {\n",
+				"enum \"order status\" as order_status {\n  \"in progress\"\n  \"+1\"\n}\n",
+				"order_items_2 }o--|| order_items : id\n",
+				"order_items ..> order_status : status\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExportSchemaPlantUML(&tt.model)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("нет строки %q в\n%s", want, got)
+				}
+			}
+		})
+	}
+}
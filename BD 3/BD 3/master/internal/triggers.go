@@ -0,0 +1,286 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TriggerDefinition описывает создаваемый триггер
+type TriggerDefinition struct {
+	Name     string
+	Table    string
+	Timing   string   // BEFORE, AFTER, INSTEAD OF
+	Events   []string // INSERT, UPDATE, UPDATE OF col1, col2, DELETE, TRUNCATE
+	ForEach  string   // ROW, STATEMENT
+	When     string   // необязательное условие WHEN (без скобок)
+	Function string   // триггерная функция без скобок
+}
+
+// TriggerInfo информация о существующем триггере
+type TriggerInfo struct {
+	Name       string
+	Table      string
+	Function   string
+	Enabled    bool
+	Definition string // результат pg_get_triggerdef
+}
+
+// validateTriggerEvent проверяет событие триггера (в т.ч. UPDATE OF col1, col2)
+func validateTriggerEvent(event string) error {
+	upper := strings.ToUpper(strings.TrimSpace(event))
+	switch upper {
+	case "INSERT", "UPDATE", "DELETE", "TRUNCATE":
+		return nil
+	}
+	if strings.HasPrefix(upper, "UPDATE OF ") {
+		for _, col := range strings.Split(strings.TrimSpace(event)[len("UPDATE OF "):], ",") {
+			if err := validateSQLIdent(strings.TrimSpace(col)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("недопустимое событие триггера: %s", event)
+}
+
+// CreateTriggerFunction создаёт (или заменяет) триггерную функцию на PL/pgSQL
+// body — тело функции между BEGIN и END, например: "NEW.updated_at := NOW(); RETURN NEW;"
+func CreateTriggerFunction(ctx context.Context, pool *pgxpool.Pool, functionName, body string) error {
+	if err := validateSQLIdent(functionName); err != nil {
+		return err
+	}
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("тело функции не может быть пустым")
+	}
+	if strings.Contains(body, "$trg$") {
+		return fmt.Errorf("тело функции не должно содержать разделитель $trg$")
+	}
+
+	query := fmt.Sprintf(
+		"CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $trg$\nBEGIN\n%s\nEND;\n$trg$",
+		functionName, body,
+	)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Создание триггерной функции: %v", err)
+		return fmt.Errorf("ошибка создания функции %s: %w", functionName, err)
+	}
+
+	fmt.Printf("Триггерная функция '%s' создана\n", functionName)
+	return nil
+}
+
+// CreateTrigger создаёт триггер по описанию
+func CreateTrigger(ctx context.Context, pool *pgxpool.Pool, def TriggerDefinition) error {
+	if err := validateSQLIdent(def.Name); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(def.Table); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(def.Function); err != nil {
+		return err
+	}
+
+	timing := strings.ToUpper(strings.TrimSpace(def.Timing))
+	if timing != "BEFORE" && timing != "AFTER" && timing != "INSTEAD OF" {
+		return fmt.Errorf("недопустимый момент срабатывания: %s", def.Timing)
+	}
+
+	forEach := strings.ToUpper(strings.TrimSpace(def.ForEach))
+	if forEach != "ROW" && forEach != "STATEMENT" {
+		return fmt.Errorf("недопустимый уровень триггера: %s", def.ForEach)
+	}
+
+	if len(def.Events) == 0 {
+		return fmt.Errorf("укажите хотя бы одно событие")
+	}
+	for _, event := range def.Events {
+		if err := validateTriggerEvent(event); err != nil {
+			return err
+		}
+		if strings.EqualFold(strings.TrimSpace(event), "TRUNCATE") && forEach == "ROW" {
+			return fmt.Errorf("TRUNCATE поддерживается только для FOR EACH STATEMENT")
+		}
+	}
+
+	if timing == "INSTEAD OF" {
+		if forEach != "ROW" {
+			return fmt.Errorf("INSTEAD OF триггеры должны быть FOR EACH ROW")
+		}
+		if strings.TrimSpace(def.When) != "" {
+			return fmt.Errorf("INSTEAD OF триггеры не поддерживают условие WHEN")
+		}
+	}
+
+	query := fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH %s",
+		def.Name, timing, strings.Join(def.Events, " OR "), def.Table, forEach)
+	if when := strings.TrimSpace(def.When); when != "" {
+		query += fmt.Sprintf(" WHEN (%s)", when)
+	}
+	query += fmt.Sprintf(" EXECUTE FUNCTION %s()", def.Function)
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Создание триггера: %v", err)
+		return fmt.Errorf("ошибка создания триггера %s: %w", def.Name, err)
+	}
+
+	fmt.Printf("Триггер '%s' на таблице '%s' создан\n", def.Name, def.Table)
+	return nil
+}
+
+// DropTrigger удаляет триггер с таблицы
+func DropTrigger(ctx context.Context, pool *pgxpool.Pool, table, triggerName string) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(triggerName); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", triggerName, table)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Удаление триггера: %v", err)
+		return fmt.Errorf("не удалось удалить триггер: %w", err)
+	}
+	return nil
+}
+
+// SetTriggerEnabled включает или отключает триггер
+func SetTriggerEnabled(ctx context.Context, pool *pgxpool.Pool, table, triggerName string, enabled bool) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(triggerName); err != nil {
+		return err
+	}
+
+	action := "DISABLE"
+	if enabled {
+		action = "ENABLE"
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s %s TRIGGER %s", table, action, triggerName)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Изменение состояния триггера: %v", err)
+		return fmt.Errorf("не удалось изменить состояние триггера: %w", err)
+	}
+	return nil
+}
+
+// ListTriggers возвращает пользовательские триггеры таблицы
+func ListTriggers(ctx context.Context, pool *pgxpool.Pool, table string) ([]TriggerInfo, error) {
+	if err := validateSQLIdent(table); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT
+		t.tgname,
+		c.relname,
+		p.proname,
+		t.tgenabled <> 'D',
+		pg_get_triggerdef(t.oid, true)
+	FROM pg_trigger t
+	JOIN pg_class c ON c.oid = t.tgrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_proc p ON p.oid = t.tgfoid
+	WHERE NOT t.tgisinternal
+	AND n.nspname = 'public'
+	AND c.relname = $1
+	ORDER BY t.tgname
+	`
+
+	rows, err := pool.Query(ctx, query, table)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения триггеров: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []TriggerInfo
+	for rows.Next() {
+		var info TriggerInfo
+		if err := rows.Scan(&info.Name, &info.Table, &info.Function, &info.Enabled, &info.Definition); err != nil {
+			return nil, fmt.Errorf("ошибка чтения триггера: %w", err)
+		}
+		triggers = append(triggers, info)
+	}
+	return triggers, rows.Err()
+}
+
+// ListTriggerFunctions возвращает функции схемы public, возвращающие trigger
+func ListTriggerFunctions(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	query := `
+	SELECT p.proname
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE n.nspname = 'public'
+	AND p.prorettype = 'trigger'::regtype
+	ORDER BY p.proname
+	`
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения триггерных функций: %w", err)
+	}
+	defer rows.Close()
+
+	var functions []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения функции: %w", err)
+		}
+		functions = append(functions, name)
+	}
+	return functions, rows.Err()
+}
+
+// GetTriggerFunctionSource возвращает исходный код триггерной функции
+func GetTriggerFunctionSource(ctx context.Context, pool *pgxpool.Pool, functionName string) (string, error) {
+	if err := validateSQLIdent(functionName); err != nil {
+		return "", err
+	}
+
+	query := `
+	SELECT pg_get_functiondef(p.oid)
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE n.nspname = 'public' AND p.proname = $1
+	AND p.prorettype = 'trigger'::regtype
+	`
+	var source string
+	if err := pool.QueryRow(ctx, query, functionName).Scan(&source); err != nil {
+		log.Printf("Получение исходного кода функции: %v", err)
+		return "", fmt.Errorf("не удалось получить исходный код функции %s: %w", functionName, err)
+	}
+	return source, nil
+}
+
+// CreateUpdatedAtTrigger создаёт функцию set_updated_at и BEFORE UPDATE триггер,
+// поддерживающий столбец column в актуальном состоянии
+func CreateUpdatedAtTrigger(ctx context.Context, pool *pgxpool.Pool, table, column string) error {
+	if err := validateSQLIdent(table); err != nil {
+		return err
+	}
+	if err := validateSQLIdent(column); err != nil {
+		return err
+	}
+
+	functionName := fmt.Sprintf("set_%s", column)
+	body := fmt.Sprintf("\tNEW.%s := NOW();\n\tRETURN NEW;", column)
+	if err := CreateTriggerFunction(ctx, pool, functionName, body); err != nil {
+		return err
+	}
+
+	return CreateTrigger(ctx, pool, TriggerDefinition{
+		Name:     fmt.Sprintf("trg_%s_%s", table, column),
+		Table:    table,
+		Timing:   "BEFORE",
+		Events:   []string{"UPDATE"},
+		ForEach:  "ROW",
+		Function: functionName,
+	})
+}
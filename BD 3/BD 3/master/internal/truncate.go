@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TruncateImpact таблица, затрагиваемая TRUNCATE
+type TruncateImpact struct {
+	Table      string
+	RowCount   int64
+	Selected   bool   // таблица выбрана пользователем
+	Referenced string // таблица, на которую ссылается внешний ключ (для связанных таблиц)
+	Level      int    // глубина по цепочке внешних ключей
+}
+
+// GetTruncateImpact возвращает выбранные таблицы и все таблицы, которые ссылаются
+// на них внешними ключами (рекурсивно), с количеством строк в каждой.
+// Связанные таблицы будут очищены при CASCADE; без CASCADE TRUNCATE завершится ошибкой,
+// если они не входят в список.
+func GetTruncateImpact(ctx context.Context, pool *pgxpool.Pool, tables []string) ([]TruncateImpact, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("не выбрано ни одной таблицы")
+	}
+	for _, table := range tables {
+		if err := validateSQLIdent(table); err != nil {
+			return nil, err
+		}
+	}
+
+	// Секции наследуют копии внешних ключей родителя, поэтому учитываются только сами таблицы
+	// (relispartition = false): строки секций входят в COUNT(*) по родителю.
+	// Имена выводятся через regclass — со схемой и кавычками, если они нужны
+	query := `
+	WITH RECURSIVE refs AS (
+		SELECT c.oid, ''::text AS referenced, 0 AS level
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = ANY($1)
+		UNION
+		SELECT ch.oid, refs.oid::regclass::text, refs.level + 1
+		FROM refs
+		JOIN pg_constraint con ON con.confrelid = refs.oid AND con.contype = 'f'
+		JOIN pg_class ch ON ch.oid = con.conrelid
+		WHERE refs.level < 20 AND NOT ch.relispartition
+	)
+	SELECT oid::regclass::text, referenced, level
+	FROM (
+		SELECT DISTINCT ON (oid) oid, referenced, level
+		FROM refs
+		ORDER BY oid, level
+	) impact
+	ORDER BY level, oid::regclass::text
+	`
+
+	rows, err := pool.Query(ctx, query, tables)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения связанных таблиц: %w", err)
+	}
+
+	var impact []TruncateImpact
+	for rows.Next() {
+		var item TruncateImpact
+		if err := rows.Scan(&item.Table, &item.Referenced, &item.Level); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("ошибка чтения связанной таблицы: %w", err)
+		}
+		item.Selected = item.Level == 0
+		impact = append(impact, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range impact {
+		// Имя получено из regclass и уже экранировано, validateSQLIdent здесь не нужен
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", impact[i].Table)
+		if err := pool.QueryRow(ctx, countQuery).Scan(&impact[i].RowCount); err != nil {
+			return nil, fmt.Errorf("ошибка подсчёта строк в %s: %w", impact[i].Table, err)
+		}
+	}
+
+	return impact, nil
+}
+
+// TruncateTables очищает таблицы одной командой TRUNCATE
+// restartIdentity — RESTART IDENTITY (сбросить последовательности), иначе CONTINUE IDENTITY
+// cascade — очистить также таблицы, ссылающиеся на указанные внешними ключами
+func TruncateTables(ctx context.Context, pool *pgxpool.Pool, tables []string, restartIdentity, cascade bool) error {
+	if len(tables) == 0 {
+		return fmt.Errorf("не выбрано ни одной таблицы")
+	}
+	for _, table := range tables {
+		if err := validateSQLIdent(table); err != nil {
+			return err
+		}
+	}
+
+	identity := "CONTINUE IDENTITY"
+	if restartIdentity {
+		identity = "RESTART IDENTITY"
+	}
+
+	query := fmt.Sprintf("TRUNCATE TABLE %s %s %s", strings.Join(tables, ", "), identity, dropBehavior(cascade))
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("TRUNCATE: %v", err)
+		return fmt.Errorf("ошибка очистки таблиц: %w", err)
+	}
+
+	fmt.Printf("%s выполнен\n", query)
+	return nil
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Виды использования пользовательского типа
+const (
+	TypeUsageTableColumn   = "column"
+	TypeUsageViewColumn    = "view_column"
+	TypeUsageMatViewColumn = "matview_column"
+	TypeUsageForeignColumn = "foreign_column"
+	TypeUsageCompositeType = "composite_field"
+	TypeUsageDomain        = "domain"
+	TypeUsageFunction      = "function"
+	TypeUsageRange         = "range"
+)
+
+// TypeUsage место, где используется тип (напрямую или как массив)
+type TypeUsage struct {
+	Kind   string // одна из констант TypeUsage*
+	Object string // таблица.столбец, тип.поле, имя домена или сигнатура функции
+	Detail string // полный тип столбца/поля или роль типа в функции
+}
+
+// GetTypeUsage возвращает все столбцы таблиц и представлений, поля составных типов,
+// домены, функции и диапазонные типы, ссылающиеся на тип typeName схемы public
+func GetTypeUsage(ctx context.Context, pool *pgxpool.Pool, typeName string) ([]TypeUsage, error) {
+	if err := validateSQLIdent(typeName); err != nil {
+		return nil, err
+	}
+
+	query := `
+	WITH target AS (
+		SELECT t.oid, t.typarray
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = 'public' AND t.typname = $1
+	)
+	SELECT
+		CASE c.relkind
+			WHEN 'v' THEN 'view_column'
+			WHEN 'm' THEN 'matview_column'
+			WHEN 'f' THEN 'foreign_column'
+			WHEN 'c' THEN 'composite_field'
+			ELSE 'column'
+		END,
+		CASE WHEN c.relkind = 'c'
+			THEN (SELECT ct.typname FROM pg_type ct WHERE ct.typrelid = c.oid)
+			ELSE c.relname
+		END || '.' || a.attname,
+		format_type(a.atttypid, a.atttypmod)
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN target ON a.atttypid IN (target.oid, target.typarray)
+	WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f', 'c')
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND a.attnum > 0 AND NOT a.attisdropped
+
+	UNION ALL
+
+	SELECT 'domain', d.typname, format_type(d.typbasetype, d.typtypmod)
+	FROM pg_type d, target
+	WHERE d.typtype = 'd' AND d.typbasetype IN (target.oid, target.typarray)
+
+	UNION ALL
+
+	SELECT 'function', p.oid::regprocedure::text,
+		concat_ws(', ',
+			CASE WHEN target.oid = ANY(p.proargtypes) OR target.typarray = ANY(p.proargtypes)
+				OR target.oid = ANY(COALESCE(p.proallargtypes, '{}'))
+				OR target.typarray = ANY(COALESCE(p.proallargtypes, '{}')) THEN 'argument' END,
+			CASE WHEN p.prorettype IN (target.oid, target.typarray) THEN 'result' END)
+	FROM pg_proc p, target
+	WHERE p.prorettype IN (target.oid, target.typarray)
+	OR target.oid = ANY(p.proargtypes) OR target.typarray = ANY(p.proargtypes)
+	OR target.oid = ANY(COALESCE(p.proallargtypes, '{}'))
+	OR target.typarray = ANY(COALESCE(p.proallargtypes, '{}'))
+
+	UNION ALL
+
+	SELECT 'range', format_type(r.rngtypid, NULL), 'subtype'
+	FROM pg_range r, target
+	WHERE r.rngsubtype = target.oid
+
+	ORDER BY 1, 2
+	`
+
+	rows, err := pool.Query(ctx, query, typeName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения использования типа: %w", err)
+	}
+	defer rows.Close()
+
+	var usages []TypeUsage
+	for rows.Next() {
+		var usage TypeUsage
+		if err := rows.Scan(&usage.Kind, &usage.Object, &usage.Detail); err != nil {
+			return nil, fmt.Errorf("ошибка чтения использования типа: %w", err)
+		}
+		usages = append(usages, usage)
+	}
+	return usages, rows.Err()
+}
+
+// EnumValueUsage количество строк с значением ENUM во всех столбцах таблиц
+type EnumValueUsage struct {
+	Value    string
+	Total    int64
+	ByColumn map[string]int64 // ключ — "таблица.столбец"
+}
+
+// GetEnumValueUsage возвращает для каждого значения ENUM (в порядке сортировки) число строк,
+// в которых оно встречается, по каждому столбцу таблиц и суммарно
+func GetEnumValueUsage(ctx context.Context, pool *pgxpool.Pool, enumTypeName string) ([]EnumColumn, []EnumValueUsage, error) {
+	values, err := GetEnumValues(ctx, pool, enumTypeName)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns, err := GetEnumColumns(ctx, pool, enumTypeName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usage := make([]EnumValueUsage, len(values))
+	for i, value := range values {
+		usage[i] = EnumValueUsage{Value: value, ByColumn: make(map[string]int64)}
+	}
+
+	for _, col := range columns {
+		counts, err := CountEnumValueRows(ctx, pool, col)
+		if err != nil {
+			return nil, nil, err
+		}
+		key := col.Table + "." + col.Column
+		for i := range usage {
+			count := counts[usage[i].Value]
+			usage[i].ByColumn[key] = count
+			usage[i].Total += count
+		}
+	}
+
+	return columns, usage, nil
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ VIEW options ============
+
+// WITH CHECK OPTION modes
+const (
+	CheckOptionNone     = ""
+	CheckOptionLocal    = "LOCAL"
+	CheckOptionCascaded = "CASCADED"
+)
+
+// ViewOptions are the optional clauses of CREATE VIEW
+type ViewOptions struct {
+	OrReplace       bool
+	Temporary       bool     // TEMPORARY: lives only in the session that created it, create it through a ViewSession
+	Recursive       bool     // RECURSIVE: requires Columns; the query refers to the view itself
+	Columns         []string // explicit column names, e.g. (id, title)
+	CheckOption     string   // CheckOptionNone, CheckOptionLocal or CheckOptionCascaded
+	SecurityBarrier bool
+	SecurityInvoker bool // PostgreSQL 15+
+}
+
+// BuildCreateViewSQL builds a CREATE VIEW statement with the given options
+func BuildCreateViewSQL(viewName, selectQuery string, opts ViewOptions) (string, error) {
+	if err := validateSQLIdent(viewName); err != nil {
+		return "", err
+	}
+	selectQuery = strings.TrimSuffix(strings.TrimSpace(selectQuery), ";")
+	if selectQuery == "" {
+		return "", fmt.Errorf("SELECT query cannot be empty")
+	}
+	for _, col := range opts.Columns {
+		if err := validateSQLIdent(col); err != nil {
+			return "", err
+		}
+	}
+	if opts.Recursive && len(opts.Columns) == 0 {
+		return "", fmt.Errorf("a recursive view requires explicit column names")
+	}
+
+	checkOption := strings.ToUpper(strings.TrimSpace(opts.CheckOption))
+	switch checkOption {
+	case CheckOptionNone, CheckOptionLocal, CheckOptionCascaded:
+	default:
+		return "", fmt.Errorf("unknown check option: %s", opts.CheckOption)
+	}
+	if checkOption != CheckOptionNone && opts.Recursive {
+		return "", fmt.Errorf("WITH CHECK OPTION is not supported on recursive views")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	if opts.OrReplace {
+		sb.WriteString("OR REPLACE ")
+	}
+	if opts.Temporary {
+		sb.WriteString("TEMPORARY ")
+	}
+	if opts.Recursive {
+		sb.WriteString("RECURSIVE ")
+	}
+	sb.WriteString("VIEW " + viewName)
+	if len(opts.Columns) > 0 {
+		sb.WriteString(" (" + strings.Join(opts.Columns, ", ") + ")")
+	}
+
+	var with []string
+	if opts.SecurityBarrier {
+		with = append(with, "security_barrier = true")
+	}
+	if opts.SecurityInvoker {
+		with = append(with, "security_invoker = true")
+	}
+	if len(with) > 0 {
+		sb.WriteString(" WITH (" + strings.Join(with, ", ") + ")")
+	}
+
+	sb.WriteString(" AS " + selectQuery)
+	if checkOption != CheckOptionNone {
+		sb.WriteString(fmt.Sprintf(" WITH %s CHECK OPTION", checkOption))
+	}
+	return sb.String(), nil
+}
+
+// CreateViewWithOptions creates a view with explicit columns, check option and security options
+// Example: CreateViewWithOptions(ctx, pool, "active_products", "SELECT * FROM products WHERE active",
+// ViewOptions{CheckOption: CheckOptionLocal, SecurityBarrier: true})
+func CreateViewWithOptions(ctx context.Context, pool *pgxpool.Pool, viewName, selectQuery string, opts ViewOptions) error {
+	// Each Exec may run on a different pooled connection, so a temporary view would be invisible to later queries
+	if opts.Temporary {
+		return fmt.Errorf("temporary views must be created through a ViewSession")
+	}
+	query, err := BuildCreateViewSQL(viewName, selectQuery, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		log.Printf("Error creating view: %v", err)
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+	fmt.Printf("VIEW '%s' created successfully!\n", viewName)
+	return nil
+}
+
+// ViewInfo describes a view together with its options and updatability
+type ViewInfo struct {
+	Name            string
+	Temporary       bool   // belongs to the session of a ViewSession
+	CheckOption     string // CheckOptionNone, CheckOptionLocal or CheckOptionCascaded
+	SecurityBarrier bool
+	SecurityInvoker bool
+	IsUpdatable     bool // information_schema.views.is_updatable: UPDATE/DELETE work without rules or triggers
+	IsInsertable    bool // information_schema.views.is_insertable_into
+}
+
+// GetViewsInfo returns views of the public schema with their options and whether PostgreSQL can update them automatically
+func GetViewsInfo(ctx context.Context, pool *pgxpool.Pool) ([]ViewInfo, error) {
+	return queryViewsInfo(ctx, pool, "v.table_schema = 'public'")
+}
+
+// viewQuerier is implemented by both *pgxpool.Pool and the dedicated connection of a ViewSession
+type viewQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// queryViewsInfo lists views matching the condition on information_schema.views v / pg_namespace n
+func queryViewsInfo(ctx context.Context, db viewQuerier, condition string) ([]ViewInfo, error) {
+	query := `
+		SELECT v.table_name,
+			c.relpersistence = 't',
+			CASE v.check_option WHEN 'NONE' THEN '' ELSE v.check_option END,
+			COALESCE('security_barrier=true' = ANY(c.reloptions) OR 'security_barrier=on' = ANY(c.reloptions), false),
+			COALESCE('security_invoker=true' = ANY(c.reloptions) OR 'security_invoker=on' = ANY(c.reloptions), false),
+			v.is_updatable = 'YES',
+			v.is_insertable_into = 'YES'
+		FROM information_schema.views v
+		JOIN pg_namespace n ON n.nspname = v.table_schema
+		JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = v.table_name
+		WHERE ` + condition + `
+		ORDER BY v.table_name
+	`
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []ViewInfo
+	for rows.Next() {
+		var v ViewInfo
+		if err := rows.Scan(&v.Name, &v.Temporary, &v.CheckOption, &v.SecurityBarrier, &v.SecurityInvoker,
+			&v.IsUpdatable, &v.IsInsertable); err != nil {
+			return nil, fmt.Errorf("failed to read view: %w", err)
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
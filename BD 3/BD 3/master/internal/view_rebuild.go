@@ -0,0 +1,406 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ Dependency-preserving VIEW editing ============
+
+// ViewSnapshot is everything needed to recreate a view or materialized view after it was dropped
+type ViewSnapshot struct {
+	Name       string
+	Kind       string   // DependencyView or DependencyMaterializedView
+	Level      int      // dependency depth from the edited view (0 = the edited view itself)
+	Definition string   // SELECT part as returned by pg_get_viewdef
+	Options    []string // reloptions, e.g. security_barrier=true or check_option=local
+	Indexes    []string // CREATE INDEX statements (materialized views only)
+	Comment    string
+	Grants     []string // GRANT statements restoring explicit privileges
+	Populated  bool     // false for materialized views created WITH NO DATA
+	Triggers   []string // CREATE TRIGGER statements (INSTEAD OF triggers on views)
+	Owner      string   // quoted owner role if it differs from the current user, otherwise empty
+	Columns    []ViewColumnStatement
+}
+
+// ViewColumnStatement is a column comment or column-level GRANT of a view
+type ViewColumnStatement struct {
+	Column    string
+	Statement string
+}
+
+// captureViewSnapshot reads the definition, options, indexes, comment and grants of a view
+func captureViewSnapshot(ctx context.Context, tx pgx.Tx, oid uint32, level int) (*ViewSnapshot, error) {
+	snapshot := &ViewSnapshot{Level: level}
+
+	var relkind string
+	query := `
+	SELECT c.relname, c.relkind::text, pg_get_viewdef(c.oid), COALESCE(c.reloptions, '{}'),
+		COALESCE(obj_description(c.oid, 'pg_class'), ''), c.relispopulated
+	FROM pg_class c WHERE c.oid = $1
+	`
+	if err := tx.QueryRow(ctx, query, oid).Scan(&snapshot.Name, &relkind, &snapshot.Definition,
+		&snapshot.Options, &snapshot.Comment, &snapshot.Populated); err != nil {
+		return nil, fmt.Errorf("failed to read view definition: %w", err)
+	}
+	snapshot.Definition = strings.TrimSuffix(strings.TrimSpace(snapshot.Definition), ";")
+	snapshot.Kind = DependencyView
+	if relkind == "m" {
+		snapshot.Kind = DependencyMaterializedView
+	}
+
+	indexRows, err := tx.Query(ctx, "SELECT pg_get_indexdef(indexrelid) FROM pg_index WHERE indrelid = $1 ORDER BY indexrelid", oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes of %s: %w", snapshot.Name, err)
+	}
+	for indexRows.Next() {
+		var def string
+		if err := indexRows.Scan(&def); err != nil {
+			indexRows.Close()
+			return nil, err
+		}
+		snapshot.Indexes = append(snapshot.Indexes, def)
+	}
+	indexRows.Close()
+
+	grantQuery := `
+	SELECT a.privilege_type, CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE quote_ident(r.rolname) END, a.is_grantable
+	FROM pg_class c, aclexplode(c.relacl) a
+	LEFT JOIN pg_roles r ON r.oid = a.grantee
+	WHERE c.oid = $1 AND a.grantee <> c.relowner
+	`
+	grantRows, err := tx.Query(ctx, grantQuery, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read privileges of %s: %w", snapshot.Name, err)
+	}
+	for grantRows.Next() {
+		var privilege, grantee string
+		var grantable bool
+		if err := grantRows.Scan(&privilege, &grantee, &grantable); err != nil {
+			grantRows.Close()
+			return nil, err
+		}
+		grant := fmt.Sprintf("GRANT %s ON %s TO %s", privilege, snapshot.Name, grantee)
+		if grantable {
+			grant += " WITH GRANT OPTION"
+		}
+		snapshot.Grants = append(snapshot.Grants, grant)
+	}
+	grantRows.Close()
+
+	triggerRows, err := tx.Query(ctx, "SELECT pg_get_triggerdef(oid) FROM pg_trigger WHERE tgrelid = $1 AND NOT tgisinternal ORDER BY tgname", oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read triggers of %s: %w", snapshot.Name, err)
+	}
+	for triggerRows.Next() {
+		var def string
+		if err := triggerRows.Scan(&def); err != nil {
+			triggerRows.Close()
+			return nil, err
+		}
+		snapshot.Triggers = append(snapshot.Triggers, def)
+	}
+	triggerRows.Close()
+
+	ownerQuery := `
+	SELECT CASE WHEN c.relowner = (SELECT oid FROM pg_roles WHERE rolname = current_user) THEN ''
+		ELSE quote_ident(pg_get_userbyid(c.relowner)) END
+	FROM pg_class c WHERE c.oid = $1
+	`
+	if err := tx.QueryRow(ctx, ownerQuery, oid).Scan(&snapshot.Owner); err != nil {
+		return nil, fmt.Errorf("failed to read owner of %s: %w", snapshot.Name, err)
+	}
+
+	columnQuery := `
+	SELECT quote_ident(a.attname), 'COMMENT ON COLUMN ' || quote_ident(c.relname) || '.' || quote_ident(a.attname)
+		|| ' IS ' || quote_literal(col_description(c.oid, a.attnum))
+	FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid
+	WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped AND col_description(c.oid, a.attnum) IS NOT NULL
+	UNION ALL
+	SELECT quote_ident(a.attname), 'GRANT ' || p.privilege_type || ' (' || quote_ident(a.attname) || ') ON '
+		|| quote_ident(c.relname) || ' TO '
+		|| CASE WHEN p.grantee = 0 THEN 'PUBLIC' ELSE quote_ident(pg_get_userbyid(p.grantee)) END
+		|| CASE WHEN p.is_grantable THEN ' WITH GRANT OPTION' ELSE '' END
+	FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid, aclexplode(a.attacl) p
+	WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped AND p.grantee <> c.relowner
+	`
+	columnRows, err := tx.Query(ctx, columnQuery, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column comments and privileges of %s: %w", snapshot.Name, err)
+	}
+	for columnRows.Next() {
+		var col ViewColumnStatement
+		if err := columnRows.Scan(&col.Column, &col.Statement); err != nil {
+			columnRows.Close()
+			return nil, err
+		}
+		snapshot.Columns = append(snapshot.Columns, col)
+	}
+	columnRows.Close()
+
+	return snapshot, nil
+}
+
+// createStatement returns the CREATE statement for the snapshot
+func (s *ViewSnapshot) createStatement() string {
+	with := ""
+	if len(s.Options) > 0 {
+		with = fmt.Sprintf(" WITH (%s)", strings.Join(s.Options, ", "))
+	}
+	if s.Kind == DependencyMaterializedView {
+		data := ""
+		if !s.Populated {
+			data = " WITH NO DATA"
+		}
+		return fmt.Sprintf("CREATE MATERIALIZED VIEW %s%s AS %s%s", s.Name, with, s.Definition, data)
+	}
+	return fmt.Sprintf("CREATE VIEW %s%s AS %s", s.Name, with, s.Definition)
+}
+
+// dropStatement returns the DROP statement for the snapshot (RESTRICT: dependents are dropped explicitly)
+func (s *ViewSnapshot) dropStatement() string {
+	if s.Kind == DependencyMaterializedView {
+		return fmt.Sprintf("DROP MATERIALIZED VIEW %s RESTRICT", s.Name)
+	}
+	return fmt.Sprintf("DROP VIEW %s RESTRICT", s.Name)
+}
+
+// restoreStatements returns statements recreating indexes, comments, triggers, grants and the owner of
+// the snapshot. Column comments and grants are restored only for columns in existingColumns
+// (nil means all columns, which holds for dependents whose definition is unchanged)
+func (s *ViewSnapshot) restoreStatements(existingColumns map[string]bool) []string {
+	keyword := "VIEW"
+	if s.Kind == DependencyMaterializedView {
+		keyword = "MATERIALIZED VIEW"
+	}
+
+	statements := append([]string(nil), s.Indexes...)
+	if s.Comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON %s %s IS %s", keyword, s.Name, quoteLiteral(s.Comment)))
+	}
+	statements = append(statements, s.Triggers...)
+	statements = append(statements, s.Grants...)
+	for _, col := range s.Columns {
+		if existingColumns == nil || existingColumns[col.Column] {
+			statements = append(statements, col.Statement)
+		} else {
+			log.Printf("Column %s.%s no longer exists, skipping: %s", s.Name, col.Column, col.Statement)
+		}
+	}
+	// Last: the current user may lose the right to run the statements above once ownership moves
+	if s.Owner != "" {
+		statements = append(statements, fmt.Sprintf("ALTER %s %s OWNER TO %s", keyword, s.Name, s.Owner))
+	}
+	return statements
+}
+
+// relationColumns returns the quoted column names of a relation
+func relationColumns(ctx context.Context, tx pgx.Tx, name string) (map[string]bool, error) {
+	query := `
+	SELECT quote_ident(a.attname) FROM pg_attribute a
+	WHERE a.attrelid = ('public.' || quote_ident($1))::regclass AND a.attnum > 0 AND NOT a.attisdropped
+	`
+	rows, err := tx.Query(ctx, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+		columns[col] = true
+	}
+	return columns, rows.Err()
+}
+
+// captureViewChain returns snapshots of the view and of every view/MV that depends on it (transitively),
+// ordered so that each view comes after everything it depends on
+func captureViewChain(ctx context.Context, tx pgx.Tx, viewName string) ([]*ViewSnapshot, error) {
+	query := `
+	WITH RECURSIVE deps AS (
+		SELECT c.oid, 0 AS level
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND c.relkind IN ('v', 'm')
+		UNION ALL
+		SELECT r.ev_class, deps.level + 1
+		FROM deps
+		JOIN pg_depend d ON d.refclassid = 'pg_class'::regclass AND d.refobjid = deps.oid
+			AND d.classid = 'pg_rewrite'::regclass
+		JOIN pg_rewrite r ON r.oid = d.objid
+		WHERE r.ev_class <> deps.oid AND deps.level < 50
+	)
+	SELECT oid, MAX(level) FROM deps GROUP BY oid ORDER BY MAX(level), oid
+	`
+
+	rows, err := tx.Query(ctx, query, viewName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependent views: %w", err)
+	}
+	type node struct {
+		oid   uint32
+		level int
+	}
+	var nodes []node
+	for rows.Next() {
+		var n node
+		if err := rows.Scan(&n.oid, &n.level); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("view '%s' not found", viewName)
+	}
+
+	var chain []*ViewSnapshot
+	for _, n := range nodes {
+		snapshot, err := captureViewSnapshot(ctx, tx, n.oid, n.level)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, snapshot)
+	}
+	return chain, nil
+}
+
+// GetDependentViews returns views and materialized views that depend on viewName (directly or
+// through other views), in the order they would be recreated
+func GetDependentViews(ctx context.Context, pool *pgxpool.Pool, viewName string) ([]ViewSnapshot, error) {
+	if err := validateSQLIdent(viewName); err != nil {
+		return nil, err
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	chain, err := captureViewChain(ctx, tx, viewName)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents []ViewSnapshot
+	for _, snapshot := range chain[1:] {
+		dependents = append(dependents, *snapshot)
+	}
+	return dependents, nil
+}
+
+// ViewReplaceResult describes how ReplaceViewPreservingDependents applied the new definition
+type ViewReplaceResult struct {
+	Rebuilt   bool     // false: CREATE OR REPLACE was enough
+	Recreated []string // dependent views/MVs that were dropped and recreated
+}
+
+// ReplaceViewPreservingDependents changes the SELECT of a view or materialized view without losing
+// the views built on top of it. For a view it first tries CREATE OR REPLACE VIEW; if the change is
+// incompatible (columns removed, renamed or retyped) or the object is a materialized view, it captures
+// the definitions of all dependent views/MVs, drops the chain, recreates the edited view and then every
+// dependent in dependency order, restoring options, MV indexes, INSTEAD OF triggers, table and column
+// comments, table and column grants and a non-default owner.
+// Everything runs in one transaction: if any dependent no longer compiles, nothing is changed.
+func ReplaceViewPreservingDependents(ctx context.Context, pool *pgxpool.Pool, viewName, selectQuery string) (*ViewReplaceResult, error) {
+	if err := validateSQLIdent(viewName); err != nil {
+		return nil, err
+	}
+	selectQuery = strings.TrimSuffix(strings.TrimSpace(selectQuery), ";")
+	if selectQuery == "" {
+		return nil, fmt.Errorf("SELECT query cannot be empty")
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	chain, err := captureViewChain(ctx, tx, viewName)
+	if err != nil {
+		return nil, err
+	}
+	target := chain[0]
+	result := &ViewReplaceResult{}
+
+	if target.Kind == DependencyView {
+		// Compatible changes (same columns, new ones appended) need no rebuild
+		with := ""
+		if len(target.Options) > 0 {
+			with = fmt.Sprintf(" WITH (%s)", strings.Join(target.Options, ", "))
+		}
+		if _, err := tx.Exec(ctx, "SAVEPOINT replace_view"); err != nil {
+			return nil, err
+		}
+		_, replaceErr := tx.Exec(ctx, fmt.Sprintf("CREATE OR REPLACE VIEW %s%s AS %s", viewName, with, selectQuery))
+		if replaceErr == nil {
+			if err := tx.Commit(ctx); err != nil {
+				return nil, fmt.Errorf("failed to commit: %w", err)
+			}
+			fmt.Printf("VIEW '%s' replaced in place\n", viewName)
+			return result, nil
+		}
+		if _, err := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT replace_view"); err != nil {
+			return nil, err
+		}
+		log.Printf("CREATE OR REPLACE VIEW %s is incompatible, rebuilding dependents: %v", viewName, replaceErr)
+	}
+
+	result.Rebuilt = true
+
+	// Drop from the top of the chain down to the edited view
+	for i := len(chain) - 1; i >= 0; i-- {
+		if _, err := tx.Exec(ctx, chain[i].dropStatement()); err != nil {
+			return nil, fmt.Errorf("failed to drop %s: %w", chain[i].Name, err)
+		}
+	}
+
+	target.Definition = selectQuery
+	for _, snapshot := range chain {
+		if _, err := tx.Exec(ctx, snapshot.createStatement()); err != nil {
+			log.Printf("Error recreating %s: %v", snapshot.Name, err)
+			if snapshot != target {
+				return nil, fmt.Errorf("dependent %s %s is incompatible with the new definition: %w",
+					strings.ToLower(snapshot.Kind), snapshot.Name, err)
+			}
+			return nil, fmt.Errorf("failed to create %s: %w", snapshot.Name, err)
+		}
+		var existingColumns map[string]bool
+		if snapshot == target {
+			// The new definition may have removed columns that had comments or grants
+			if existingColumns, err = relationColumns(ctx, tx, snapshot.Name); err != nil {
+				return nil, err
+			}
+		}
+		for _, statement := range snapshot.restoreStatements(existingColumns) {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return nil, fmt.Errorf("failed to restore %s (%s): %w", snapshot.Name, statement, err)
+			}
+		}
+		if snapshot != target {
+			result.Recreated = append(result.Recreated, snapshot.Name)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	fmt.Printf("VIEW '%s' rebuilt with %d dependent view(s)\n", viewName, len(result.Recreated))
+	return result, nil
+}
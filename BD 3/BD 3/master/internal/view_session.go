@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ Temporary VIEW session ============
+
+// ViewSession keeps one dedicated connection for temporary views. A TEMPORARY view exists only in
+// the session that created it, so creating, listing, previewing and dropping it must run on the same
+// connection rather than on whichever connection the pool hands out
+type ViewSession struct {
+	mu   sync.Mutex
+	pool *pgxpool.Pool
+	conn *pgxpool.Conn
+}
+
+// NewViewSession creates a session; the connection is acquired on first use
+func NewViewSession(pool *pgxpool.Pool) *ViewSession {
+	return &ViewSession{pool: pool}
+}
+
+// connection returns the session connection, acquiring it if needed. The caller holds s.mu
+func (s *ViewSession) connection(ctx context.Context) (*pgxpool.Conn, error) {
+	if s.conn != nil && s.conn.Conn().IsClosed() {
+		// The server closed the session, its temporary views are gone with it
+		s.conn.Release()
+		s.conn = nil
+	}
+	if s.conn == nil {
+		conn, err := s.pool.Acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire connection: %w", err)
+		}
+		s.conn = conn
+	}
+	return s.conn, nil
+}
+
+// CreateView creates a view (temporary or not) on the session connection
+// Example: session.CreateView(ctx, "recent_orders", "SELECT * FROM orders WHERE created_at > now() - interval '1 day'",
+// ViewOptions{Temporary: true})
+func (s *ViewSession) CreateView(ctx context.Context, viewName, selectQuery string, opts ViewOptions) error {
+	query, err := BuildCreateViewSQL(viewName, selectQuery, opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, err := s.connection(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, query); err != nil {
+		log.Printf("Error creating view: %v", err)
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+	fmt.Printf("VIEW '%s' created successfully!\n", viewName)
+	return nil
+}
+
+// TemporaryViews returns the temporary views of the session with their options
+func (s *ViewSession) TemporaryViews(ctx context.Context) ([]ViewInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil, nil
+	}
+	conn, err := s.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return queryViewsInfo(ctx, conn, "n.oid = pg_my_temp_schema()")
+}
+
+// PreviewView returns up to limit rows of a view of the session, header first
+func (s *ViewSession) PreviewView(ctx context.Context, viewName string, limit int) ([][]string, error) {
+	if err := validateSQLIdent(viewName); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, err := s.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.Query(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT $1", viewName), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query view %s: %w", viewName, err)
+	}
+	return rowsAsStrings(rows)
+}
+
+// DropView drops a view of the session
+func (s *ViewSession) DropView(ctx context.Context, viewName string) error {
+	if err := validateSQLIdent(viewName); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, err := s.connection(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, fmt.Sprintf("DROP VIEW IF EXISTS %s", viewName)); err != nil {
+		log.Printf("Error dropping view: %v", err)
+		return fmt.Errorf("failed to drop view: %w", err)
+	}
+	fmt.Printf("VIEW '%s' dropped successfully!\n", viewName)
+	return nil
+}
+
+// Close drops the temporary objects of the session and returns the connection to the pool,
+// so the next user of that pooled connection does not see them
+func (s *ViewSession) Close(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return
+	}
+	if _, err := s.conn.Exec(ctx, "DISCARD TEMP"); err != nil {
+		// The views could not be dropped, so close the connection instead of returning it to the pool
+		log.Printf("Error discarding temporary views: %v", err)
+		if err := s.conn.Hijack().Close(ctx); err != nil {
+			log.Printf("Error closing view session connection: %v", err)
+		}
+		s.conn = nil
+		return
+	}
+	s.conn.Release()
+	s.conn = nil
+}
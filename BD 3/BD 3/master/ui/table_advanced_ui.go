@@ -14,8 +14,22 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// computedMarker отмечает в заголовке вычисляемые и IDENTITY столбцы (только чтение)
+const computedMarker = " ⚙"
+
 // CreateAdvancedUI создаёт расширенное UI с доступом ко всем функциям
 func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Pool) {
+	// Открытие таблицы в основной сетке из других окон (назначается после создания tableSelect)
+	var openTableInGrid func(table string)
+
+	// Запуск сохранённых расписаний обновления материализованных представлений
+	getMVRefreshScheduler(ctx, pool)
+
+	// Соединение временных представлений нужно вернуть до закрытия пула, иначе pool.Close() будет ждать его
+	window.SetOnClosed(func() {
+		getViewSession(pool).Close(ctx)
+	})
+
 	// Создаем главное меню
 	mainMenu := fyne.NewMainMenu(
 
@@ -26,6 +40,16 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 			fyne.NewMenuItem("Переименовать таблицу", func() {
 				UIRenameTable(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("Очистить таблицы (TRUNCATE)", func() {
+				UITruncateTables(ctx, pool, window, nil, nil)
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Комментарий объекта", func() {
+				UIEditComment(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Все комментарии", func() {
+				UIListComments(ctx, pool, window)
+			}),
 		),
 		fyne.NewMenu("Столбцы",
 			fyne.NewMenuItem("Добавить столбец", func() {
@@ -40,6 +64,9 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 			fyne.NewMenuItem("Переименовать столбец", func() {
 				UIRenameColumn(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("Значения по умолчанию (DEFAULT)", func() {
+				UIColumnDefaults(ctx, pool, window)
+			}),
 		),
 		fyne.NewMenu("Ограничения",
 			fyne.NewMenuItem("Добавить CHECK", func() {
@@ -61,19 +88,129 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 				UIDropNotNull(ctx, pool, window)
 			}),
 		),
+		fyne.NewMenu("Схема",
+			fyne.NewMenuItem("ER-диаграмма", func() {
+				UIERDiagram(ctx, pool, window, func(table string) {
+					if openTableInGrid != nil {
+						openTableInGrid(table)
+					}
+				})
+			}),
+			fyne.NewMenuItem("Экспорт схемы (DOT, Mermaid, PlantUML, SVG)", func() {
+				UIExportSchema(ctx, pool, window)
+			}),
+		),
+		fyne.NewMenu("Обслуживание",
+			fyne.NewMenuItem("Панель обслуживания таблицы", func() {
+				UIMaintenancePanel(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Статистика всех таблиц", func() {
+				UIBloatOverview(ctx, pool, window)
+			}),
+		),
+		fyne.NewMenu("Партиционирование",
+			fyne.NewMenuItem("Создать партиционированную таблицу", func() {
+				UICreatePartitionedTable(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Создать партицию", func() {
+				UICreatePartition(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Подключить партицию (ATTACH)", func() {
+				UIAttachPartition(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Отключить партицию (DETACH)", func() {
+				UIDetachPartition(ctx, pool, window)
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Дерево партиций", func() {
+				UIPartitionTree(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Создать будущие месячные партиции", func() {
+				UICreateFuturePartitions(ctx, pool, window)
+			}),
+		),
+		fyne.NewMenu("Триггеры",
+			fyne.NewMenuItem("Создать триггерную функцию", func() {
+				UICreateTriggerFunction(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Создать триггер", func() {
+				UICreateTrigger(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Триггеры таблицы", func() {
+				UIListTriggers(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Исходный код функции", func() {
+				UITriggerFunctionSource(ctx, pool, window)
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Автообновление updated_at", func() {
+				UIUpdatedAtTrigger(ctx, pool, window)
+			}),
+		),
+		fyne.NewMenu("Функции",
+			fyne.NewMenuItem("Редактор функций и процедур", func() {
+				UIRoutineEditor(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Список функций и процедур", func() {
+				UIListRoutines(ctx, pool, window)
+			}),
+		),
+		fyne.NewMenu("Роли и права",
+			fyne.NewMenuItem("Список ролей", func() {
+				UIListRoles(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Создать роль", func() {
+				UICreateRole(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Изменить роль", func() {
+				UIAlterRole(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Удалить роль", func() {
+				UIDropRole(ctx, pool, window)
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("GRANT / REVOKE", func() {
+				UIGrantRevoke(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Матрица привилегий", func() {
+				UIPrivilegeMatrix(ctx, pool, window)
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Row-Level Security", func() {
+				UIRowLevelSecurity(ctx, pool, window)
+			}),
+		),
 		fyne.NewMenu("Типы данных",
 			fyne.NewMenuItem("Создать ENUM тип", func() {
 				UICreateEnumType(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("Значения ENUM", func() {
+				UIManageEnumValues(ctx, pool, window)
+			}),
 			fyne.NewMenuItem("Создать составной тип", func() {
 				UICreateCompositeType(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("Изменить составной тип", func() {
+				UIEditCompositeType(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Создать диапазонный тип (RANGE)", func() {
+				UICreateRangeType(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Создать домен (DOMAIN)", func() {
+				UICreateDomain(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Изменить домен", func() {
+				UIEditDomain(ctx, pool, window)
+			}),
 			fyne.NewMenuItem("Просмотреть все типы", func() {
 				UIListCustomTypes(ctx, pool, window)
 			}),
 			fyne.NewMenuItem("Информация о типе", func() {
 				UITypeInfo(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("Где используется тип", func() {
+				UITypeUsage(ctx, pool, window)
+			}),
 			fyne.NewMenuItem("Удалить тип", func() {
 				UIDropType(ctx, pool, window)
 			}),
@@ -128,6 +265,21 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 				UIJoinWizard(ctx, pool, window)
 			}),
 		),
+		fyne.NewMenu("Расширения",
+			fyne.NewMenuItem("Менеджер расширений", func() {
+				UIExtensionManager(ctx, pool, window)
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Сводная таблица (crosstab, tablefunc)", func() {
+				UICrosstab(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Статистика запросов (pg_stat_statements)", func() {
+				UIStatementStats(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("Дайджест столбца (pgcrypto)", func() {
+				UIColumnDigest(ctx, pool, window)
+			}),
+		),
 		fyne.NewMenu("Подключение",
 			fyne.NewMenuItem("Тест подключения", func() {
 				UITestConnection(ctx, pool, window)
@@ -140,9 +292,15 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 			fyne.NewMenuItem("✏️ Create or Replace VIEW", func() {
 				UICreateOrReplaceView(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("🛠 Edit VIEW (keep dependents)", func() {
+				UIEditView(ctx, pool, window)
+			}),
 			fyne.NewMenuItem("📜 List VIEWs", func() {
 				UIListViews(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("⏳ Temporary VIEWs (session)", func() {
+				UITemporaryViews(ctx, pool, window)
+			}),
 			fyne.NewMenuItem("🔍 Get VIEW Definition", func() {
 				UIGetViewDefinition(ctx, pool, window)
 			}),
@@ -156,6 +314,12 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 			fyne.NewMenuItem("🔄 Refresh MATERIALIZED VIEW", func() {
 				UIRefreshMaterializedView(ctx, pool, window)
 			}),
+			fyne.NewMenuItem("⏰ MATERIALIZED VIEW Refresh Schedules", func() {
+				UIMVRefreshSchedules(ctx, pool, window)
+			}),
+			fyne.NewMenuItem("🗂️ MATERIALIZED VIEW Indexes", func() {
+				UIMaterializedViewIndexes(ctx, pool, window)
+			}),
 			fyne.NewMenuItem("📜 List MATERIALIZED VIEWs", func() {
 				UIListMaterializedViews(ctx, pool, window)
 			}),
@@ -185,8 +349,30 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 		return
 	}
 
+	// Комментарии столбцов и таблицы (COMMENT ON), вычисляемые и IDENTITY столбцы (только чтение)
+	var columnComments map[string]string
+	var computedColumns map[string]string
+	tableCommentLabel := widget.NewLabel("")
+	tableCommentLabel.Wrapping = fyne.TextWrapWord
+
+	var tableWidget *widget.Table
+	reloadColumnInfo := func() {
+		columnComments, _ = operation.GetColumnComments(ctx, pool, currentTableName)
+		computedColumns, _ = operation.GetComputedColumns(ctx, pool, currentTableName)
+		tableComment, _ := operation.GetComment(ctx, pool, operation.CommentTable, currentTableName, "")
+		if tableComment != "" {
+			tableCommentLabel.SetText("💬 " + tableComment)
+		} else {
+			tableCommentLabel.SetText("")
+		}
+		if tableWidget != nil {
+			tableWidget.Refresh()
+		}
+	}
+	reloadColumnInfo()
+
 	// Создаём виджет таблицы с обрезкой текста
-	tableWidget := widget.NewTable(
+	tableWidget = widget.NewTable(
 		func() (int, int) {
 			if len(tableData) == 0 {
 				return 0, 0
@@ -215,11 +401,29 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 					text = text[:maxLen-3] + "..."
 				}
 
+				// Столбцы с комментарием помечаются значком, комментарий открывается по клику
+				column := headerColumnName(currentTableName, tableData[0][id.Col])
+				if id.Row == 0 && columnComments[column] != "" {
+					text += commentMarker
+				}
+				if id.Row == 0 && computedColumns[column] != "" {
+					text += computedMarker
+				}
+
 				label.SetText(text)
-				if id.Row == 0 {
+				switch {
+				case id.Row == 0:
 					label.TextStyle = fyne.TextStyle{Bold: true}
 					label.Importance = widget.HighImportance
+				case computedColumns[column] != "":
+					// Вычисляемые и IDENTITY значения не редактируются
+					label.TextStyle = fyne.TextStyle{Italic: true}
+					label.Importance = widget.LowImportance
+				default:
+					label.TextStyle = fyne.TextStyle{}
+					label.Importance = widget.MediumImportance
 				}
+				label.Refresh()
 			}
 		},
 	)
@@ -227,7 +431,17 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 	// Обработчик клика для редактирования
 	tableWidget.OnSelected = func(id widget.TableCellID) {
 		if id.Row == 0 {
-			return // Не редактируем заголовки
+			// По клику на заголовок показываем комментарий столбца
+			tableWidget.UnselectAll()
+			column := headerColumnName(currentTableName, tableData[0][id.Col])
+			showCommentEditor(ctx, pool, window, operation.CommentColumn, column, currentTableName, reloadColumnInfo)
+			return
+		}
+
+		if column := headerColumnName(currentTableName, tableData[0][id.Col]); computedColumns[column] != "" {
+			tableWidget.UnselectAll()
+			showInfo(window, fmt.Sprintf("Столбец %s (%s) заполняется базой данных и не редактируется", column, computedColumns[column]))
+			return
 		}
 
 		entry := widget.NewEntry()
@@ -260,10 +474,17 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 	tableSelect := widget.NewSelect(tablesList, func(selected string) {
 		currentTableName = selected
 		loadTableByName(ctx, pool, selected, &tableData, tableWidget, infoLabel)
+		reloadColumnInfo()
 	})
 	if len(tablesList) > 0 {
 		tableSelect.SetSelected(currentTableName)
 	}
+	openTableInGrid = func(table string) {
+		if list, err := getTablesListFromDB(ctx, pool); err == nil {
+			tableSelect.Options = list
+		}
+		tableSelect.SetSelected(table)
+	}
 
 	// ТЕПЕРЬ можно создавать кнопки
 	createTableBtn := widget.NewButton("➕ Создать таблицу", func() {
@@ -276,6 +497,17 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 
 	refreshBtn := widget.NewButton("🔄 Обновить", func() {
 		loadTableByName(ctx, pool, currentTableName, &tableData, tableWidget, infoLabel)
+		reloadColumnInfo()
+	})
+
+	truncateBtn := widget.NewButton("🧹 Очистить", func() {
+		UITruncateTables(ctx, pool, window, []string{currentTableName}, func() {
+			loadTableByName(ctx, pool, currentTableName, &tableData, tableWidget, infoLabel)
+		})
+	})
+
+	tableCommentBtn := widget.NewButton("💬 Комментарий", func() {
+		showCommentEditor(ctx, pool, window, operation.CommentTable, currentTableName, "", reloadColumnInfo)
 	})
 
 	addRowBtn := widget.NewButton("➕ Добавить строку", func() {
@@ -298,8 +530,11 @@ func CreateAdvancedUI(window fyne.Window, ctx context.Context, pool *pgxpool.Poo
 			refreshBtn,
 			addRowBtn,
 			deleteRowBtn,
+			truncateBtn,
+			tableCommentBtn,
 		),
 		infoLabel,
+		tableCommentLabel,
 		widget.NewSeparator(),
 	)
 
@@ -361,71 +596,9 @@ func UITestConnection(ctx context.Context, pool *pgxpool.Pool, window fyne.Windo
 	showInfo(window, "Подключение к базе данных успешно!")
 }
 
-// UICreateTablesWithTypes создаёт диалог для создания таблицы с типами
+// UICreateTablesWithTypes открывает конструктор таблицы
 func UICreateTablesWithTypes(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
-	tableNameEntry := widget.NewEntry()
-	tableNameEntry.SetPlaceHolder("Имя таблицы")
-
-	columnsEntry := widget.NewMultiLineEntry()
-	columnsEntry.SetPlaceHolder("Столбцы (формат: имя тип ограничения)\nПример:\nid SERIAL PRIMARY KEY\nname VARCHAR(100) NOT NULL")
-	columnsEntry.SetMinRowsVisible(8)
-
-	form := container.NewVBox(
-		widget.NewLabel("Создание таблицы с типами"),
-		widget.NewForm(
-			widget.NewFormItem("Имя таблицы", tableNameEntry),
-		),
-		widget.NewLabel("Определения столбцов:"),
-		columnsEntry,
-	)
-
-	dlg := dialog.NewCustomConfirm("Создать таблицу", "Создать", "Отмена", form, func(ok bool) {
-		if ok {
-			tableName := strings.TrimSpace(tableNameEntry.Text)
-			if tableName == "" {
-				showError(window, "Укажите имя таблицы")
-				return
-			}
-
-			lines := strings.Split(columnsEntry.Text, "\n")
-			var columns []operation.ColumnDefinition
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if line == "" {
-					continue
-				}
-				parts := strings.Fields(line)
-				if len(parts) < 2 {
-					showError(window, "Неверный формат столбца: "+line)
-					return
-				}
-				col := operation.ColumnDefinition{
-					Name: parts[0],
-					Type: parts[1],
-				}
-				if len(parts) > 2 {
-					col.Constraints = strings.Join(parts[2:], " ")
-				}
-				columns = append(columns, col)
-			}
-
-			if len(columns) == 0 {
-				showError(window, "Необходимо указать хотя бы один столбец")
-				return
-			}
-
-			err := operation.CreateTablesWithTypes(ctx, pool, tableName, columns)
-			if err != nil {
-				showError(window, "Ошибка создания таблицы: "+err.Error())
-				return
-			}
-
-			showInfo(window, fmt.Sprintf("Таблица '%s' успешно создана!", tableName))
-		}
-	}, window)
-
-	dlg.Resize(fyne.NewSize(600, 500))
-	dlg.Show()
+	showTableDesigner(ctx, pool, window, nil)
 }
 
 // UICreateTablesWithTypesButton - кнопочная версия создания таблицы с обновлением UI
@@ -433,76 +606,14 @@ func UICreateTablesWithTypesButton(ctx context.Context, pool *pgxpool.Pool, wind
 	dataPtr *[][]string, table *widget.Table, infoLabel *widget.Label,
 	currentTable *string, tableSelect *widget.Select) {
 
-	tableNameEntry := widget.NewEntry()
-	tableNameEntry.SetPlaceHolder("Имя таблицы")
-
-	columnsEntry := widget.NewMultiLineEntry()
-	columnsEntry.SetPlaceHolder("Столбцы (формат: имя тип ограничения)\nПример:\nid SERIAL PRIMARY KEY\nname VARCHAR(100) NOT NULL\nemail VARCHAR(255) UNIQUE")
-	columnsEntry.SetMinRowsVisible(8)
-
-	form := container.NewVBox(
-		widget.NewLabel("Создание новой таблицы"),
-		widget.NewForm(
-			widget.NewFormItem("Имя таблицы", tableNameEntry),
-		),
-		widget.NewLabel("Определения столбцов:"),
-		columnsEntry,
-	)
-
-	dlg := dialog.NewCustomConfirm("Создать таблицу", "Создать", "Отмена", form, func(ok bool) {
-		if ok {
-			tableName := strings.TrimSpace(tableNameEntry.Text)
-			if tableName == "" {
-				showError(window, "Укажите имя таблицы")
-				return
-			}
-
-			lines := strings.Split(columnsEntry.Text, "\n")
-			var columns []operation.ColumnDefinition
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if line == "" {
-					continue
-				}
-				parts := strings.Fields(line)
-				if len(parts) < 2 {
-					showError(window, "Неверный формат: "+line)
-					return
-				}
-				col := operation.ColumnDefinition{
-					Name: parts[0],
-					Type: parts[1],
-				}
-				if len(parts) > 2 {
-					col.Constraints = strings.Join(parts[2:], " ")
-				}
-				columns = append(columns, col)
-			}
-
-			if len(columns) == 0 {
-				showError(window, "Необходимо указать хотя бы один столбец")
-				return
-			}
-
-			err := operation.CreateTablesWithTypes(ctx, pool, tableName, columns)
-			if err != nil {
-				showError(window, "Ошибка: "+err.Error())
-				return
-			}
-
-			showInfo(window, fmt.Sprintf("Таблица '%s' создана!", tableName))
-
-			// Обновляем список таблиц
-			tablesList, _ := getTablesListFromDB(ctx, pool)
-			tableSelect.Options = tablesList
-			*currentTable = tableName
-			tableSelect.SetSelected(tableName)
-			loadTableByName(ctx, pool, tableName, dataPtr, table, infoLabel)
-		}
-	}, window)
-
-	dlg.Resize(fyne.NewSize(600, 500))
-	dlg.Show()
+	showTableDesigner(ctx, pool, window, func(tableName string) {
+		// Обновляем список таблиц
+		tablesList, _ := getTablesListFromDB(ctx, pool)
+		tableSelect.Options = tablesList
+		*currentTable = tableName
+		tableSelect.SetSelected(tableName)
+		loadTableByName(ctx, pool, tableName, dataPtr, table, infoLabel)
+	})
 }
 
 // UIRenameTable создаёт диалог для переименования таблицы
@@ -533,29 +644,52 @@ func UIRenameTable(ctx context.Context, pool *pgxpool.Pool, window fyne.Window)
 
 // UIAddColumn создаёт диалог для добавления столбца
 func UIAddColumn(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	typeOptions, _ := operation.GetColumnTypeOptions(ctx, pool)
+
 	tableEntry := widget.NewEntry()
 	tableEntry.SetPlaceHolder("Имя таблицы")
 	columnEntry := widget.NewEntry()
 	columnEntry.SetPlaceHolder("Имя столбца")
-	typeEntry := widget.NewEntry()
+	typeEntry := newTypePicker(typeOptions)
 	typeEntry.SetPlaceHolder("Тип данных (например, VARCHAR(100))")
+	collationEntry := widget.NewEntry()
+	collationEntry.SetPlaceHolder("Необязательно (например, C)")
+	notNullCheck := widget.NewCheck("NOT NULL", nil)
+	expressionEntry := widget.NewEntry()
+	expressionEntry.SetPlaceHolder("Выражение DEFAULT или GENERATED")
+	valueSelect := widget.NewSelect(designerValueKinds, func(s string) {
+		if s == valueIdentityAlways || s == valueIdentityByDefault {
+			expressionEntry.Disable()
+		} else {
+			expressionEntry.Enable()
+		}
+	})
+	valueSelect.SetSelected(valueDefault)
 	constraintsEntry := widget.NewEntry()
-	constraintsEntry.SetPlaceHolder("Ограничения (например, NOT NULL)")
+	constraintsEntry.SetPlaceHolder("Ограничения (например, UNIQUE, CHECK (...))")
 
 	form := widget.NewForm(
 		widget.NewFormItem("Таблица", tableEntry),
 		widget.NewFormItem("Столбец", columnEntry),
 		widget.NewFormItem("Тип", typeEntry),
+		widget.NewFormItem("COLLATE", collationEntry),
+		widget.NewFormItem("", notNullCheck),
+		widget.NewFormItem("Значение", container.NewBorder(nil, nil, valueSelect, nil, expressionEntry)),
 		widget.NewFormItem("Ограничения", constraintsEntry),
 	)
 
-	dialog.ShowCustomConfirm("Добавить столбец", "Добавить", "Отмена", form, func(ok bool) {
+	dlg := dialog.NewCustomConfirm("Добавить столбец", "Добавить", "Отмена", form, func(ok bool) {
 		if ok {
-			err := operation.AddColumn(ctx, pool,
-				strings.TrimSpace(tableEntry.Text),
-				strings.TrimSpace(columnEntry.Text),
-				strings.TrimSpace(typeEntry.Text),
-				strings.TrimSpace(constraintsEntry.Text))
+			col := operation.ColumnDefinition{
+				Name:        strings.TrimSpace(columnEntry.Text),
+				Type:        strings.TrimSpace(typeEntry.Text),
+				Collation:   strings.TrimSpace(collationEntry.Text),
+				NotNull:     notNullCheck.Checked,
+				Constraints: strings.TrimSpace(constraintsEntry.Text),
+			}
+			designerColumn{ValueKind: valueSelect.Selected, Expression: expressionEntry.Text}.applyValueKind(&col)
+
+			err := operation.AddColumn(ctx, pool, strings.TrimSpace(tableEntry.Text), col)
 			if err != nil {
 				showError(window, "Ошибка добавления столбца: "+err.Error())
 				return
@@ -563,6 +697,8 @@ func UIAddColumn(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 			showInfo(window, "Столбец успешно добавлен!")
 		}
 	}, window)
+	dlg.Resize(fyne.NewSize(550, 450))
+	dlg.Show()
 }
 
 // UIDropColumn создаёт диалог для удаления столбца
@@ -591,36 +727,6 @@ func UIDropColumn(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 	}, window)
 }
 
-// UIAlterColumnType создаёт диалог для изменения типа столбца
-func UIAlterColumnType(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
-	tableEntry := widget.NewEntry()
-	tableEntry.SetPlaceHolder("Имя таблицы")
-	columnEntry := widget.NewEntry()
-	columnEntry.SetPlaceHolder("Имя столбца")
-	newTypeEntry := widget.NewEntry()
-	newTypeEntry.SetPlaceHolder("Новый тип (например, TEXT)")
-
-	form := widget.NewForm(
-		widget.NewFormItem("Таблица", tableEntry),
-		widget.NewFormItem("Столбец", columnEntry),
-		widget.NewFormItem("Новый тип", newTypeEntry),
-	)
-
-	dialog.ShowCustomConfirm("Изменить тип столбца", "Изменить", "Отмена", form, func(ok bool) {
-		if ok {
-			err := operation.AlterColumnType(ctx, pool,
-				strings.TrimSpace(tableEntry.Text),
-				strings.TrimSpace(columnEntry.Text),
-				strings.TrimSpace(newTypeEntry.Text))
-			if err != nil {
-				showError(window, "Ошибка изменения типа: "+err.Error())
-				return
-			}
-			showInfo(window, "Тип столбца успешно изменен!")
-		}
-	}, window)
-}
-
 // UIRenameColumn создаёт диалог для переименования столбца
 func UIRenameColumn(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 	tableEntry := widget.NewEntry()
@@ -958,12 +1064,15 @@ func showAddRowDialogAdvanced(ctx context.Context, pool *pgxpool.Pool, window fy
 
 	headers := (*dataPtr)[0]
 
+	// Вычисляемые и IDENTITY столбцы заполняет база данных
+	computed, _ := operation.GetComputedColumns(ctx, pool, tableName)
+
 	var entries []*widget.Entry
 	var formItems []*widget.FormItem
 	var columnNames []string
 
 	for _, colName := range headers {
-		if strings.ToLower(colName) == "id" {
+		if strings.ToLower(colName) == "id" || computed[headerColumnName(tableName, colName)] != "" {
 			continue
 		}
 
@@ -1129,31 +1238,30 @@ func showDeleteTableDialog(ctx context.Context, pool *pgxpool.Pool, window fyne.
 				}
 			}
 
-			err := dropTable(ctx, pool, tableName)
-			if err != nil {
-				showError(window, "Ошибка удаления таблицы: "+err.Error())
-				return
+			dropFn := func(cascade bool) error {
+				return operation.DropTable(ctx, pool, tableName, cascade)
 			}
+			showDropImpactDialog(ctx, pool, window, operation.DependencyTable, tableName, dropFn, func() {
+				showInfo(window, fmt.Sprintf("Таблица '%s' успешно удалена!", tableName))
 
-			showInfo(window, fmt.Sprintf("Таблица '%s' успешно удалена!", tableName))
-
-			tablesList, err := getTablesListFromDB(ctx, pool)
-			if err != nil {
-				showError(window, "Ошибка обновления списка таблиц")
-				return
-			}
+				tablesList, err := getTablesListFromDB(ctx, pool)
+				if err != nil {
+					showError(window, "Ошибка обновления списка таблиц")
+					return
+				}
 
-			tableSelect.Options = tablesList
+				tableSelect.Options = tablesList
 
-			if len(tablesList) > 0 {
-				*currentTable = tablesList[0]
-				tableSelect.SetSelected(*currentTable)
-				loadTableByName(ctx, pool, *currentTable, dataPtr, table, infoLabel)
-			} else {
-				*dataPtr = [][]string{{"Нет таблиц"}}
-				table.Refresh()
-				infoLabel.SetText("Таблиц не найдено")
-			}
+				if len(tablesList) > 0 {
+					*currentTable = tablesList[0]
+					tableSelect.SetSelected(*currentTable)
+					loadTableByName(ctx, pool, *currentTable, dataPtr, table, infoLabel)
+				} else {
+					*dataPtr = [][]string{{"Нет таблиц"}}
+					table.Refresh()
+					infoLabel.SetText("Таблиц не найдено")
+				}
+			})
 		},
 		window,
 	)
@@ -1162,16 +1270,6 @@ func showDeleteTableDialog(ctx context.Context, pool *pgxpool.Pool, window fyne.
 	dlg.Show()
 }
 
-// Функция удаления таблицы из БД
-func dropTable(ctx context.Context, pool *pgxpool.Pool, tableName string) error {
-	query := fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", tableName)
-	_, err := pool.Exec(ctx, query)
-	if err != nil {
-		return fmt.Errorf("не удалось удалить таблицу: %w", err)
-	}
-	return nil
-}
-
 // Вспомогательные функции для работы с БД
 
 func getTablesListFromDB(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
@@ -1333,7 +1431,7 @@ func UICreateCompositeType(ctx context.Context, pool *pgxpool.Pool, window fyne.
 			}
 
 			lines := strings.Split(fieldsEntry.Text, "\n")
-			fields := make(map[string]string)
+			var fields []operation.CompositeField
 
 			for _, line := range lines {
 				line = strings.TrimSpace(line)
@@ -1347,9 +1445,10 @@ func UICreateCompositeType(ctx context.Context, pool *pgxpool.Pool, window fyne.
 					return
 				}
 
-				fieldName := parts[0]
-				fieldType := strings.Join(parts[1:], " ")
-				fields[fieldName] = fieldType
+				fields = append(fields, operation.CompositeField{
+					Name: parts[0],
+					Type: strings.Join(parts[1:], " "),
+				})
 			}
 
 			if len(fields) == 0 {
@@ -1441,8 +1540,8 @@ func UITypeInfo(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 				)
 			} else if info.Kind == "COMPOSITE" {
 				var fieldTexts []string
-				for fieldName, fieldType := range info.Fields {
-					fieldTexts = append(fieldTexts, fmt.Sprintf("%s: %s", fieldName, fieldType))
+				for _, field := range info.Fields {
+					fieldTexts = append(fieldTexts, fmt.Sprintf("%s: %s", field.Name, field.Type))
 				}
 				fieldsList := widget.NewLabel(strings.Join(fieldTexts, "\n"))
 				content = container.NewVBox(
@@ -1450,10 +1549,73 @@ func UITypeInfo(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 						widget.NewLabel("Имя: "+info.Name),
 						widget.NewLabel("Поля:"),
 						fieldsList,
+						widget.NewButton("✏ Изменить поля", func() {
+							showCompositeTypeEditor(ctx, pool, window, info.Name)
+						}),
+					)),
+				)
+			} else if info.Kind == "RANGE" && info.Range != nil {
+				rangeType := info.Range
+				orDash := func(s string) string {
+					if s == "" {
+						return "—"
+					}
+					return s
+				}
+				content = container.NewVBox(
+					widget.NewCard("Тип", "RANGE", container.NewVBox(
+						widget.NewLabel("Имя: "+info.Name),
+						widget.NewLabel("Подтип: "+rangeType.Subtype),
+						widget.NewLabel("Класс операторов: "+rangeType.SubtypeOpClass),
+						widget.NewLabel("Правило сортировки: "+orDash(rangeType.Collation)),
+						widget.NewLabel("SUBTYPE_DIFF: "+orDash(rangeType.SubtypeDiff)),
+					)),
+				)
+			} else if info.Kind == "DOMAIN" && info.Domain != nil {
+				domain := info.Domain
+				defaultText := domain.Default
+				if defaultText == "" {
+					defaultText = "—"
+				}
+				var checkTexts []string
+				for _, check := range domain.Checks {
+					checkTexts = append(checkTexts, fmt.Sprintf("%s: CHECK (%s)", check.Name, check.Expression))
+				}
+				if len(checkTexts) == 0 {
+					checkTexts = append(checkTexts, "—")
+				}
+				content = container.NewVBox(
+					widget.NewCard("Тип", "DOMAIN", container.NewVBox(
+						widget.NewLabel("Имя: "+info.Name),
+						widget.NewLabel("Базовый тип: "+domain.BaseType),
+						widget.NewLabel("DEFAULT: "+defaultText),
+						widget.NewLabel(fmt.Sprintf("NOT NULL: %t", domain.NotNull)),
+						widget.NewLabel("Ограничения:"),
+						widget.NewLabel(strings.Join(checkTexts, "\n")),
+						widget.NewButton("✏ Изменить домен", func() {
+							showDomainEditor(ctx, pool, window, info.Name)
+						}),
 					)),
 				)
 			}
 
+			if content != nil {
+				description := info.Description
+				if description == "" {
+					description = "—"
+				}
+				descriptionLabel := widget.NewLabel("Описание: " + description)
+				descriptionLabel.Wrapping = fyne.TextWrapWord
+				editCommentBtn := widget.NewButton("💬 Изменить описание", func() {
+					showCommentEditor(ctx, pool, window, operation.CommentType, info.Name, "", nil)
+				})
+				usageBtn := widget.NewButton("🔎 Где используется", func() {
+					showTypeUsageReport(ctx, pool, window, info.Name)
+				})
+				content.Add(descriptionLabel)
+				content.Add(container.NewHBox(editCommentBtn, usageBtn))
+			}
+
 			infoWindow := fyne.CurrentApp().NewWindow("Информация о типе: " + typeName)
 			infoWindow.SetContent(container.NewScroll(content))
 			infoWindow.Resize(fyne.NewSize(600, 400))
@@ -1468,8 +1630,17 @@ func UIDropType(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 	typeNameEntry := widget.NewEntry()
 	typeNameEntry.SetPlaceHolder("Имя типа для удаления")
 
+	usageBtn := widget.NewButton("🔎 Где используется", func() {
+		typeName := strings.TrimSpace(typeNameEntry.Text)
+		if typeName == "" {
+			showError(window, "Укажите имя типа")
+			return
+		}
+		showTypeUsageReport(ctx, pool, window, typeName)
+	})
+
 	form := widget.NewForm(
-		widget.NewFormItem("Имя типа", typeNameEntry),
+		widget.NewFormItem("Имя типа", container.NewBorder(nil, nil, nil, usageBtn, typeNameEntry)),
 	)
 
 	dialog.ShowCustomConfirm("Удалить тип", "Удалить", "Отмена", form, func(ok bool) {
@@ -1480,13 +1651,17 @@ func UIDropType(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 				return
 			}
 
-			err := operation.DropEnumType(ctx, pool, typeName)
-			if err != nil {
-				showError(window, "Ошибка удаления: "+err.Error())
-				return
+			// Домены удаляются через DROP DOMAIN, остальные типы — через DROP TYPE
+			isDomain := getCustomTypeKind(ctx, pool, typeName) == "DOMAIN"
+			dropFn := func(cascade bool) error {
+				if isDomain {
+					return operation.DropDomain(ctx, pool, typeName, cascade)
+				}
+				return operation.DropEnumType(ctx, pool, typeName, cascade)
 			}
-
-			showInfo(window, fmt.Sprintf("Тип '%s' успешно удален!", typeName))
+			showDropImpactDialog(ctx, pool, window, operation.DependencyType, typeName, dropFn, func() {
+				showInfo(window, fmt.Sprintf("Тип '%s' успешно удален!", typeName))
+			})
 		}
 	}, window)
 }
@@ -0,0 +1,235 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для изменения типа столбца ==========
+
+// UIAlterColumnType открывает окно изменения типа столбца с выражением USING
+// и предварительной проверкой преобразования существующих строк
+func UIAlterColumnType(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tables, err := getTablesListFromDB(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения таблиц: "+err.Error())
+		return
+	}
+	typeOptions, _ := operation.GetColumnTypeOptions(ctx, pool)
+
+	alterWindow := fyne.CurrentApp().NewWindow("Изменить тип столбца")
+
+	var columns []operation.ColumnDefaultInfo
+	currentTypeLabel := widget.NewLabel("")
+	columnSelect := widget.NewSelect(nil, func(name string) {
+		currentTypeLabel.SetText("")
+		for _, col := range columns {
+			if col.Column == name {
+				currentTypeLabel.SetText("Текущий тип: " + col.Type)
+			}
+		}
+	})
+	columnSelect.PlaceHolder = "Столбец"
+
+	loadColumns := func(table string) {
+		var err error
+		columns, err = operation.GetColumnDefaults(ctx, pool, table)
+		if err != nil {
+			showError(alterWindow, err.Error())
+			return
+		}
+		var names []string
+		for _, col := range columns {
+			names = append(names, col.Column)
+		}
+		columnSelect.Options = names
+		columnSelect.ClearSelected()
+		columnSelect.Refresh()
+	}
+	tableSelect := widget.NewSelect(tables, loadColumns)
+	tableSelect.PlaceHolder = "Таблица"
+
+	newTypeEntry := newTypePicker(typeOptions)
+	newTypeEntry.SetPlaceHolder("Новый тип (например, INTEGER)")
+	usingEntry := widget.NewEntry()
+	usingEntry.SetPlaceHolder("Необязательно, например: trim(price)::integer")
+
+	summaryLabel := widget.NewLabel("Выберите столбец и новый тип, затем нажмите «Проверить»")
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	samplesHeader := []string{"ctid", "Значение", "Ошибка"}
+	samplesData := [][]string{samplesHeader}
+	samplesTable := widget.NewTable(
+		func() (int, int) {
+			return len(samplesData), len(samplesHeader)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.SetText(samplesData[id.Row][id.Col])
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+		},
+	)
+
+	var checkBtn, alterBtn *widget.Button
+	setBusy := func(busy bool) {
+		for _, btn := range []*widget.Button{checkBtn, alterBtn} {
+			if busy {
+				btn.Disable()
+			} else {
+				btn.Enable()
+			}
+		}
+	}
+
+	showCheck := func(check *operation.ColumnTypeCheck) {
+		var lines []string
+		lines = append(lines, fmt.Sprintf("%s → %s", check.CurrentType, check.NewType))
+		if check.Rewrite {
+			lines = append(lines, "⚠ "+check.RewriteReason)
+		} else {
+			lines = append(lines, "ℹ "+check.RewriteReason)
+		}
+		if check.UsingRequired && check.Using == "" {
+			lines = append(lines, fmt.Sprintf("⚠ Требуется выражение USING, например: %s", check.SuggestedUsing))
+		}
+		if check.FailedRows > 0 {
+			lines = append(lines, fmt.Sprintf("✗ Не преобразуются %d из %d строк (показаны первые %d)",
+				check.FailedRows, check.TotalRows, len(check.Samples)))
+		} else {
+			lines = append(lines, fmt.Sprintf("✓ Все %d строк преобразуются", check.TotalRows))
+		}
+		summaryLabel.SetText(strings.Join(lines, "\n"))
+
+		samplesData = [][]string{samplesHeader}
+		for _, sample := range check.Samples {
+			samplesData = append(samplesData, []string{sample.CTID, sample.Value, sample.Error})
+		}
+		setOptimalColumnWidths(samplesTable, samplesData)
+		samplesTable.Refresh()
+	}
+
+	// Результат последней проверки: повторная проверка тех же параметров не сканирует таблицу заново
+	var lastCheck *operation.ColumnTypeCheck
+	var lastCheckKey string
+
+	// runCheck проверяет преобразование строк в фоне (построчная проверка долгая на больших таблицах)
+	// и вызывает onDone в потоке UI
+	runCheck := func(onDone func(check *operation.ColumnTypeCheck)) {
+		table, column := tableSelect.Selected, columnSelect.Selected
+		if table == "" || column == "" {
+			showError(alterWindow, "Выберите таблицу и столбец")
+			return
+		}
+		newType, using := strings.TrimSpace(newTypeEntry.Text), strings.TrimSpace(usingEntry.Text)
+		key := strings.Join([]string{table, column, newType, using}, "\x00")
+		if lastCheck != nil && key == lastCheckKey {
+			showCheck(lastCheck)
+			if onDone != nil {
+				onDone(lastCheck)
+			}
+			return
+		}
+
+		setBusy(true)
+		summaryLabel.SetText(fmt.Sprintf("Проверка преобразования %s.%s...", table, column))
+		go func() {
+			check, err := operation.CheckColumnTypeChange(ctx, pool, table, column,
+				newType, using, operation.DefaultConversionSampleLimit)
+			fyne.Do(func() {
+				setBusy(false)
+				if err != nil {
+					summaryLabel.SetText("")
+					showError(alterWindow, err.Error())
+					return
+				}
+				lastCheck, lastCheckKey = check, key
+				showCheck(check)
+				if onDone != nil {
+					onDone(check)
+				}
+			})
+		}()
+	}
+
+	checkBtn = widget.NewButton("Проверить", func() {
+		runCheck(nil)
+	})
+
+	suggestBtn := widget.NewButton("Подставить USING", func() {
+		if columnSelect.Selected == "" || strings.TrimSpace(newTypeEntry.Text) == "" {
+			return
+		}
+		usingEntry.SetText(fmt.Sprintf("%s::%s", columnSelect.Selected, strings.TrimSpace(newTypeEntry.Text)))
+	})
+
+	alterBtn = widget.NewButton("Изменить тип", func() {
+		runCheck(func(check *operation.ColumnTypeCheck) {
+			if check.FailedRows > 0 {
+				showError(alterWindow, fmt.Sprintf("%d строк не преобразуются к типу %s — исправьте данные или выражение USING",
+					check.FailedRows, check.NewType))
+				return
+			}
+			if check.UsingRequired && check.Using == "" {
+				showError(alterWindow, "Для этого преобразования нужно выражение USING, например: "+check.SuggestedUsing)
+				return
+			}
+
+			table, column := tableSelect.Selected, columnSelect.Selected
+			apply := func() {
+				if err := operation.AlterColumnType(ctx, pool, table, column, newTypeEntry.Text, usingEntry.Text); err != nil {
+					showError(alterWindow, "Ошибка изменения типа: "+err.Error())
+					return
+				}
+				lastCheck = nil
+				showInfo(alterWindow, "Тип столбца успешно изменен!")
+				loadColumns(table)
+				columnSelect.SetSelected(column)
+			}
+
+			if check.Rewrite {
+				dialog.ShowConfirm("Перезапись таблицы",
+					fmt.Sprintf("Изменение типа %s.%s перезапишет таблицу (%d строк) и её индексы.\n"+
+						"На время операции таблица будет заблокирована. Продолжить?", table, column, check.TotalRows),
+					func(ok bool) {
+						if ok {
+							apply()
+						}
+					}, alterWindow)
+				return
+			}
+			apply()
+		})
+	})
+	alterBtn.Importance = widget.HighImportance
+
+	form := widget.NewForm(
+		widget.NewFormItem("Таблица", tableSelect),
+		widget.NewFormItem("Столбец", container.NewBorder(nil, nil, nil, currentTypeLabel, columnSelect)),
+		widget.NewFormItem("Новый тип", newTypeEntry),
+		widget.NewFormItem("USING", container.NewBorder(nil, nil, nil, suggestBtn, usingEntry)),
+	)
+
+	controls := container.NewVBox(
+		form,
+		container.NewHBox(checkBtn, alterBtn),
+		summaryLabel,
+		widget.NewSeparator(),
+		widget.NewLabel("Строки, которые не удаётся преобразовать:"),
+	)
+
+	alterWindow.SetContent(container.NewBorder(controls, nil, nil, nil, container.NewScroll(samplesTable)))
+	alterWindow.Resize(fyne.NewSize(850, 600))
+	alterWindow.CenterOnScreen()
+	alterWindow.Show()
+}
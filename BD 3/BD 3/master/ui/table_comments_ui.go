@@ -0,0 +1,167 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для комментариев (COMMENT ON) ==========
+
+// productsHeaderColumns сопоставляет русские заголовки таблицы products со столбцами БД
+var productsHeaderColumns = map[string]string{
+	"ID":         "id",
+	"Название":   "name",
+	"Описание":   "description",
+	"Цена":       "price",
+	"Количество": "quantity",
+	"Активен":    "is_active",
+	"Категория":  "category_id",
+}
+
+// headerColumnName возвращает имя столбца БД для заголовка основной таблицы
+func headerColumnName(tableName, header string) string {
+	if tableName == "products" {
+		if column, ok := productsHeaderColumns[header]; ok {
+			return column
+		}
+	}
+	return header
+}
+
+// commentMarker помечает заголовки столбцов, у которых есть комментарий
+const commentMarker = " ⓘ"
+
+// showCommentEditor показывает текущий комментарий объекта и позволяет его изменить
+func showCommentEditor(ctx context.Context, pool *pgxpool.Pool, window fyne.Window,
+	objectType, objectName, parent string, onSaved func()) {
+
+	comment, err := operation.GetComment(ctx, pool, objectType, objectName, parent)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+
+	commentEntry := widget.NewMultiLineEntry()
+	commentEntry.SetText(comment)
+	commentEntry.SetPlaceHolder("Комментарий (пусто — удалить)")
+	commentEntry.SetMinRowsVisible(4)
+	commentEntry.Wrapping = fyne.TextWrapWord
+
+	title := fmt.Sprintf("%s %s", objectType, objectName)
+	if parent != "" {
+		title = fmt.Sprintf("%s %s.%s", objectType, parent, objectName)
+	}
+
+	dlg := dialog.NewCustomConfirm(title, "Сохранить", "Закрыть", commentEntry, func(ok bool) {
+		if !ok || commentEntry.Text == comment {
+			return
+		}
+		if err := operation.SetComment(ctx, pool, objectType, objectName, parent, commentEntry.Text); err != nil {
+			showError(window, err.Error())
+			return
+		}
+		if onSaved != nil {
+			onSaved()
+		}
+	}, window)
+
+	dlg.Resize(fyne.NewSize(500, 250))
+	dlg.Show()
+}
+
+// UIEditComment создаёт диалог для просмотра и изменения комментария любого объекта
+func UIEditComment(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tables, _ := getTablesListFromDB(ctx, pool)
+
+	objectSelect := widget.NewSelect(nil, nil)
+	objectSelect.PlaceHolder = "Объект"
+
+	tableSelect := widget.NewSelect(tables, nil)
+	tableSelect.PlaceHolder = "Таблица"
+	tableSelect.Disable()
+
+	objectTypeSelect := widget.NewSelect(operation.CommentObjectTypes, nil)
+
+	reloadObjects := func() {
+		objectType := objectTypeSelect.Selected
+		needsTable := objectType == operation.CommentColumn || objectType == operation.CommentConstraint
+		if needsTable {
+			tableSelect.Enable()
+			if tableSelect.Selected == "" {
+				objectSelect.Options = nil
+				objectSelect.ClearSelected()
+				return
+			}
+		} else {
+			tableSelect.Disable()
+		}
+
+		objects, err := operation.ListCommentObjects(ctx, pool, objectType, tableSelect.Selected)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		objectSelect.Options = objects
+		objectSelect.ClearSelected()
+	}
+	objectTypeSelect.OnChanged = func(string) { reloadObjects() }
+	tableSelect.OnChanged = func(string) { reloadObjects() }
+	objectTypeSelect.SetSelected(operation.CommentTable)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Тип объекта", objectTypeSelect),
+		widget.NewFormItem("Таблица", tableSelect),
+		widget.NewFormItem("Объект", objectSelect),
+	)
+
+	dialog.ShowCustomConfirm("Комментарий объекта", "Открыть", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		if objectSelect.Selected == "" {
+			showError(window, "Выберите объект")
+			return
+		}
+
+		parent := ""
+		if objectTypeSelect.Selected == operation.CommentColumn || objectTypeSelect.Selected == operation.CommentConstraint {
+			parent = tableSelect.Selected
+		}
+		showCommentEditor(ctx, pool, window, objectTypeSelect.Selected, objectSelect.Selected, parent, func() {
+			showInfo(window, "Комментарий сохранён!")
+		})
+	}, window)
+}
+
+// UIListComments показывает все комментарии схемы
+func UIListComments(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	data, err := operation.ListAllComments(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения комментариев: "+err.Error())
+		return
+	}
+	if len(data) <= 1 {
+		showInfo(window, "Комментариев нет")
+		return
+	}
+
+	table, err := CreateTable(data)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+	setOptimalColumnWidths(table, data)
+
+	commentsWindow := fyne.CurrentApp().NewWindow("Комментарии объектов")
+	commentsWindow.SetContent(container.NewScroll(table))
+	commentsWindow.Resize(fyne.NewSize(900, 500))
+	commentsWindow.CenterOnScreen()
+	commentsWindow.Show()
+}
@@ -0,0 +1,253 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для значений по умолчанию ==========
+
+// UIColumnDefaults показывает значения по умолчанию столбцов таблицы и позволяет
+// установить/удалить DEFAULT с необязательным заполнением существующих NULL
+func UIColumnDefaults(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tables, err := getTablesListFromDB(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения таблиц: "+err.Error())
+		return
+	}
+
+	defaultsWindow := fyne.CurrentApp().NewWindow("Значения по умолчанию (DEFAULT)")
+
+	var columns []operation.ColumnDefaultInfo
+	header := []string{"Столбец", "Тип", "DEFAULT", "NOT NULL", "Примечание"}
+	data := [][]string{header}
+	defaultsTable := widget.NewTable(
+		func() (int, int) {
+			return len(data), len(header)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.SetText(data[id.Row][id.Col])
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+		},
+	)
+
+	columnSelect := widget.NewSelect(nil, nil)
+	columnSelect.PlaceHolder = "Столбец"
+	expressionEntry := widget.NewEntry()
+	expressionEntry.SetPlaceHolder("Выражение, например: 0, 'new', now()")
+	nullsLabel := widget.NewLabel("")
+
+	backfillCheck := widget.NewCheck("Заполнить существующие NULL новым значением", nil)
+	batchEntry := widget.NewEntry()
+	batchEntry.SetText(strconv.Itoa(operation.DefaultBackfillBatchSize))
+	progressBar := widget.NewProgressBar()
+	progressBar.Hide()
+	statusLabel := widget.NewLabel("")
+
+	tableSelect := widget.NewSelect(tables, nil)
+
+	reload := func() {
+		table := tableSelect.Selected
+		if table == "" {
+			return
+		}
+		var err error
+		columns, err = operation.GetColumnDefaults(ctx, pool, table)
+		if err != nil {
+			showError(defaultsWindow, err.Error())
+			return
+		}
+
+		data = [][]string{header}
+		var names []string
+		for _, col := range columns {
+			notNull := ""
+			if col.NotNull {
+				notNull = "✓"
+			}
+			data = append(data, []string{col.Column, col.Type, col.Default, notNull, col.Computed})
+			// У вычисляемых и IDENTITY столбцов DEFAULT не задаётся
+			if col.Computed == "" {
+				names = append(names, col.Column)
+			}
+		}
+		setOptimalColumnWidths(defaultsTable, data)
+		defaultsTable.Refresh()
+
+		selected := columnSelect.Selected
+		columnSelect.Options = names
+		columnSelect.ClearSelected()
+		for _, name := range names {
+			if name == selected {
+				columnSelect.SetSelected(name)
+			}
+		}
+		columnSelect.Refresh()
+	}
+
+	tableSelect.OnChanged = func(string) { reload() }
+
+	columnSelect.OnChanged = func(name string) {
+		nullsLabel.SetText("")
+		if name == "" {
+			return
+		}
+		for _, col := range columns {
+			if col.Column == name {
+				expressionEntry.SetText(col.Default)
+			}
+		}
+		if count, err := operation.CountNullValues(ctx, pool, tableSelect.Selected, name); err == nil {
+			nullsLabel.SetText(fmt.Sprintf("Строк с NULL: %d", count))
+		}
+	}
+
+	defaultsTable.OnSelected = func(id widget.TableCellID) {
+		defaultsTable.UnselectAll()
+		if id.Row == 0 || id.Row > len(columns) {
+			return
+		}
+		col := columns[id.Row-1]
+		if col.Computed != "" {
+			showInfo(defaultsWindow, fmt.Sprintf("Столбец %s (%s) — значение по умолчанию не задаётся", col.Column, col.Computed))
+			return
+		}
+		columnSelect.SetSelected(col.Column)
+	}
+
+	var setBtn, dropBtn *widget.Button
+	setBusy := func(busy bool) {
+		if busy {
+			setBtn.Disable()
+			dropBtn.Disable()
+		} else {
+			setBtn.Enable()
+			dropBtn.Enable()
+		}
+	}
+
+	runBackfill := func(table, column string, batchSize int) {
+		total, _ := operation.CountNullValues(ctx, pool, table, column)
+		if total == 0 {
+			statusLabel.SetText("DEFAULT установлено, строк с NULL нет")
+			return
+		}
+
+		setBusy(true)
+		progressBar.SetValue(0)
+		progressBar.Show()
+		statusLabel.SetText(fmt.Sprintf("Заполнение NULL: 0 из %d", total))
+
+		go func() {
+			updated, err := operation.BackfillColumnDefault(ctx, pool, table, column, batchSize, func(done int64) {
+				fyne.Do(func() {
+					progressBar.SetValue(float64(done) / float64(total))
+					statusLabel.SetText(fmt.Sprintf("Заполнение NULL: %d из %d", done, total))
+				})
+			})
+			fyne.Do(func() {
+				setBusy(false)
+				progressBar.Hide()
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("Заполнено строк: %d", updated))
+					showError(defaultsWindow, err.Error())
+				} else {
+					statusLabel.SetText(fmt.Sprintf("DEFAULT установлено, заполнено строк: %d", updated))
+				}
+				reload()
+			})
+		}()
+	}
+
+	setBtn = widget.NewButton("SET DEFAULT", func() {
+		table, column := tableSelect.Selected, columnSelect.Selected
+		if table == "" || column == "" {
+			showError(defaultsWindow, "Выберите таблицу и столбец")
+			return
+		}
+
+		batchSize := operation.DefaultBackfillBatchSize
+		if backfillCheck.Checked {
+			n, err := strconv.Atoi(strings.TrimSpace(batchEntry.Text))
+			if err != nil || n <= 0 {
+				showError(defaultsWindow, "Размер пакета должен быть положительным числом")
+				return
+			}
+			batchSize = n
+		}
+
+		if err := operation.SetColumnDefault(ctx, pool, table, column, expressionEntry.Text); err != nil {
+			showError(defaultsWindow, err.Error())
+			return
+		}
+		reload()
+
+		if backfillCheck.Checked {
+			runBackfill(table, column, batchSize)
+		} else {
+			statusLabel.SetText("DEFAULT установлено")
+		}
+	})
+	setBtn.Importance = widget.HighImportance
+
+	dropBtn = widget.NewButton("DROP DEFAULT", func() {
+		table, column := tableSelect.Selected, columnSelect.Selected
+		if table == "" || column == "" {
+			showError(defaultsWindow, "Выберите таблицу и столбец")
+			return
+		}
+
+		dialog.ShowConfirm("Удаление DEFAULT",
+			fmt.Sprintf("Удалить значение по умолчанию столбца %s.%s?", table, column),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := operation.DropColumnDefault(ctx, pool, table, column); err != nil {
+					showError(defaultsWindow, err.Error())
+					return
+				}
+				expressionEntry.SetText("")
+				statusLabel.SetText("DEFAULT удалено")
+				reload()
+			}, defaultsWindow)
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem("Таблица", tableSelect),
+		widget.NewFormItem("Столбец", container.NewBorder(nil, nil, nil, nullsLabel, columnSelect)),
+		widget.NewFormItem("DEFAULT", expressionEntry),
+		widget.NewFormItem("", backfillCheck),
+		widget.NewFormItem("Размер пакета", batchEntry),
+	)
+
+	controls := container.NewVBox(
+		form,
+		container.NewHBox(setBtn, dropBtn),
+		progressBar,
+		statusLabel,
+		widget.NewSeparator(),
+	)
+
+	defaultsWindow.SetContent(container.NewBorder(controls, nil, nil, nil, container.NewScroll(defaultsTable)))
+	defaultsWindow.Resize(fyne.NewSize(850, 600))
+	defaultsWindow.CenterOnScreen()
+	defaultsWindow.Show()
+
+	if len(tables) > 0 {
+		tableSelect.SetSelected(tables[0])
+	}
+}
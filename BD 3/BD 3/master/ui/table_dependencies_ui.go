@@ -0,0 +1,137 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для удаления с учётом зависимостей ==========
+
+const (
+	dropRestrictOption = "RESTRICT — отказаться, если есть зависимые объекты"
+	dropCascadeOption  = "CASCADE — удалить вместе с зависимыми объектами"
+)
+
+// newDependencyTree строит дерево зависимых объектов
+func newDependencyTree(objects []operation.DependentObject) *widget.Tree {
+	children := make(map[string][]string)
+	byKey := make(map[string]operation.DependentObject, len(objects))
+	for _, obj := range objects {
+		children[obj.ParentKey] = append(children[obj.ParentKey], obj.Key)
+		byKey[obj.Key] = obj
+	}
+
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			return children[id]
+		},
+		func(id widget.TreeNodeID) bool {
+			return id == "" || len(children[id]) > 0
+		},
+		func(branch bool) fyne.CanvasObject {
+			return widget.NewLabel("dependent object")
+		},
+		func(id widget.TreeNodeID, branch bool, obj fyne.CanvasObject) {
+			dep := byKey[id]
+			text := dep.Description
+			if dep.Automatic {
+				text += " (авто)"
+			}
+			obj.(*widget.Label).SetText(text)
+		},
+	)
+	tree.OpenAllBranches()
+	return tree
+}
+
+// showDropImpactDialog показывает объекты, зависящие от удаляемого (pg_depend),
+// предлагает RESTRICT или CASCADE и вызывает drop только после явного подтверждения
+func showDropImpactDialog(ctx context.Context, pool *pgxpool.Pool, window fyne.Window,
+	kind, name string, drop func(cascade bool) error, onDropped func()) {
+
+	objects, err := operation.GetDependentObjects(ctx, pool, kind, name)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+
+	var blocking int
+	for _, obj := range objects {
+		if !obj.Automatic {
+			blocking++
+		}
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf("%s '%s': зависимых объектов — %d (из них блокируют RESTRICT — %d)",
+		kind, name, len(objects), blocking))
+	summary.Wrapping = fyne.TextWrapWord
+
+	var details fyne.CanvasObject = widget.NewLabel("Зависимых объектов нет — объект можно удалить с RESTRICT.")
+	if len(objects) > 0 {
+		details = newDependencyTree(objects)
+	}
+
+	modeRadio := widget.NewRadioGroup([]string{dropRestrictOption, dropCascadeOption}, nil)
+	modeRadio.SetSelected(dropRestrictOption)
+
+	confirmCheck := widget.NewCheck(fmt.Sprintf("Я понимаю, что будут удалены %d зависимых объектов", len(objects)), nil)
+	confirmEntry := widget.NewEntry()
+	confirmEntry.SetPlaceHolder(fmt.Sprintf("Введите '%s' для подтверждения CASCADE", name))
+	confirmCheck.Hide()
+	confirmEntry.Hide()
+
+	modeRadio.OnChanged = func(mode string) {
+		if mode == dropCascadeOption && len(objects) > 0 {
+			confirmCheck.Show()
+			confirmEntry.Show()
+		} else {
+			confirmCheck.Hide()
+			confirmEntry.Hide()
+		}
+	}
+
+	content := container.NewBorder(
+		container.NewVBox(summary, widget.NewSeparator()),
+		container.NewVBox(widget.NewSeparator(), modeRadio, confirmCheck, confirmEntry),
+		nil, nil,
+		details,
+	)
+
+	dlg := dialog.NewCustomConfirm("Удаление: "+name, "Удалить", "Отмена", content, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		cascade := modeRadio.Selected == dropCascadeOption
+		if cascade && len(objects) > 0 {
+			if !confirmCheck.Checked || strings.TrimSpace(confirmEntry.Text) != name {
+				showError(window, "CASCADE не подтверждён: отметьте флажок и введите имя объекта")
+				return
+			}
+		}
+
+		if err := drop(cascade); err != nil {
+			hint := ""
+			if !cascade && blocking > 0 {
+				hint = "\n\nОбъект используется другими объектами — выберите CASCADE, чтобы удалить их вместе с ним."
+			}
+			showError(window, "Ошибка удаления: "+err.Error()+hint)
+			return
+		}
+
+		if onDropped != nil {
+			onDropped()
+		}
+	}, window)
+
+	dlg.Resize(fyne.NewSize(700, 550))
+	dlg.Show()
+}
@@ -0,0 +1,451 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== Конструктор таблиц ==========
+
+// Источник значения столбца в конструкторе
+const (
+	valueDefault           = "DEFAULT"
+	valueGenerated         = "GENERATED (STORED)"
+	valueIdentityAlways    = "IDENTITY ALWAYS"
+	valueIdentityByDefault = "IDENTITY BY DEFAULT"
+)
+
+var designerValueKinds = []string{valueDefault, valueGenerated, valueIdentityAlways, valueIdentityByDefault}
+
+// designerColumn строка конструктора таблицы
+type designerColumn struct {
+	Name       string
+	Type       string
+	Array      bool
+	PrimaryKey bool
+	NotNull    bool
+	Unique     bool
+	ValueKind  string // один из designerValueKinds, пустое значение — DEFAULT
+	Expression string // выражение DEFAULT или GENERATED
+	Collation  string
+}
+
+// applyValueKind заполняет DEFAULT/GENERATED/IDENTITY определения столбца по строке конструктора
+func (row designerColumn) applyValueKind(col *operation.ColumnDefinition) {
+	expression := strings.TrimSpace(row.Expression)
+	switch row.ValueKind {
+	case valueGenerated:
+		col.Generated = expression
+	case valueIdentityAlways:
+		col.Identity = operation.IdentityAlways
+	case valueIdentityByDefault:
+		col.Identity = operation.IdentityByDefault
+	default:
+		col.Default = expression
+	}
+}
+
+// designerDefinitions преобразует строки конструктора в определения столбцов и ограничения таблицы.
+// Пустые строки пропускаются; составной первичный ключ выносится на уровень таблицы.
+func designerDefinitions(rows []designerColumn, tableConstraints string) ([]operation.ColumnDefinition, []string, error) {
+	var pkColumns []string
+	for _, row := range rows {
+		if row.PrimaryKey && strings.TrimSpace(row.Name) != "" {
+			pkColumns = append(pkColumns, strings.TrimSpace(row.Name))
+		}
+	}
+	compositePK := len(pkColumns) > 1
+
+	var columns []operation.ColumnDefinition
+	for i, row := range rows {
+		name := strings.TrimSpace(row.Name)
+		typ := strings.TrimSpace(row.Type)
+		if name == "" && typ == "" {
+			continue
+		}
+		if name == "" {
+			return nil, nil, fmt.Errorf("Строка %d: укажите имя столбца", i+1)
+		}
+		if typ == "" {
+			return nil, nil, fmt.Errorf("Строка %d: укажите тип столбца %s", i+1, name)
+		}
+		if row.Array {
+			typ += "[]"
+		}
+
+		if row.ValueKind == valueGenerated && strings.TrimSpace(row.Expression) == "" {
+			return nil, nil, fmt.Errorf("Строка %d: укажите выражение вычисляемого столбца %s", i+1, name)
+		}
+
+		singlePK := row.PrimaryKey && !compositePK
+		col := operation.ColumnDefinition{
+			Name:      name,
+			Type:      typ,
+			Collation: strings.TrimSpace(row.Collation),
+			NotNull:   row.NotNull && !singlePK,
+		}
+		row.applyValueKind(&col)
+		if singlePK {
+			col.Constraints = "PRIMARY KEY"
+		} else if row.Unique {
+			col.Constraints = "UNIQUE"
+		}
+
+		columns = append(columns, col)
+	}
+
+	var constraints []string
+	if compositePK {
+		constraints = append(constraints, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkColumns, ", ")))
+	}
+	for _, line := range strings.Split(tableConstraints, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), ","))
+		if line != "" {
+			constraints = append(constraints, line)
+		}
+	}
+
+	return columns, constraints, nil
+}
+
+// designerRowsFromText разбирает вставленный текст определений (построчно или тело CREATE TABLE)
+// в строки конструктора. Ограничения CHECK и REFERENCES столбца переносятся на уровень таблицы.
+func designerRowsFromText(text string) ([]designerColumn, []string, error) {
+	columns, tableConstraints, err := operation.ParseTableDefinition(text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []designerColumn
+	for _, col := range columns {
+		row := designerColumn{
+			Name:       col.Name,
+			Type:       col.Type,
+			NotNull:    col.NotNull,
+			Expression: col.Default,
+			Collation:  col.Collation,
+		}
+		switch {
+		case col.Generated != "":
+			row.ValueKind, row.Expression = valueGenerated, col.Generated
+		case col.Identity == operation.IdentityAlways:
+			row.ValueKind = valueIdentityAlways
+		case col.Identity == operation.IdentityByDefault:
+			row.ValueKind = valueIdentityByDefault
+		}
+		if strings.HasSuffix(row.Type, "[]") {
+			row.Type = strings.TrimSuffix(row.Type, "[]")
+			row.Array = true
+		}
+
+		clauses, err := operation.SplitColumnConstraints(col.Constraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, clause := range clauses {
+			// Имя ограничения сохраняем только для ограничений, переносимых в таблицу
+			prefix := ""
+			body := clause
+			if fields := strings.Fields(clause); len(fields) > 2 && strings.EqualFold(fields[0], "CONSTRAINT") {
+				prefix = fields[0] + " " + fields[1] + " "
+				body = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(clause, fields[0]), " "+fields[1]))
+			}
+			upper := strings.ToUpper(body)
+
+			switch {
+			case upper == "PRIMARY KEY" && prefix == "":
+				row.PrimaryKey = true
+			case upper == "UNIQUE" && prefix == "":
+				row.Unique = true
+			case upper == "NOT NULL":
+				row.NotNull = true
+			case upper == "PRIMARY KEY" || upper == "UNIQUE":
+				tableConstraints = append(tableConstraints, fmt.Sprintf("%s%s (%s)", prefix, body, col.Name))
+			case strings.HasPrefix(upper, "CHECK"):
+				tableConstraints = append(tableConstraints, prefix+body)
+			case strings.HasPrefix(upper, "REFERENCES"):
+				tableConstraints = append(tableConstraints, fmt.Sprintf("%sFOREIGN KEY (%s) %s", prefix, col.Name, body))
+			default:
+				return nil, nil, fmt.Errorf("Столбец %s: ограничение «%s» не поддерживается конструктором", col.Name, clause)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, tableConstraints, nil
+}
+
+// newTypePicker создаёт поле выбора типа: список известных типов с возможностью ввести свой (например, VARCHAR(50))
+func newTypePicker(options []string) *widget.SelectEntry {
+	picker := widget.NewSelectEntry(options)
+	picker.SetPlaceHolder("Тип")
+	return picker
+}
+
+// showTableDesigner открывает конструктор таблицы: строки столбцов, ограничения таблицы и предпросмотр DDL.
+// onCreated вызывается с именем таблицы после успешного создания
+func showTableDesigner(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, onCreated func(tableName string)) {
+	typeOptions, err := operation.GetColumnTypeOptions(ctx, pool)
+	if err != nil {
+		log.Printf("Ошибка получения пользовательских типов: %v", err)
+	}
+
+	designerWindow := fyne.CurrentApp().NewWindow("Конструктор таблицы")
+
+	rows := []designerColumn{
+		{Name: "id", Type: "SERIAL", PrimaryKey: true},
+		{},
+	}
+
+	tableNameEntry := widget.NewEntry()
+	tableNameEntry.SetPlaceHolder("Имя таблицы")
+
+	constraintsEntry := widget.NewMultiLineEntry()
+	constraintsEntry.SetPlaceHolder("По одному на строку, например:\nFOREIGN KEY (category_id) REFERENCES categories(id)\nCHECK (price >= 0)\nUNIQUE (name, category_id)")
+	constraintsEntry.SetMinRowsVisible(4)
+
+	previewLabel := widget.NewLabel("")
+	previewLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	updatePreview := func() {
+		columns, constraints, err := designerDefinitions(rows, constraintsEntry.Text)
+		if err != nil {
+			previewLabel.SetText("-- " + err.Error())
+			return
+		}
+		tableName := strings.TrimSpace(tableNameEntry.Text)
+		if tableName == "" {
+			tableName = "<имя_таблицы>"
+		}
+		previewLabel.SetText(operation.BuildCreateTableSQL(tableName, columns, constraints) + ";")
+	}
+
+	tableNameEntry.OnChanged = func(string) { updatePreview() }
+	constraintsEntry.OnChanged = func(string) { updatePreview() }
+
+	rowsBox := container.NewVBox()
+	var rebuildRows func()
+	rebuildRows = func() {
+		rowsBox.Objects = nil
+		for i := range rows {
+			i := i
+			row := &rows[i]
+
+			nameEntry := widget.NewEntry()
+			nameEntry.SetPlaceHolder("Имя столбца")
+			nameEntry.SetText(row.Name)
+			nameEntry.OnChanged = func(s string) {
+				row.Name = s
+				updatePreview()
+			}
+
+			typePicker := newTypePicker(typeOptions)
+			typePicker.SetText(row.Type)
+			typePicker.OnChanged = func(s string) {
+				row.Type = s
+				updatePreview()
+			}
+
+			expressionEntry := widget.NewEntry()
+			expressionEntry.SetPlaceHolder("Выражение")
+			expressionEntry.SetText(row.Expression)
+			expressionEntry.OnChanged = func(s string) {
+				row.Expression = s
+				updatePreview()
+			}
+
+			// Для IDENTITY выражение не задаётся
+			updateExpression := func() {
+				if row.ValueKind == valueIdentityAlways || row.ValueKind == valueIdentityByDefault {
+					expressionEntry.Disable()
+				} else {
+					expressionEntry.Enable()
+				}
+			}
+			valueSelect := widget.NewSelect(designerValueKinds, nil)
+			if row.ValueKind == "" {
+				row.ValueKind = valueDefault
+			}
+			valueSelect.SetSelected(row.ValueKind)
+			updateExpression()
+			valueSelect.OnChanged = func(s string) {
+				row.ValueKind = s
+				updateExpression()
+				updatePreview()
+			}
+
+			collationEntry := widget.NewEntry()
+			collationEntry.SetPlaceHolder("COLLATE")
+			collationEntry.SetText(row.Collation)
+			collationEntry.OnChanged = func(s string) {
+				row.Collation = s
+				updatePreview()
+			}
+
+			newCheck := func(label string, value *bool) *widget.Check {
+				check := widget.NewCheck(label, nil)
+				check.SetChecked(*value)
+				check.OnChanged = func(b bool) {
+					*value = b
+					updatePreview()
+				}
+				return check
+			}
+
+			upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+				if i > 0 {
+					rows[i-1], rows[i] = rows[i], rows[i-1]
+					rebuildRows()
+				}
+			})
+			downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+				if i < len(rows)-1 {
+					rows[i+1], rows[i] = rows[i], rows[i+1]
+					rebuildRows()
+				}
+			})
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				rows = append(rows[:i], rows[i+1:]...)
+				rebuildRows()
+			})
+			if i == 0 {
+				upBtn.Disable()
+			}
+			if i == len(rows)-1 {
+				downBtn.Disable()
+			}
+
+			rowsBox.Add(container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					newCheck("[]", &row.Array),
+					newCheck("PK", &row.PrimaryKey),
+					newCheck("NOT NULL", &row.NotNull),
+					newCheck("UNIQUE", &row.Unique),
+					upBtn, downBtn, removeBtn,
+				),
+				container.NewGridWithColumns(4,
+					nameEntry,
+					typePicker,
+					container.NewBorder(nil, nil, valueSelect, nil, expressionEntry),
+					collationEntry,
+				),
+			))
+		}
+		rowsBox.Refresh()
+		updatePreview()
+	}
+
+	addColumnBtn := widget.NewButtonWithIcon("Добавить столбец", theme.ContentAddIcon(), func() {
+		rows = append(rows, designerColumn{})
+		rebuildRows()
+	})
+
+	pasteBtn := widget.NewButton("📋 Вставить из текста", func() {
+		textEntry := widget.NewMultiLineEntry()
+		textEntry.TextStyle = fyne.TextStyle{Monospace: true}
+		textEntry.SetPlaceHolder("Определения столбцов, например:\nprice NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (price >= 0)\n\"Имя\" character varying(50)")
+		textEntry.SetMinRowsVisible(10)
+
+		dlg := dialog.NewCustomConfirm("Вставить определения столбцов", "Добавить", "Отмена", textEntry, func(ok bool) {
+			if !ok {
+				return
+			}
+			parsed, parsedConstraints, err := designerRowsFromText(textEntry.Text)
+			if err != nil {
+				showError(designerWindow, "Ошибка разбора: "+err.Error())
+				return
+			}
+
+			// Пустые строки конструктора заменяем вставленными столбцами
+			var kept []designerColumn
+			for _, row := range rows {
+				if strings.TrimSpace(row.Name) != "" || strings.TrimSpace(row.Type) != "" {
+					kept = append(kept, row)
+				}
+			}
+			rows = append(kept, parsed...)
+
+			if len(parsedConstraints) > 0 {
+				text := strings.TrimRight(constraintsEntry.Text, "\n")
+				if text != "" {
+					text += "\n"
+				}
+				constraintsEntry.SetText(text + strings.Join(parsedConstraints, "\n"))
+			}
+			rebuildRows()
+		}, designerWindow)
+		dlg.Resize(fyne.NewSize(650, 400))
+		dlg.Show()
+	})
+
+	createBtn := widget.NewButton("Создать", func() {
+		tableName := strings.TrimSpace(tableNameEntry.Text)
+		if tableName == "" {
+			showError(designerWindow, "Укажите имя таблицы")
+			return
+		}
+
+		columns, constraints, err := designerDefinitions(rows, constraintsEntry.Text)
+		if err != nil {
+			showError(designerWindow, err.Error())
+			return
+		}
+		if len(columns) == 0 {
+			showError(designerWindow, "Необходимо указать хотя бы один столбец")
+			return
+		}
+
+		if err := operation.CreateTablesWithTypesAdvanced(ctx, pool, tableName, columns, constraints); err != nil {
+			showError(designerWindow, "Ошибка создания таблицы: "+err.Error())
+			return
+		}
+
+		designerWindow.Close()
+		showInfo(window, fmt.Sprintf("Таблица '%s' успешно создана!", tableName))
+		if onCreated != nil {
+			onCreated(tableName)
+		}
+	})
+	createBtn.Importance = widget.HighImportance
+
+	cancelBtn := widget.NewButton("Отмена", func() {
+		designerWindow.Close()
+	})
+
+	columnsPanel := container.NewBorder(
+		widget.NewLabel("Столбцы (имя, тип, DEFAULT/GENERATED/IDENTITY, COLLATE; [] — массив):"),
+		container.NewHBox(addColumnBtn, pasteBtn),
+		nil, nil,
+		container.NewVScroll(rowsBox),
+	)
+
+	bottom := container.NewHSplit(
+		container.NewBorder(widget.NewLabel("Ограничения таблицы:"), nil, nil, nil, constraintsEntry),
+		container.NewBorder(widget.NewLabel("Предпросмотр DDL:"), nil, nil, nil, container.NewScroll(previewLabel)),
+	)
+
+	split := container.NewVSplit(columnsPanel, bottom)
+	split.Offset = 0.55
+
+	designerWindow.SetContent(container.NewBorder(
+		widget.NewForm(widget.NewFormItem("Имя таблицы", tableNameEntry)),
+		container.NewHBox(createBtn, cancelBtn),
+		nil, nil,
+		split,
+	))
+
+	rebuildRows()
+
+	designerWindow.Resize(fyne.NewSize(1100, 700))
+	designerWindow.CenterOnScreen()
+	designerWindow.Show()
+}
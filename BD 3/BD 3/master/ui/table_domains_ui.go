@@ -0,0 +1,337 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для доменов ==========
+
+// getCustomTypeKind возвращает вид пользовательского типа (ENUM, COMPOSITE, DOMAIN, RANGE) или пустую строку
+func getCustomTypeKind(ctx context.Context, pool *pgxpool.Pool, typeName string) string {
+	types, err := operation.GetCustomTypes(ctx, pool)
+	if err != nil {
+		return ""
+	}
+	for _, t := range types {
+		if t["type_name"] == typeName {
+			kind, _ := t["type_kind"].(string)
+			return kind
+		}
+	}
+	return ""
+}
+
+// getCustomTypeNames возвращает имена пользовательских типов указанного вида (ENUM, COMPOSITE, DOMAIN, RANGE)
+func getCustomTypeNames(ctx context.Context, pool *pgxpool.Pool, kind string) ([]string, error) {
+	types, err := operation.GetCustomTypes(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, t := range types {
+		if t["type_kind"] == kind {
+			names = append(names, t["type_name"].(string))
+		}
+	}
+	return names, nil
+}
+
+// UICreateDomain открывает окно создания домена: базовый тип, DEFAULT, NOT NULL
+// и список именованных ограничений CHECK с предпросмотром SQL
+func UICreateDomain(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	typeOptions, err := operation.GetColumnTypeOptions(ctx, pool)
+	if err != nil {
+		log.Printf("Ошибка получения пользовательских типов: %v", err)
+	}
+
+	domainWindow := fyne.CurrentApp().NewWindow("Создать домен (DOMAIN)")
+
+	checks := []operation.DomainConstraint{{}}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя домена, например: positive_price")
+	baseTypePicker := newTypePicker(typeOptions)
+	baseTypePicker.SetPlaceHolder("Базовый тип, например: NUMERIC(10, 2)")
+	defaultEntry := widget.NewEntry()
+	defaultEntry.SetPlaceHolder("Необязательно, например: 0")
+	notNullCheck := widget.NewCheck("NOT NULL", nil)
+
+	previewLabel := widget.NewLabel("")
+	previewLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	definition := func() operation.DomainDefinition {
+		domain := operation.DomainDefinition{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			BaseType: strings.TrimSpace(baseTypePicker.Text),
+			Default:  defaultEntry.Text,
+			NotNull:  notNullCheck.Checked,
+		}
+		for _, check := range checks {
+			check.Name = strings.TrimSpace(check.Name)
+			check.Expression = strings.TrimSpace(check.Expression)
+			if check.Name != "" || check.Expression != "" {
+				domain.Checks = append(domain.Checks, check)
+			}
+		}
+		return domain
+	}
+
+	updatePreview := func() {
+		domain := definition()
+		if domain.Name == "" {
+			domain.Name = "<имя_домена>"
+		}
+		if domain.BaseType == "" {
+			domain.BaseType = "<базовый_тип>"
+		}
+		previewLabel.SetText(operation.BuildCreateDomainSQL(domain) + ";")
+	}
+
+	nameEntry.OnChanged = func(string) { updatePreview() }
+	baseTypePicker.OnChanged = func(string) { updatePreview() }
+	defaultEntry.OnChanged = func(string) { updatePreview() }
+	notNullCheck.OnChanged = func(bool) { updatePreview() }
+
+	checksBox := container.NewVBox()
+	var rebuildChecks func()
+	rebuildChecks = func() {
+		checksBox.Objects = nil
+		for i := range checks {
+			i := i
+			check := &checks[i]
+
+			checkNameEntry := widget.NewEntry()
+			checkNameEntry.SetPlaceHolder("Имя ограничения")
+			checkNameEntry.SetText(check.Name)
+			checkNameEntry.OnChanged = func(s string) {
+				check.Name = s
+				updatePreview()
+			}
+
+			expressionEntry := widget.NewEntry()
+			expressionEntry.SetPlaceHolder("Условие, например: VALUE > 0")
+			expressionEntry.SetText(check.Expression)
+			expressionEntry.OnChanged = func(s string) {
+				check.Expression = s
+				updatePreview()
+			}
+
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				checks = append(checks[:i], checks[i+1:]...)
+				rebuildChecks()
+			})
+
+			checksBox.Add(container.NewBorder(nil, nil, nil, removeBtn,
+				container.NewGridWithColumns(2, checkNameEntry, expressionEntry)))
+		}
+		checksBox.Refresh()
+		updatePreview()
+	}
+
+	addCheckBtn := widget.NewButtonWithIcon("Добавить CHECK", theme.ContentAddIcon(), func() {
+		checks = append(checks, operation.DomainConstraint{})
+		rebuildChecks()
+	})
+
+	createBtn := widget.NewButton("Создать", func() {
+		domain := definition()
+		if domain.Name == "" {
+			showError(domainWindow, "Укажите имя домена")
+			return
+		}
+		if err := operation.CreateDomain(ctx, pool, domain); err != nil {
+			showError(domainWindow, "Ошибка создания домена: "+err.Error())
+			return
+		}
+		showInfo(window, fmt.Sprintf("Домен '%s' успешно создан!", domain.Name))
+		domainWindow.Close()
+	})
+	createBtn.Importance = widget.HighImportance
+
+	form := widget.NewForm(
+		widget.NewFormItem("Имя домена", nameEntry),
+		widget.NewFormItem("Базовый тип", baseTypePicker),
+		widget.NewFormItem("DEFAULT", defaultEntry),
+		widget.NewFormItem("", notNullCheck),
+	)
+
+	content := container.NewVBox(
+		form,
+		widget.NewSeparator(),
+		widget.NewLabel("Ограничения CHECK (значение домена обозначается VALUE):"),
+		checksBox,
+		addCheckBtn,
+		widget.NewSeparator(),
+		widget.NewLabel("Предпросмотр:"),
+		previewLabel,
+	)
+
+	rebuildChecks()
+
+	domainWindow.SetContent(container.NewBorder(nil, container.NewHBox(createBtn), nil, nil, container.NewScroll(content)))
+	domainWindow.Resize(fyne.NewSize(750, 550))
+	domainWindow.CenterOnScreen()
+	domainWindow.Show()
+}
+
+// UIEditDomain предлагает выбрать домен и открывает редактор его ограничений
+func UIEditDomain(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	domains, err := getCustomTypeNames(ctx, pool, "DOMAIN")
+	if err != nil {
+		showError(window, "Ошибка получения доменов: "+err.Error())
+		return
+	}
+	if len(domains) == 0 {
+		showInfo(window, "В схеме public нет доменов")
+		return
+	}
+
+	domainSelect := widget.NewSelect(domains, nil)
+	domainSelect.SetSelected(domains[0])
+
+	dialog.ShowCustomConfirm("Изменить домен", "Открыть", "Отмена",
+		widget.NewForm(widget.NewFormItem("Домен", domainSelect)), func(ok bool) {
+			if ok && domainSelect.Selected != "" {
+				showDomainEditor(ctx, pool, window, domainSelect.Selected)
+			}
+		}, window)
+}
+
+// showDomainEditor открывает окно изменения DEFAULT, NOT NULL и ограничений CHECK домена
+func showDomainEditor(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, domainName string) {
+	editorWindow := fyne.CurrentApp().NewWindow("Домен: " + domainName)
+
+	baseTypeLabel := widget.NewLabel("")
+	defaultEntry := widget.NewEntry()
+	defaultEntry.SetPlaceHolder("Пусто — удалить DEFAULT")
+	notNullCheck := widget.NewCheck("NOT NULL", nil)
+	checksBox := container.NewVBox()
+
+	var reload func()
+
+	dropCheck := func(check operation.DomainConstraint) {
+		dialog.ShowConfirm("Удаление ограничения",
+			fmt.Sprintf("Удалить ограничение %s домена %s?", check.Name, domainName),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := operation.DropDomainConstraint(ctx, pool, domainName, check.Name); err != nil {
+					showError(editorWindow, err.Error())
+					return
+				}
+				reload()
+			}, editorWindow)
+	}
+
+	reload = func() {
+		domain, err := operation.GetDomainInfo(ctx, pool, domainName)
+		if err != nil {
+			showError(editorWindow, err.Error())
+			return
+		}
+
+		baseTypeLabel.SetText(domain.BaseType)
+		defaultEntry.SetText(domain.Default)
+		notNullCheck.SetChecked(domain.NotNull)
+
+		checksBox.Objects = nil
+		if len(domain.Checks) == 0 {
+			checksBox.Add(widget.NewLabel("Ограничений CHECK нет"))
+		}
+		for _, check := range domain.Checks {
+			check := check
+			label := widget.NewLabel(fmt.Sprintf("%s: CHECK (%s)", check.Name, check.Expression))
+			label.Wrapping = fyne.TextWrapWord
+
+			buttons := container.NewHBox()
+			if !check.Validated {
+				label.SetText(label.Text + "  [NOT VALID]")
+				buttons.Add(widget.NewButton("Проверить данные", func() {
+					if err := operation.ValidateDomainConstraint(ctx, pool, domainName, check.Name); err != nil {
+						showError(editorWindow, err.Error())
+						return
+					}
+					reload()
+				}))
+			}
+			buttons.Add(widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				dropCheck(check)
+			}))
+
+			checksBox.Add(container.NewBorder(nil, nil, nil, buttons, label))
+		}
+		checksBox.Refresh()
+	}
+
+	applyDefaultBtn := widget.NewButton("Применить", func() {
+		if err := operation.SetDomainDefault(ctx, pool, domainName, defaultEntry.Text); err != nil {
+			showError(editorWindow, err.Error())
+		}
+		reload()
+	})
+
+	applyNotNullBtn := widget.NewButton("Применить", func() {
+		// SET NOT NULL завершится ошибкой, если в столбцах этого домена уже есть NULL
+		if err := operation.SetDomainNotNull(ctx, pool, domainName, notNullCheck.Checked); err != nil {
+			showError(editorWindow, err.Error())
+		}
+		reload()
+	})
+
+	newNameEntry := widget.NewEntry()
+	newNameEntry.SetPlaceHolder("Имя ограничения")
+	newExpressionEntry := widget.NewEntry()
+	newExpressionEntry.SetPlaceHolder("Условие, например: VALUE <> ''")
+	notValidCheck := widget.NewCheck("NOT VALID (не проверять существующие данные)", nil)
+
+	addCheckBtn := widget.NewButtonWithIcon("Добавить CHECK", theme.ContentAddIcon(), func() {
+		check := operation.DomainConstraint{
+			Name:       strings.TrimSpace(newNameEntry.Text),
+			Expression: strings.TrimSpace(newExpressionEntry.Text),
+		}
+		if err := operation.AddDomainConstraint(ctx, pool, domainName, check, notValidCheck.Checked); err != nil {
+			showError(editorWindow, err.Error())
+			return
+		}
+		newNameEntry.SetText("")
+		newExpressionEntry.SetText("")
+		reload()
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem("Базовый тип", baseTypeLabel),
+		widget.NewFormItem("DEFAULT", container.NewBorder(nil, nil, nil, applyDefaultBtn, defaultEntry)),
+		widget.NewFormItem("", container.NewBorder(nil, nil, nil, applyNotNullBtn, notNullCheck)),
+	)
+
+	content := container.NewVBox(
+		form,
+		widget.NewSeparator(),
+		widget.NewLabel("Ограничения CHECK:"),
+		checksBox,
+		widget.NewSeparator(),
+		widget.NewLabel("Новое ограничение:"),
+		container.NewGridWithColumns(2, newNameEntry, newExpressionEntry),
+		notValidCheck,
+		addCheckBtn,
+	)
+
+	reload()
+
+	editorWindow.SetContent(container.NewScroll(content))
+	editorWindow.Resize(fyne.NewSize(700, 500))
+	editorWindow.CenterOnScreen()
+	editorWindow.Show()
+}
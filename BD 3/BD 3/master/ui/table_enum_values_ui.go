@@ -0,0 +1,246 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для значений ENUM ==========
+
+const (
+	enumPositionEnd    = "В конец"
+	enumPositionBefore = "BEFORE"
+	enumPositionAfter  = "AFTER"
+	enumReplacementNil = "NULL"
+)
+
+// UIManageEnumValues открывает окно управления значениями ENUM: добавление с позицией,
+// переименование, изменение порядка и удаление с переносом данных на новый тип
+func UIManageEnumValues(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	enums, err := getCustomTypeNames(ctx, pool, "ENUM")
+	if err != nil {
+		showError(window, "Ошибка получения типов: "+err.Error())
+		return
+	}
+	if len(enums) == 0 {
+		showInfo(window, "В схеме public нет ENUM типов")
+		return
+	}
+
+	enumWindow := fyne.CurrentApp().NewWindow("Значения ENUM")
+
+	var values []string // текущий порядок в окне, может отличаться от БД до «Применить порядок»
+	orderChanged := false
+	valuesBox := container.NewVBox()
+	typeSelect := widget.NewSelect(enums, nil)
+
+	newValueEntry := widget.NewEntry()
+	newValueEntry.SetPlaceHolder("Новое значение")
+	positionSelect := widget.NewSelect([]string{enumPositionEnd, enumPositionBefore, enumPositionAfter}, nil)
+	positionSelect.SetSelected(enumPositionEnd)
+	neighborSelect := widget.NewSelect(nil, nil)
+	neighborSelect.PlaceHolder = "Значение"
+	ifNotExistsCheck := widget.NewCheck("IF NOT EXISTS", nil)
+	applyOrderBtn := widget.NewButton("Применить порядок", nil)
+
+	var reload, rebuildValues func()
+
+	renameValue := func(value string) {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(value)
+		dialog.ShowCustomConfirm("Переименовать значение "+value, "Переименовать", "Отмена",
+			widget.NewForm(widget.NewFormItem("Новое имя", nameEntry)), func(ok bool) {
+				if !ok {
+					return
+				}
+				err := operation.RenameEnumValue(ctx, pool, typeSelect.Selected, value, strings.TrimSpace(nameEntry.Text))
+				if err != nil {
+					showError(enumWindow, err.Error())
+					return
+				}
+				reload()
+			}, enumWindow)
+	}
+
+	removeValue := func(value string) {
+		typeName := typeSelect.Selected
+		columns, err := operation.GetEnumColumns(ctx, pool, typeName)
+		if err != nil {
+			showError(enumWindow, err.Error())
+			return
+		}
+
+		var usage []string
+		for _, col := range columns {
+			counts, err := operation.CountEnumValueRows(ctx, pool, col)
+			if err != nil {
+				showError(enumWindow, err.Error())
+				return
+			}
+			usage = append(usage, fmt.Sprintf("%s.%s — строк со значением: %d", col.Table, col.Column, counts[value]))
+		}
+		if len(usage) == 0 {
+			usage = append(usage, "Тип не используется в столбцах таблиц")
+		}
+
+		replacements := []string{enumReplacementNil}
+		for _, v := range values {
+			if v != value {
+				replacements = append(replacements, v)
+			}
+		}
+		replacementSelect := widget.NewSelect(replacements, nil)
+		replacementSelect.SetSelected(enumReplacementNil)
+
+		usageLabel := widget.NewLabel(strings.Join(usage, "\n"))
+		usageLabel.Wrapping = fyne.TextWrapWord
+		content := container.NewVBox(
+			widget.NewLabel(fmt.Sprintf("PostgreSQL не умеет удалять значения ENUM. Будет создан тип-замена без '%s',\n"+
+				"все столбцы будут переведены на него, затем старый тип удалён — в одной транзакции.", value)),
+			usageLabel,
+			widget.NewForm(widget.NewFormItem("Заменить на", replacementSelect)),
+		)
+
+		dialog.ShowCustomConfirm("Удалить значение "+value, "Удалить", "Отмена", content, func(ok bool) {
+			if !ok {
+				return
+			}
+			replacement := replacementSelect.Selected
+			if replacement == enumReplacementNil {
+				replacement = ""
+			}
+			migrated, err := operation.RemoveEnumValue(ctx, pool, typeName, value, replacement)
+			if err != nil {
+				showError(enumWindow, err.Error())
+				return
+			}
+			showInfo(enumWindow, fmt.Sprintf("Значение '%s' удалено, перенесено столбцов: %d", value, migrated))
+			reload()
+		}, enumWindow)
+	}
+
+	rebuildValues = func() {
+		valuesBox.Objects = nil
+		for i, value := range values {
+			i, value := i, value
+
+			upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+				values[i-1], values[i] = values[i], values[i-1]
+				orderChanged = true
+				rebuildValues()
+			})
+			downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+				values[i+1], values[i] = values[i], values[i+1]
+				orderChanged = true
+				rebuildValues()
+			})
+			if i == 0 {
+				upBtn.Disable()
+			}
+			if i == len(values)-1 {
+				downBtn.Disable()
+			}
+
+			valuesBox.Add(container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					upBtn, downBtn,
+					widget.NewButton("Переименовать", func() { renameValue(value) }),
+					widget.NewButtonWithIcon("", theme.DeleteIcon(), func() { removeValue(value) }),
+				),
+				widget.NewLabel(value),
+			))
+		}
+		valuesBox.Refresh()
+
+		if orderChanged {
+			applyOrderBtn.Enable()
+		} else {
+			applyOrderBtn.Disable()
+		}
+	}
+
+	reload = func() {
+		var err error
+		values, err = operation.GetEnumValues(ctx, pool, typeSelect.Selected)
+		if err != nil {
+			showError(enumWindow, err.Error())
+			return
+		}
+		orderChanged = false
+		neighborSelect.Options = values
+		neighborSelect.ClearSelected()
+		neighborSelect.Refresh()
+		rebuildValues()
+	}
+	typeSelect.OnChanged = func(string) { reload() }
+
+	applyOrderBtn.OnTapped = func() {
+		typeName := typeSelect.Selected
+		order := append([]string(nil), values...)
+		dialog.ShowConfirm("Изменение порядка",
+			fmt.Sprintf("Порядок значений ENUM нельзя изменить на месте: тип %s будет пересоздан,\n"+
+				"а все столбцы переведены на него в одной транзакции. Продолжить?", typeName),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				migrated, err := operation.ReorderEnumValues(ctx, pool, typeName, order)
+				if err != nil {
+					showError(enumWindow, err.Error())
+					return
+				}
+				showInfo(enumWindow, fmt.Sprintf("Порядок значений изменён, перенесено столбцов: %d", migrated))
+				reload()
+			}, enumWindow)
+	}
+
+	addValueBtn := widget.NewButtonWithIcon("Добавить значение", theme.ContentAddIcon(), func() {
+		var position operation.EnumValuePosition
+		if positionSelect.Selected != enumPositionEnd {
+			if neighborSelect.Selected == "" {
+				showError(enumWindow, "Выберите значение, относительно которого добавить новое")
+				return
+			}
+			position = operation.EnumValuePosition{
+				Neighbor: neighborSelect.Selected,
+				After:    positionSelect.Selected == enumPositionAfter,
+			}
+		}
+		err := operation.AddEnumValue(ctx, pool, typeSelect.Selected, strings.TrimSpace(newValueEntry.Text),
+			position, ifNotExistsCheck.Checked)
+		if err != nil {
+			showError(enumWindow, err.Error())
+			return
+		}
+		newValueEntry.SetText("")
+		reload()
+	})
+
+	content := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("ENUM тип", typeSelect)),
+		widget.NewSeparator(),
+		widget.NewLabel("Значения (в порядке сортировки):"),
+		valuesBox,
+		applyOrderBtn,
+		widget.NewSeparator(),
+		widget.NewLabel("Новое значение:"),
+		container.NewGridWithColumns(3, newValueEntry, positionSelect, neighborSelect),
+		container.NewHBox(ifNotExistsCheck, addValueBtn),
+	)
+
+	typeSelect.SetSelected(enums[0])
+
+	enumWindow.SetContent(container.NewScroll(content))
+	enumWindow.Resize(fyne.NewSize(700, 550))
+	enumWindow.CenterOnScreen()
+	enumWindow.Show()
+}
@@ -0,0 +1,500 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== ER-диаграмма ==========
+
+// Размеры элементов диаграммы в координатах модели (до масштабирования)
+const (
+	erTextSize     = 12
+	erHeaderHeight = 26
+	erRowHeight    = 18
+	erPadding      = 8
+	erMarkerWidth  = 28
+	erLayerGap     = 110
+	erBoxGap       = 36
+	erMinZoom      = 0.2
+	erMaxZoom      = 4
+)
+
+// erBox положение и размер таблицы на диаграмме
+type erBox struct {
+	Pos  fyne.Position
+	Size fyne.Size
+}
+
+var (
+	_ fyne.Tappable   = (*erDiagram)(nil)
+	_ fyne.Draggable  = (*erDiagram)(nil)
+	_ fyne.Scrollable = (*erDiagram)(nil)
+)
+
+// erDiagram виджет ER-диаграммы: таблицы, столбцы с PK/FK и связи между ними
+type erDiagram struct {
+	widget.BaseWidget
+
+	model  *operation.SchemaModel
+	boxes  map[string]*erBox
+	zoom   float32
+	offset fyne.Position
+
+	dragTarget string // таблица, которую перетаскивают ("" — сдвиг всей диаграммы)
+	dragging   bool
+
+	onOpen func(table string)
+}
+
+// newERDiagram создаёт диаграмму и выполняет автоматическую раскладку
+func newERDiagram(model *operation.SchemaModel, onOpen func(table string)) *erDiagram {
+	d := &erDiagram{
+		model:  model,
+		zoom:   1,
+		offset: fyne.NewPos(20, 20),
+		onOpen: onOpen,
+	}
+	d.autoLayout()
+	d.ExtendBaseWidget(d)
+	return d
+}
+
+// columnMarker возвращает отметку ключа столбца
+func columnMarker(col operation.SchemaColumn) string {
+	switch {
+	case col.PrimaryKey && col.ForeignKey:
+		return "PF"
+	case col.PrimaryKey:
+		return "PK"
+	case col.ForeignKey:
+		return "FK"
+	case col.Unique:
+		return "U"
+	}
+	return ""
+}
+
+// columnLabel возвращает подпись столбца
+func columnLabel(col operation.SchemaColumn) string {
+	text := col.Name + ": " + col.Type
+	if col.NotNull && !col.PrimaryKey {
+		text += " *"
+	}
+	return text
+}
+
+// autoLayout раскладывает таблицы по слоям (SchemaLayers): таблицы, на которые ссылаются, — левее
+func (d *erDiagram) autoLayout() {
+	d.boxes = make(map[string]*erBox, len(d.model.Tables))
+
+	// Размеры блоков
+	for _, t := range d.model.Tables {
+		width := fyne.MeasureText(t.Name, erTextSize, fyne.TextStyle{Bold: true}).Width
+		for _, col := range t.Columns {
+			w := fyne.MeasureText(columnLabel(col), erTextSize, fyne.TextStyle{}).Width + erMarkerWidth
+			if w > width {
+				width = w
+			}
+		}
+		d.boxes[t.Name] = &erBox{Size: fyne.NewSize(width+2*erPadding,
+			erHeaderHeight+float32(len(t.Columns))*erRowHeight+erPadding/2)}
+	}
+
+	x := float32(0)
+	for _, layer := range operation.SchemaLayers(d.model) {
+		y := float32(0)
+		maxWidth := float32(0)
+		for _, name := range layer {
+			box := d.boxes[name]
+			box.Pos = fyne.NewPos(x, y)
+			y += box.Size.Height + erBoxGap
+			if box.Size.Width > maxWidth {
+				maxWidth = box.Size.Width
+			}
+		}
+		x += maxWidth + erLayerGap
+	}
+}
+
+// toScreen переводит координаты модели в координаты виджета
+func (d *erDiagram) toScreen(p fyne.Position) fyne.Position {
+	return fyne.NewPos(d.offset.X+p.X*d.zoom, d.offset.Y+p.Y*d.zoom)
+}
+
+// toModel переводит координаты виджета в координаты модели
+func (d *erDiagram) toModel(p fyne.Position) fyne.Position {
+	return fyne.NewPos((p.X-d.offset.X)/d.zoom, (p.Y-d.offset.Y)/d.zoom)
+}
+
+// tableAt возвращает таблицу под точкой виджета
+func (d *erDiagram) tableAt(p fyne.Position) string {
+	m := d.toModel(p)
+	for _, t := range d.model.Tables {
+		box := d.boxes[t.Name]
+		if m.X >= box.Pos.X && m.X <= box.Pos.X+box.Size.Width &&
+			m.Y >= box.Pos.Y && m.Y <= box.Pos.Y+box.Size.Height {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+// zoomAt масштабирует диаграмму относительно точки виджета
+func (d *erDiagram) zoomAt(p fyne.Position, factor float32) {
+	newZoom := d.zoom * factor
+	if newZoom < erMinZoom {
+		newZoom = erMinZoom
+	}
+	if newZoom > erMaxZoom {
+		newZoom = erMaxZoom
+	}
+	m := d.toModel(p)
+	d.zoom = newZoom
+	d.offset = fyne.NewPos(p.X-m.X*d.zoom, p.Y-m.Y*d.zoom)
+	d.Refresh()
+}
+
+// ZoomIn увеличивает масштаб относительно центра
+func (d *erDiagram) ZoomIn() {
+	d.zoomAt(fyne.NewPos(d.Size().Width/2, d.Size().Height/2), 1.25)
+}
+
+// ZoomOut уменьшает масштаб относительно центра
+func (d *erDiagram) ZoomOut() {
+	d.zoomAt(fyne.NewPos(d.Size().Width/2, d.Size().Height/2), 1/1.25)
+}
+
+// FitToView подбирает масштаб и сдвиг так, чтобы была видна вся диаграмма
+func (d *erDiagram) FitToView() {
+	if len(d.boxes) == 0 || d.Size().Width == 0 {
+		return
+	}
+	var maxX, maxY float32
+	for _, box := range d.boxes {
+		if r := box.Pos.X + box.Size.Width; r > maxX {
+			maxX = r
+		}
+		if b := box.Pos.Y + box.Size.Height; b > maxY {
+			maxY = b
+		}
+	}
+	const margin = 20
+	zoomX := (d.Size().Width - 2*margin) / maxX
+	zoomY := (d.Size().Height - 2*margin) / maxY
+	d.zoom = zoomX
+	if zoomY < d.zoom {
+		d.zoom = zoomY
+	}
+	if d.zoom > 1.5 {
+		d.zoom = 1.5
+	}
+	if d.zoom < erMinZoom {
+		d.zoom = erMinZoom
+	}
+	d.offset = fyne.NewPos(margin, margin)
+	d.Refresh()
+}
+
+// ResetLayout заново раскладывает таблицы
+func (d *erDiagram) ResetLayout() {
+	d.autoLayout()
+	d.FitToView()
+}
+
+// Scrolled масштабирует диаграмму колесом мыши
+func (d *erDiagram) Scrolled(ev *fyne.ScrollEvent) {
+	if ev.Scrolled.DY > 0 {
+		d.zoomAt(ev.Position, 1.1)
+	} else if ev.Scrolled.DY < 0 {
+		d.zoomAt(ev.Position, 1/1.1)
+	}
+}
+
+// Dragged перемещает таблицу (если перетаскивание началось на ней) или всю диаграмму
+func (d *erDiagram) Dragged(ev *fyne.DragEvent) {
+	if !d.dragging {
+		d.dragging = true
+		start := fyne.NewPos(ev.Position.X-ev.Dragged.DX, ev.Position.Y-ev.Dragged.DY)
+		d.dragTarget = d.tableAt(start)
+	}
+
+	if d.dragTarget != "" {
+		box := d.boxes[d.dragTarget]
+		box.Pos = fyne.NewPos(box.Pos.X+ev.Dragged.DX/d.zoom, box.Pos.Y+ev.Dragged.DY/d.zoom)
+	} else {
+		d.offset = fyne.NewPos(d.offset.X+ev.Dragged.DX, d.offset.Y+ev.Dragged.DY)
+	}
+	d.Refresh()
+}
+
+// DragEnd завершает перетаскивание
+func (d *erDiagram) DragEnd() {
+	d.dragging = false
+	d.dragTarget = ""
+}
+
+// Tapped открывает таблицу под курсором
+func (d *erDiagram) Tapped(ev *fyne.PointEvent) {
+	if name := d.tableAt(ev.Position); name != "" && d.onOpen != nil {
+		d.onOpen(name)
+	}
+}
+
+// MinSize минимальный размер области диаграммы
+func (d *erDiagram) MinSize() fyne.Size {
+	return fyne.NewSize(300, 200)
+}
+
+// CreateRenderer создаёт отрисовщик диаграммы
+func (d *erDiagram) CreateRenderer() fyne.WidgetRenderer {
+	r := &erDiagramRenderer{
+		diagram:    d,
+		background: canvas.NewRectangle(theme.Color(theme.ColorNameBackground)),
+		content:    container.NewWithoutLayout(),
+	}
+	r.Refresh()
+	return r
+}
+
+// erDiagramRenderer перестраивает графические объекты диаграммы при каждом обновлении
+type erDiagramRenderer struct {
+	diagram    *erDiagram
+	background *canvas.Rectangle
+	content    *fyne.Container
+}
+
+func (r *erDiagramRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+}
+
+func (r *erDiagramRenderer) MinSize() fyne.Size {
+	return r.diagram.MinSize()
+}
+
+func (r *erDiagramRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.background, r.content}
+}
+
+func (r *erDiagramRenderer) Destroy() {}
+
+func (r *erDiagramRenderer) Refresh() {
+	d := r.diagram
+	var objects []fyne.CanvasObject
+
+	// Сначала связи, чтобы блоки таблиц рисовались поверх них
+	for _, rel := range d.model.Relations {
+		objects = append(objects, r.relationObjects(rel)...)
+	}
+	for _, t := range d.model.Tables {
+		objects = append(objects, r.tableObjects(t)...)
+	}
+
+	r.content.Objects = objects
+	r.background.FillColor = theme.Color(theme.ColorNameBackground)
+	r.background.Refresh()
+	r.content.Refresh()
+}
+
+// newERText создаёт масштабированный текст в координатах модели
+func (r *erDiagramRenderer) newERText(text string, pos fyne.Position, c color.Color, style fyne.TextStyle) *canvas.Text {
+	d := r.diagram
+	t := canvas.NewText(text, c)
+	t.TextSize = erTextSize * d.zoom
+	t.TextStyle = style
+	t.Move(d.toScreen(pos))
+	return t
+}
+
+// tableObjects рисует блок таблицы
+func (r *erDiagramRenderer) tableObjects(t operation.SchemaTable) []fyne.CanvasObject {
+	d := r.diagram
+	box := d.boxes[t.Name]
+
+	body := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	body.StrokeColor = theme.Color(theme.ColorNamePrimary)
+	body.StrokeWidth = 1.5
+	body.CornerRadius = 4 * d.zoom
+	body.Move(d.toScreen(box.Pos))
+	body.Resize(fyne.NewSize(box.Size.Width*d.zoom, box.Size.Height*d.zoom))
+
+	header := canvas.NewRectangle(theme.Color(theme.ColorNameButton))
+	header.CornerRadius = 4 * d.zoom
+	header.Move(d.toScreen(box.Pos))
+	header.Resize(fyne.NewSize(box.Size.Width*d.zoom, erHeaderHeight*d.zoom))
+
+	objects := []fyne.CanvasObject{body, header,
+		r.newERText(t.Name, fyne.NewPos(box.Pos.X+erPadding, box.Pos.Y+5),
+			theme.Color(theme.ColorNameForeground), fyne.TextStyle{Bold: true}),
+	}
+
+	pkColor := color.NRGBA{R: 0xFF, G: 0xC1, B: 0x07, A: 0xFF}
+	fkColor := theme.Color(theme.ColorNamePrimary)
+	for i, col := range t.Columns {
+		y := box.Pos.Y + erHeaderHeight + float32(i)*erRowHeight
+		if marker := columnMarker(col); marker != "" {
+			markerColor := fkColor
+			if col.PrimaryKey {
+				markerColor = pkColor
+			}
+			objects = append(objects, r.newERText(marker, fyne.NewPos(box.Pos.X+erPadding, y),
+				markerColor, fyne.TextStyle{Bold: true, Monospace: true}))
+		}
+		objects = append(objects, r.newERText(columnLabel(col), fyne.NewPos(box.Pos.X+erPadding+erMarkerWidth, y),
+			theme.Color(theme.ColorNameForeground), fyne.TextStyle{}))
+	}
+	return objects
+}
+
+// columnY возвращает вертикальную координату середины строки столбца
+func (d *erDiagram) columnY(table string, columns []string) float32 {
+	box := d.boxes[table]
+	t := d.model.Table(table)
+	if t != nil && len(columns) > 0 {
+		for i, col := range t.Columns {
+			if col.Name == columns[0] {
+				return box.Pos.Y + erHeaderHeight + (float32(i)+0.5)*erRowHeight
+			}
+		}
+	}
+	return box.Pos.Y + erHeaderHeight/2
+}
+
+// newERLine создаёт отрезок в координатах модели
+func (r *erDiagramRenderer) newERLine(from, to fyne.Position, c color.Color) *canvas.Line {
+	d := r.diagram
+	line := canvas.NewLine(c)
+	line.StrokeWidth = 1.5
+	line.Position1 = d.toScreen(from)
+	line.Position2 = d.toScreen(to)
+	return line
+}
+
+// relationObjects рисует связь: ломаная от внешнего ключа к первичному, «воронья лапка» на стороне многих
+func (r *erDiagramRenderer) relationObjects(rel operation.SchemaRelation) []fyne.CanvasObject {
+	d := r.diagram
+	src, dst := d.boxes[rel.FromTable], d.boxes[rel.ToTable]
+	if src == nil || dst == nil {
+		return nil
+	}
+
+	lineColor := theme.Color(theme.ColorNameForeground)
+	fromY := d.columnY(rel.FromTable, rel.FromColumns)
+	toY := d.columnY(rel.ToTable, rel.ToColumns)
+
+	var from, to fyne.Position
+	var points []fyne.Position
+	dir := float32(-1) // направление линии от таблицы с FK
+
+	if rel.FromTable == rel.ToTable {
+		// Ссылка на себя — петля справа от блока
+		right := src.Pos.X + src.Size.Width
+		from = fyne.NewPos(right, fromY)
+		to = fyne.NewPos(right, toY)
+		loopX := right + 24
+		points = []fyne.Position{from, fyne.NewPos(loopX, fromY), fyne.NewPos(loopX, toY), to}
+		dir = 1
+	} else {
+		if src.Pos.X+src.Size.Width/2 < dst.Pos.X+dst.Size.Width/2 {
+			from = fyne.NewPos(src.Pos.X+src.Size.Width, fromY)
+			to = fyne.NewPos(dst.Pos.X, toY)
+			dir = 1
+		} else {
+			from = fyne.NewPos(src.Pos.X, fromY)
+			to = fyne.NewPos(dst.Pos.X+dst.Size.Width, toY)
+		}
+		midX := (from.X + to.X) / 2
+		points = []fyne.Position{from, fyne.NewPos(midX, fromY), fyne.NewPos(midX, toY), to}
+	}
+
+	var objects []fyne.CanvasObject
+	for i := 0; i+1 < len(points); i++ {
+		objects = append(objects, r.newERLine(points[i], points[i+1], lineColor))
+	}
+
+	// «Воронья лапка» (многие) на стороне внешнего ключа, если он не уникален
+	if !rel.Unique {
+		tip := fyne.NewPos(from.X+dir*12, from.Y)
+		objects = append(objects,
+			r.newERLine(tip, fyne.NewPos(from.X, from.Y-6), lineColor),
+			r.newERLine(tip, fyne.NewPos(from.X, from.Y+6), lineColor),
+		)
+	}
+
+	// Черта «один» на стороне первичного ключа; для необязательной связи — кружок
+	endDir := float32(1)
+	if points[len(points)-2].X < to.X {
+		endDir = -1
+	}
+	bar := to.X + endDir*10
+	objects = append(objects, r.newERLine(fyne.NewPos(bar, to.Y-6), fyne.NewPos(bar, to.Y+6), lineColor))
+	if rel.Nullable {
+		circle := canvas.NewCircle(theme.Color(theme.ColorNameBackground))
+		circle.StrokeColor = lineColor
+		circle.StrokeWidth = 1.5
+		center := d.toScreen(fyne.NewPos(to.X+endDir*18, to.Y))
+		radius := 4 * d.zoom
+		circle.Move(fyne.NewPos(center.X-radius, center.Y-radius))
+		circle.Resize(fyne.NewSize(2*radius, 2*radius))
+		objects = append(objects, circle)
+	}
+	return objects
+}
+
+// UIERDiagram открывает интерактивную ER-диаграмму схемы.
+// onOpen вызывается при клике по таблице (открыть её в основной таблице).
+func UIERDiagram(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, onOpen func(table string)) {
+	model, err := operation.GetSchemaModel(ctx, pool, nil)
+	if err != nil {
+		showError(window, "Ошибка загрузки схемы: "+err.Error())
+		return
+	}
+	if len(model.Tables) == 0 {
+		showInfo(window, "В схеме нет таблиц")
+		return
+	}
+
+	diagramWindow := fyne.CurrentApp().NewWindow("ER-диаграмма")
+
+	diagram := newERDiagram(model, func(table string) {
+		if onOpen != nil {
+			onOpen(table)
+			window.RequestFocus()
+		}
+	})
+
+	reloadBtn := widget.NewButton("🔄 Обновить", func() {
+		newModel, err := operation.GetSchemaModel(ctx, pool, nil)
+		if err != nil {
+			showError(diagramWindow, err.Error())
+			return
+		}
+		diagram.model = newModel
+		diagram.ResetLayout()
+	})
+
+	toolbar := container.NewHBox(
+		widget.NewButton("＋", diagram.ZoomIn),
+		widget.NewButton("－", diagram.ZoomOut),
+		widget.NewButton("По размеру окна", diagram.FitToView),
+		widget.NewButton("Авторасстановка", diagram.ResetLayout),
+		reloadBtn,
+		widget.NewLabel("Колесо — масштаб, перетаскивание — сдвиг или перенос таблицы, клик — открыть таблицу"),
+	)
+	// Фон панели инструментов перекрывает части диаграммы, вышедшие за её область
+	top := container.NewStack(canvas.NewRectangle(theme.Color(theme.ColorNameBackground)), toolbar)
+
+	diagramWindow.SetContent(container.NewBorder(top, nil, nil, nil, diagram))
+	diagramWindow.Resize(fyne.NewSize(1100, 700))
+	diagramWindow.CenterOnScreen()
+	diagramWindow.Show()
+	diagram.FitToView()
+}
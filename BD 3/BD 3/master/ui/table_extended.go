@@ -24,11 +24,15 @@ func UICreateView(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 	selectQueryEntry.SetPlaceHolder("SELECT id, name FROM products WHERE active = true")
 	selectQueryEntry.SetMinRowsVisible(6)
 
+	optionsForm := newViewOptionsForm()
+
 	form := container.NewVBox(
 		widget.NewLabel("View Name:"),
 		viewNameEntry,
 		widget.NewLabel("SELECT Query:"),
 		selectQueryEntry,
+		widget.NewSeparator(),
+		optionsForm.content(),
 	)
 
 	dialog.ShowCustomConfirm("Create VIEW", "Create", "Cancel", form, func(ok bool) {
@@ -44,7 +48,7 @@ func UICreateView(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 			return
 		}
 
-		err := internal.CreateView(ctx, pool, viewName, selectQuery)
+		err := createViewWithOptions(ctx, pool, viewName, selectQuery, optionsForm.options(false))
 		if err != nil {
 			showError(window, fmt.Sprintf("Failed to create view: %v", err))
 			return
@@ -63,11 +67,15 @@ func UICreateOrReplaceView(ctx context.Context, pool *pgxpool.Pool, window fyne.
 	selectQueryEntry.SetPlaceHolder("SELECT id, name FROM products WHERE active = true")
 	selectQueryEntry.SetMinRowsVisible(6)
 
+	optionsForm := newViewOptionsForm()
+
 	form := container.NewVBox(
 		widget.NewLabel("View Name:"),
 		viewNameEntry,
 		widget.NewLabel("SELECT Query:"),
 		selectQueryEntry,
+		widget.NewSeparator(),
+		optionsForm.content(),
 	)
 
 	dialog.ShowCustomConfirm("Create or Replace VIEW", "Create/Replace", "Cancel", form, func(ok bool) {
@@ -83,7 +91,7 @@ func UICreateOrReplaceView(ctx context.Context, pool *pgxpool.Pool, window fyne.
 			return
 		}
 
-		err := internal.CreateOrReplaceView(ctx, pool, viewName, selectQuery)
+		err := createViewWithOptions(ctx, pool, viewName, selectQuery, optionsForm.options(true))
 		if err != nil {
 			showError(window, fmt.Sprintf("Failed to create or replace view: %v", err))
 			return
@@ -95,29 +103,53 @@ func UICreateOrReplaceView(ctx context.Context, pool *pgxpool.Pool, window fyne.
 
 // UIListViews displays all views
 func UIListViews(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
-	views, err := internal.ListAllViews(ctx, pool)
+	views, err := internal.GetViewsInfo(ctx, pool)
 	if err != nil {
 		showError(window, fmt.Sprintf("Failed to list views: %v", err))
 		return
 	}
+	temporaryViews, err := getViewSession(pool).TemporaryViews(ctx)
+	if err != nil {
+		showError(window, fmt.Sprintf("Failed to list temporary views: %v", err))
+		return
+	}
+	views = append(views, temporaryViews...)
+
+	yesNo := func(b bool) string {
+		if b {
+			return "YES"
+		}
+		return "NO"
+	}
 
 	var tableData [][]string
-	tableData = append(tableData, []string{"View Name"})
+	tableData = append(tableData, []string{"View Name", "Updatable", "Insertable", "Check Option", "Options"})
 
 	for _, v := range views {
-		tableData = append(tableData, []string{v})
+		var options []string
+		if v.Temporary {
+			options = append(options, "temporary")
+		}
+		if v.SecurityBarrier {
+			options = append(options, "security_barrier")
+		}
+		if v.SecurityInvoker {
+			options = append(options, "security_invoker")
+		}
+		checkOption := v.CheckOption
+		if checkOption == "" {
+			checkOption = "-"
+		}
+		tableData = append(tableData, []string{v.Name, yesNo(v.IsUpdatable), yesNo(v.IsInsertable),
+			checkOption, strings.Join(options, ", ")})
 	}
 
-	table, err := CreateTable(tableData)
-	if err != nil {
-		showError(window, fmt.Sprintf("Failed to create table: %v", err))
-		return
-	}
+	table := newReportTable(tableData)
 
 	viewsWindow := fyne.CurrentApp().NewWindow("All VIEWs")
 	viewsWindow.SetTitle("All VIEWs")
 	viewsWindow.SetContent(container.NewScroll(table))
-	viewsWindow.Resize(fyne.NewSize(500, 400))
+	viewsWindow.Resize(fyne.NewSize(700, 400))
 	viewsWindow.CenterOnScreen()
 	viewsWindow.Show()
 }
@@ -167,7 +199,7 @@ func UIDropView(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 	viewNameEntry := widget.NewEntry()
 	viewNameEntry.SetPlaceHolder("view_name")
 
-	warningLabel := widget.NewLabel("⚠️ WARNING: This action cannot be undone! All dependent objects will be deleted.")
+	warningLabel := widget.NewLabel("⚠️ WARNING: This action cannot be undone! Dependent objects will be shown before dropping.")
 	warningLabel.Wrapping = fyne.TextWrapWord
 
 	confirmEntry := widget.NewEntry()
@@ -202,13 +234,12 @@ func UIDropView(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
 			return
 		}
 
-		err := internal.DropView(ctx, pool, viewName)
-		if err != nil {
-			showError(window, fmt.Sprintf("Failed to drop view: %v", err))
-			return
+		dropFn := func(cascade bool) error {
+			return internal.DropView(ctx, pool, viewName, cascade)
 		}
-
-		showInfo(window, fmt.Sprintf("VIEW '%s' dropped successfully!", viewName))
+		showDropImpactDialog(ctx, pool, window, internal.DependencyView, viewName, dropFn, func() {
+			showInfo(window, fmt.Sprintf("VIEW '%s' dropped successfully!", viewName))
+		})
 	}, window)
 }
 
@@ -223,11 +254,14 @@ func UICreateMaterializedView(ctx context.Context, pool *pgxpool.Pool, window fy
 	selectQueryEntry.SetPlaceHolder("SELECT id, name, COUNT(*) as cnt FROM products GROUP BY id, name")
 	selectQueryEntry.SetMinRowsVisible(6)
 
+	noDataCheck := widget.NewCheck("WITH NO DATA (populate later with REFRESH)", nil)
+
 	form := container.NewVBox(
 		widget.NewLabel("Materialized View Name:"),
 		mvNameEntry,
 		widget.NewLabel("SELECT Query:"),
 		selectQueryEntry,
+		noDataCheck,
 	)
 
 	dialog.ShowCustomConfirm("Create MATERIALIZED VIEW", "Create", "Cancel", form, func(ok bool) {
@@ -243,7 +277,8 @@ func UICreateMaterializedView(ctx context.Context, pool *pgxpool.Pool, window fy
 			return
 		}
 
-		err := internal.CreateMaterializedView(ctx, pool, mvName, selectQuery)
+		err := internal.CreateMaterializedViewWithOptions(ctx, pool, mvName, selectQuery,
+			internal.MaterializedViewOptions{NoData: noDataCheck.Checked})
 		if err != nil {
 			showError(window, fmt.Sprintf("Failed to create materialized view: %v", err))
 			return
@@ -258,7 +293,7 @@ func UIRefreshMaterializedView(ctx context.Context, pool *pgxpool.Pool, window f
 	mvNameEntry := widget.NewEntry()
 	mvNameEntry.SetPlaceHolder("materialized_view_name")
 
-	concurrentlyCheck := widget.NewCheck("Refresh CONCURRENTLY (if unique index exists)", nil)
+	concurrentlyCheck := widget.NewCheck("Refresh CONCURRENTLY (requires a unique index)", nil)
 
 	form := container.NewVBox(
 		widget.NewForm(
@@ -279,41 +314,57 @@ func UIRefreshMaterializedView(ctx context.Context, pool *pgxpool.Pool, window f
 			return
 		}
 
-		err := internal.RefreshMaterializedView(ctx, pool, mvName, concurrentlyCheck.Checked)
-		if err != nil {
-			showError(window, fmt.Sprintf("Failed to refresh materialized view: %v", err))
-			return
-		}
-
-		showInfo(window, fmt.Sprintf("MATERIALIZED VIEW '%s' refreshed successfully!", mvName))
+		refreshMaterializedViewWithPreflight(ctx, pool, window, mvName, concurrentlyCheck.Checked)
 	}, window)
 }
 
 // UIListMaterializedViews displays all materialized views
 func UIListMaterializedViews(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
-	mvs, err := internal.ListAllMaterializedViews(ctx, pool)
+	mvs, err := internal.GetMaterializedViewsInfo(ctx, pool)
 	if err != nil {
 		showError(window, fmt.Sprintf("Failed to list materialized views: %v", err))
 		return
 	}
 
+	scheduler := getMVRefreshScheduler(ctx, pool)
+	lastRefreshed := scheduler.LastRefreshed()
+	schedules := make(map[string]string)
+	for _, s := range scheduler.Schedules() {
+		if s.Enabled {
+			schedules[s.MVName] = s.Spec
+		}
+	}
+
+	yesNo := func(b bool) string {
+		if b {
+			return "YES"
+		}
+		return "NO"
+	}
+
 	var tableData [][]string
-	tableData = append(tableData, []string{"Materialized View Name"})
+	tableData = append(tableData, []string{"Materialized View Name", "Populated", "Size", "Rows (est.)", "Indexes",
+		"CONCURRENTLY", "Last Refreshed", "Schedule"})
 
 	for _, mv := range mvs {
-		tableData = append(tableData, []string{mv})
+		schedule := schedules[mv.Name]
+		if schedule == "" {
+			schedule = "-"
+		}
+		rows := "-"
+		if mv.RowEstimate >= 0 {
+			rows = fmt.Sprint(mv.RowEstimate)
+		}
+		tableData = append(tableData, []string{mv.Name, yesNo(mv.Populated), mv.Size, rows, fmt.Sprint(mv.IndexCount),
+			yesNo(mv.Populated && mv.HasUniqueIndex), formatRefreshTime(lastRefreshed[mv.Name]), schedule})
 	}
 
-	table, err := CreateTable(tableData)
-	if err != nil {
-		showError(window, fmt.Sprintf("Failed to create table: %v", err))
-		return
-	}
+	table := newReportTable(tableData)
 
 	mvsWindow := fyne.CurrentApp().NewWindow("All MATERIALIZED VIEWs")
 	mvsWindow.SetTitle("All MATERIALIZED VIEWs")
 	mvsWindow.SetContent(container.NewScroll(table))
-	mvsWindow.Resize(fyne.NewSize(500, 400))
+	mvsWindow.Resize(fyne.NewSize(1000, 400))
 	mvsWindow.CenterOnScreen()
 	mvsWindow.Show()
 }
@@ -358,13 +409,12 @@ func UIDropMaterializedView(ctx context.Context, pool *pgxpool.Pool, window fyne
 			return
 		}
 
-		err := internal.DropMaterializedView(ctx, pool, mvName)
-		if err != nil {
-			showError(window, fmt.Sprintf("Failed to drop materialized view: %v", err))
-			return
+		dropFn := func(cascade bool) error {
+			return internal.DropMaterializedView(ctx, pool, mvName, cascade)
 		}
-
-		showInfo(window, fmt.Sprintf("MATERIALIZED VIEW '%s' dropped successfully!", mvName))
+		showDropImpactDialog(ctx, pool, window, internal.DependencyMaterializedView, mvName, dropFn, func() {
+			showInfo(window, fmt.Sprintf("MATERIALIZED VIEW '%s' dropped successfully!", mvName))
+		})
 	}, window)
 }
 
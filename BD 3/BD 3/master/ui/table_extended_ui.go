@@ -32,6 +32,7 @@ func UISearchDialog(ctx context.Context, pool *pgxpool.Pool, window fyne.Window,
 		"REGEX NoCase",
 		"NOT REGEX !",
 		"NOT REGEX NoCase !",
+		"Похоже на (pg_trgm)",
 	}, nil)
 	searchTypeSelect.SetSelected("LIKE")
 
@@ -69,6 +70,21 @@ func UISearchDialog(ctx context.Context, pool *pgxpool.Pool, window fyne.Window,
 			return
 		}
 
+		// Нечёткий поиск требует расширения pg_trgm — проверяем и предлагаем установить
+		if searchType == "Похоже на (pg_trgm)" {
+			ensureExtension(ctx, pool, window, "pg_trgm", func() {
+				results, err := operation.NewQueryBuilder(tableName).WhereTrigramSimilar(column, pattern).Execute(ctx, pool)
+				if err != nil {
+					showError(window, "Ошибка поиска: "+err.Error())
+					return
+				}
+				resultsData = results
+				resultsLabel.SetText(fmt.Sprintf("Результаты: %d записей", max(len(results)-1, 0)))
+				resultsTable.Refresh()
+			})
+			return
+		}
+
 		qb := operation.NewQueryBuilder(tableName)
 
 		// Применяем выбранный тип поиска
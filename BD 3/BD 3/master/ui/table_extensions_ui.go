@@ -0,0 +1,440 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для расширений PostgreSQL ==========
+
+// ensureExtension проверяет, что расширение установлено, и при необходимости предлагает
+// установить его. onReady вызывается, когда расширение доступно для использования
+func ensureExtension(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, name string, onReady func()) {
+	installed, available, err := operation.GetExtensionStatus(ctx, pool, name)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+	if installed {
+		onReady()
+		return
+	}
+
+	feature := operation.ExtensionFeatures[name]
+	if !available {
+		showError(window, fmt.Sprintf("Для функции «%s» нужно расширение %s, но оно не поставляется с этим сервером PostgreSQL", feature, name))
+		return
+	}
+
+	dialog.ShowConfirm("Требуется расширение",
+		fmt.Sprintf("Для функции «%s» нужно расширение %s.\nУстановить его в текущую базу данных (CREATE EXTENSION %s)?", feature, name, name),
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := operation.InstallExtension(ctx, pool, name, "", "", false); err != nil {
+				showError(window, err.Error())
+				return
+			}
+			onReady()
+		}, window)
+}
+
+// UIExtensionManager открывает менеджер расширений: список доступных и установленных
+// расширений, установка с выбором схемы и версии, обновление, перенос и удаление
+func UIExtensionManager(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	schemas, err := operation.GetSchemas(ctx, pool)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+
+	managerWindow := fyne.CurrentApp().NewWindow("Расширения PostgreSQL")
+
+	var extensions, visible []operation.ExtensionInfo
+	var selected *operation.ExtensionInfo
+
+	header := []string{"Расширение", "Установлена", "Доступна", "Схема", "Нужно для", "Описание"}
+	data := [][]string{header}
+	extensionsTable := widget.NewTable(
+		func() (int, int) {
+			return len(data), len(header)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.SetText(data[id.Row][id.Col])
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+		},
+	)
+
+	filterEntry := widget.NewEntry()
+	filterEntry.SetPlaceHolder("Фильтр по имени или описанию")
+	installedOnlyCheck := widget.NewCheck("Только установленные", nil)
+
+	selectedLabel := widget.NewLabel("Выберите расширение в таблице")
+	schemaSelect := widget.NewSelect(schemas, nil)
+	schemaSelect.PlaceHolder = "По умолчанию"
+	versionSelect := widget.NewSelect(nil, nil)
+	versionSelect.PlaceHolder = "По умолчанию"
+	cascadeCheck := widget.NewCheck("CASCADE — установить также необходимые расширения", nil)
+
+	installBtn := widget.NewButton("Установить", nil)
+	upgradeBtn := widget.NewButton("Обновить", nil)
+	moveBtn := widget.NewButton("Перенести в схему", nil)
+	dropBtn := widget.NewButton("Удалить", nil)
+	installBtn.Importance = widget.HighImportance
+
+	updateButtons := func() {
+		for _, btn := range []*widget.Button{installBtn, upgradeBtn, moveBtn, dropBtn} {
+			btn.Disable()
+		}
+		if selected == nil {
+			return
+		}
+		if selected.Installed() {
+			upgradeBtn.Enable()
+			dropBtn.Enable()
+			if selected.Relocatable {
+				moveBtn.Enable()
+			}
+		} else {
+			installBtn.Enable()
+		}
+	}
+
+	applyFilter := func() {
+		filter := strings.ToLower(strings.TrimSpace(filterEntry.Text))
+		visible = nil
+		data = [][]string{header}
+		for _, ext := range extensions {
+			if installedOnlyCheck.Checked && !ext.Installed() {
+				continue
+			}
+			if filter != "" && !strings.Contains(strings.ToLower(ext.Name+" "+ext.Comment), filter) {
+				continue
+			}
+			installedVersion := ext.InstalledVersion
+			if ext.UpgradeAvailable() {
+				installedVersion += " ⬆"
+			}
+			visible = append(visible, ext)
+			data = append(data, []string{ext.Name, installedVersion, ext.DefaultVersion, ext.Schema,
+				operation.ExtensionFeatures[ext.Name], ext.Comment})
+		}
+		setOptimalColumnWidths(extensionsTable, data)
+		extensionsTable.Refresh()
+	}
+
+	reload := func() {
+		var err error
+		extensions, err = operation.GetExtensions(ctx, pool)
+		if err != nil {
+			showError(managerWindow, err.Error())
+			return
+		}
+		selected = nil
+		selectedLabel.SetText("Выберите расширение в таблице")
+		applyFilter()
+		updateButtons()
+	}
+
+	filterEntry.OnChanged = func(string) { applyFilter() }
+	installedOnlyCheck.OnChanged = func(bool) { applyFilter() }
+
+	extensionsTable.OnSelected = func(id widget.TableCellID) {
+		if id.Row == 0 || id.Row > len(visible) {
+			return
+		}
+		ext := visible[id.Row-1]
+		selected = &ext
+
+		status := "не установлено"
+		if ext.Installed() {
+			status = fmt.Sprintf("установлена версия %s в схеме %s", ext.InstalledVersion, ext.Schema)
+		}
+		selectedLabel.SetText(fmt.Sprintf("%s — %s", ext.Name, status))
+
+		versions, err := operation.GetExtensionVersions(ctx, pool, ext.Name)
+		if err == nil {
+			versionSelect.Options = versions
+		}
+		versionSelect.ClearSelected()
+		versionSelect.Refresh()
+		schemaSelect.ClearSelected()
+		updateButtons()
+	}
+
+	installBtn.OnTapped = func() {
+		if selected == nil {
+			return
+		}
+		err := operation.InstallExtension(ctx, pool, selected.Name, schemaSelect.Selected, versionSelect.Selected, cascadeCheck.Checked)
+		if err != nil {
+			showError(managerWindow, err.Error())
+			return
+		}
+		showInfo(managerWindow, fmt.Sprintf("Расширение %s установлено", selected.Name))
+		reload()
+	}
+
+	upgradeBtn.OnTapped = func() {
+		if selected == nil {
+			return
+		}
+		if err := operation.UpgradeExtension(ctx, pool, selected.Name, versionSelect.Selected); err != nil {
+			showError(managerWindow, err.Error())
+			return
+		}
+		showInfo(managerWindow, fmt.Sprintf("Расширение %s обновлено", selected.Name))
+		reload()
+	}
+
+	moveBtn.OnTapped = func() {
+		if selected == nil {
+			return
+		}
+		if schemaSelect.Selected == "" {
+			showError(managerWindow, "Выберите схему")
+			return
+		}
+		if err := operation.SetExtensionSchema(ctx, pool, selected.Name, schemaSelect.Selected); err != nil {
+			showError(managerWindow, err.Error())
+			return
+		}
+		reload()
+	}
+
+	dropBtn.OnTapped = func() {
+		if selected == nil {
+			return
+		}
+		name := selected.Name
+		dropFn := func(cascade bool) error {
+			return operation.DropExtension(ctx, pool, name, cascade)
+		}
+		showDropImpactDialog(ctx, pool, managerWindow, operation.DependencyExtension, name, dropFn, func() {
+			showInfo(managerWindow, fmt.Sprintf("Расширение %s удалено", name))
+			reload()
+		})
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem("Схема", schemaSelect),
+		widget.NewFormItem("Версия", versionSelect),
+		widget.NewFormItem("", cascadeCheck),
+	)
+
+	controls := container.NewVBox(
+		container.NewBorder(nil, nil, nil, installedOnlyCheck, filterEntry),
+		widget.NewSeparator(),
+	)
+	actions := container.NewVBox(
+		widget.NewSeparator(),
+		selectedLabel,
+		form,
+		container.NewHBox(installBtn, upgradeBtn, moveBtn, dropBtn),
+	)
+
+	managerWindow.SetContent(container.NewBorder(controls, actions, nil, nil, container.NewScroll(extensionsTable)))
+	managerWindow.Resize(fyne.NewSize(950, 650))
+	managerWindow.CenterOnScreen()
+	managerWindow.Show()
+
+	reload()
+}
+
+// UICrosstab открывает построитель сводной таблицы (crosstab, расширение tablefunc)
+func UICrosstab(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	ensureExtension(ctx, pool, window, "tablefunc", func() {
+		tables, err := getTablesListFromDB(ctx, pool)
+		if err != nil {
+			showError(window, "Ошибка получения таблиц: "+err.Error())
+			return
+		}
+
+		crosstabWindow := fyne.CurrentApp().NewWindow("Сводная таблица (crosstab)")
+
+		rowSelect := widget.NewSelect(nil, nil)
+		categorySelect := widget.NewSelect(nil, nil)
+		valueSelect := widget.NewSelect(nil, nil)
+		tableSelect := widget.NewSelect(tables, func(table string) {
+			columns, err := getTableColumns(ctx, pool, table)
+			if err != nil {
+				showError(crosstabWindow, err.Error())
+				return
+			}
+			for _, sel := range []*widget.Select{rowSelect, categorySelect, valueSelect} {
+				sel.Options = columns
+				sel.ClearSelected()
+				sel.Refresh()
+			}
+		})
+		aggregateSelect := widget.NewSelect(operation.CrosstabAggregates, nil)
+		aggregateSelect.SetSelected("SUM")
+
+		data := [][]string{{""}}
+		resultTable := widget.NewTable(
+			func() (int, int) {
+				return len(data), len(data[0])
+			},
+			func() fyne.CanvasObject {
+				return widget.NewLabel("")
+			},
+			func(id widget.TableCellID, obj fyne.CanvasObject) {
+				label := obj.(*widget.Label)
+				label.SetText(data[id.Row][id.Col])
+				label.TextStyle = fyne.TextStyle{Bold: id.Row == 0 || id.Col == 0}
+			},
+		)
+
+		buildBtn := widget.NewButton("Построить", func() {
+			if tableSelect.Selected == "" || rowSelect.Selected == "" || categorySelect.Selected == "" || valueSelect.Selected == "" {
+				showError(crosstabWindow, "Выберите таблицу и столбцы строк, категорий и значений")
+				return
+			}
+			result, err := operation.Crosstab(ctx, pool, tableSelect.Selected, rowSelect.Selected,
+				categorySelect.Selected, valueSelect.Selected, aggregateSelect.Selected)
+			if err != nil {
+				showError(crosstabWindow, err.Error())
+				return
+			}
+			data = result
+			setOptimalColumnWidths(resultTable, data)
+			resultTable.Refresh()
+		})
+		buildBtn.Importance = widget.HighImportance
+
+		form := widget.NewForm(
+			widget.NewFormItem("Таблица", tableSelect),
+			widget.NewFormItem("Строки", rowSelect),
+			widget.NewFormItem("Столбцы (категории)", categorySelect),
+			widget.NewFormItem("Значения", valueSelect),
+			widget.NewFormItem("Агрегат", aggregateSelect),
+		)
+
+		crosstabWindow.SetContent(container.NewBorder(container.NewVBox(form, buildBtn), nil, nil, nil,
+			container.NewScroll(resultTable)))
+		crosstabWindow.Resize(fyne.NewSize(900, 600))
+		crosstabWindow.CenterOnScreen()
+		crosstabWindow.Show()
+	})
+}
+
+// UIStatementStats показывает самые затратные запросы (расширение pg_stat_statements)
+func UIStatementStats(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	ensureExtension(ctx, pool, window, "pg_stat_statements", func() {
+		statsWindow := fyne.CurrentApp().NewWindow("Статистика запросов (pg_stat_statements)")
+
+		data := [][]string{{""}}
+		statsTable := widget.NewTable(
+			func() (int, int) {
+				return len(data), len(data[0])
+			},
+			func() fyne.CanvasObject {
+				return widget.NewLabel("")
+			},
+			func(id widget.TableCellID, obj fyne.CanvasObject) {
+				label := obj.(*widget.Label)
+				label.SetText(data[id.Row][id.Col])
+				label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			},
+		)
+
+		reload := func() {
+			result, err := operation.GetStatementStats(ctx, pool, 50)
+			if err != nil {
+				showError(statsWindow, err.Error())
+				return
+			}
+			data = result
+			setOptimalColumnWidths(statsTable, data)
+			statsTable.Refresh()
+		}
+
+		refreshBtn := widget.NewButton("Обновить", reload)
+		resetBtn := widget.NewButton("Сбросить статистику", func() {
+			dialog.ShowConfirm("Сброс статистики", "Сбросить накопленную статистику всех запросов?", func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := operation.ResetStatementStats(ctx, pool); err != nil {
+					showError(statsWindow, err.Error())
+					return
+				}
+				reload()
+			}, statsWindow)
+		})
+
+		statsWindow.SetContent(container.NewBorder(container.NewHBox(refreshBtn, resetBtn), nil, nil, nil,
+			container.NewScroll(statsTable)))
+		statsWindow.Resize(fyne.NewSize(1000, 600))
+		statsWindow.CenterOnScreen()
+		statsWindow.Show()
+
+		reload()
+	})
+}
+
+// UIColumnDigest показывает дайджест значений столбца (расширение pgcrypto), не изменяя данные
+func UIColumnDigest(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	ensureExtension(ctx, pool, window, "pgcrypto", func() {
+		tables, err := getTablesListFromDB(ctx, pool)
+		if err != nil {
+			showError(window, "Ошибка получения таблиц: "+err.Error())
+			return
+		}
+
+		columnSelect := widget.NewSelect(nil, nil)
+		tableSelect := widget.NewSelect(tables, func(table string) {
+			columns, err := getTableColumns(ctx, pool, table)
+			if err != nil {
+				showError(window, err.Error())
+				return
+			}
+			columnSelect.Options = columns
+			columnSelect.ClearSelected()
+			columnSelect.Refresh()
+		})
+		algorithmSelect := widget.NewSelect(operation.DigestAlgorithms, nil)
+		algorithmSelect.SetSelected(operation.DigestAlgorithms[0])
+
+		form := widget.NewForm(
+			widget.NewFormItem("Таблица", tableSelect),
+			widget.NewFormItem("Столбец", columnSelect),
+			widget.NewFormItem("Алгоритм", algorithmSelect),
+		)
+
+		dialog.ShowCustomConfirm("Дайджест столбца (pgcrypto)", "Показать", "Отмена", form, func(ok bool) {
+			if !ok {
+				return
+			}
+			if tableSelect.Selected == "" || columnSelect.Selected == "" {
+				showError(window, "Выберите таблицу и столбец")
+				return
+			}
+			data, err := operation.PreviewColumnDigest(ctx, pool, tableSelect.Selected, columnSelect.Selected, algorithmSelect.Selected, 100)
+			if err != nil {
+				showError(window, err.Error())
+				return
+			}
+
+			digestWindow := fyne.CurrentApp().NewWindow(fmt.Sprintf("Дайджест %s.%s (%s, первые 100 строк)",
+				tableSelect.Selected, columnSelect.Selected, algorithmSelect.Selected))
+			digestWindow.SetContent(container.NewScroll(newReportTable(data)))
+			digestWindow.Resize(fyne.NewSize(900, 500))
+			digestWindow.CenterOnScreen()
+			digestWindow.Show()
+		}, window)
+	})
+}
@@ -0,0 +1,224 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для обслуживания таблиц ==========
+
+// UIMaintenancePanel открывает панель обслуживания таблицы: размеры, статистика, VACUUM/ANALYZE/REINDEX
+func UIMaintenancePanel(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tables, err := getTablesListFromDB(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения таблиц: "+err.Error())
+		return
+	}
+
+	panelWindow := fyne.CurrentApp().NewWindow("Обслуживание таблицы")
+
+	tableSizeLabel := widget.NewLabel("—")
+	indexesSizeLabel := widget.NewLabel("—")
+	toastSizeLabel := widget.NewLabel("—")
+	totalSizeLabel := widget.NewLabel("—")
+	tuplesLabel := widget.NewLabel("—")
+	lastVacuumLabel := widget.NewLabel("—")
+	lastAnalyzeLabel := widget.NewLabel("—")
+	countersLabel := widget.NewLabel("—")
+
+	statsForm := widget.NewForm(
+		widget.NewFormItem("Таблица", tableSizeLabel),
+		widget.NewFormItem("Индексы", indexesSizeLabel),
+		widget.NewFormItem("TOAST", toastSizeLabel),
+		widget.NewFormItem("Всего", totalSizeLabel),
+		widget.NewFormItem("Строки (живые / мёртвые)", tuplesLabel),
+		widget.NewFormItem("Последний VACUUM", lastVacuumLabel),
+		widget.NewFormItem("Последний ANALYZE", lastAnalyzeLabel),
+		widget.NewFormItem("Выполнено", countersLabel),
+	)
+
+	tableSelect := widget.NewSelect(tables, nil)
+	tableSelect.PlaceHolder = "Таблица"
+
+	reloadStats := func() {
+		if tableSelect.Selected == "" {
+			return
+		}
+		st, err := operation.GetTableMaintenanceStats(ctx, pool, tableSelect.Selected)
+		if err != nil {
+			showError(panelWindow, err.Error())
+			return
+		}
+		tableSizeLabel.SetText(st.TableSize)
+		indexesSizeLabel.SetText(st.IndexesSize)
+		toastSizeLabel.SetText(st.ToastSize)
+		totalSizeLabel.SetText(st.TotalSize)
+		tuplesLabel.SetText(fmt.Sprintf("%d / %d (%.2f%% мёртвых)", st.LiveTuples, st.DeadTuples, st.DeadRatio))
+		lastVacuumLabel.SetText(fmt.Sprintf("%s (авто: %s)", st.LastVacuum, st.LastAutovacuum))
+		lastAnalyzeLabel.SetText(fmt.Sprintf("%s (авто: %s)", st.LastAnalyze, st.LastAutoanalyze))
+		countersLabel.SetText(fmt.Sprintf("VACUUM: %d, ANALYZE: %d", st.VacuumCount, st.AnalyzeCount))
+	}
+	tableSelect.OnChanged = func(string) { reloadStats() }
+
+	fullCheck := widget.NewCheck("FULL (переписать таблицу, блокирует её)", nil)
+	analyzeCheck := widget.NewCheck("ANALYZE", nil)
+	analyzeCheck.SetChecked(true)
+	concurrentlyCheck := widget.NewCheck("CONCURRENTLY", nil)
+
+	progressBar := widget.NewProgressBar()
+	phaseLabel := widget.NewLabel("")
+
+	var vacuumBtn, analyzeBtn, reindexBtn *widget.Button
+	setButtonsEnabled := func(enabled bool) {
+		for _, btn := range []*widget.Button{vacuumBtn, analyzeBtn, reindexBtn} {
+			if enabled {
+				btn.Enable()
+			} else {
+				btn.Disable()
+			}
+		}
+	}
+
+	// runWithProgress выполняет операцию в фоне и опрашивает pg_stat_progress_*
+	runWithProgress := func(title string, action func(table string) error) {
+		table := tableSelect.Selected
+		if table == "" {
+			showError(panelWindow, "Выберите таблицу")
+			return
+		}
+
+		setButtonsEnabled(false)
+		progressBar.SetValue(0)
+		phaseLabel.SetText(title + ": запуск...")
+
+		done := make(chan error, 1)
+		go func() {
+			done <- action(table)
+		}()
+
+		go func() {
+			ticker := time.NewTicker(500 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case err := <-done:
+					fyne.Do(func() {
+						setButtonsEnabled(true)
+						if err != nil {
+							phaseLabel.SetText(title + ": ошибка")
+							showError(panelWindow, err.Error())
+							return
+						}
+						progressBar.SetValue(1)
+						phaseLabel.SetText(title + ": завершено")
+						reloadStats()
+					})
+					return
+				case <-ticker.C:
+					progress, running, err := operation.GetMaintenanceProgress(ctx, pool, table)
+					if err != nil || !running {
+						continue
+					}
+					fyne.Do(func() {
+						progressBar.SetValue(progress.Fraction())
+						phaseLabel.SetText(fmt.Sprintf("%s: %s (%d / %d блоков)",
+							progress.Command, progress.Phase, progress.Done, progress.Total))
+					})
+				}
+			}
+		}()
+	}
+
+	vacuumBtn = widget.NewButton("VACUUM", func() {
+		full, analyze := fullCheck.Checked, analyzeCheck.Checked
+		run := func() {
+			runWithProgress("VACUUM", func(table string) error {
+				return operation.VacuumTable(ctx, pool, table, full, analyze)
+			})
+		}
+		if !full {
+			run()
+			return
+		}
+		dialog.ShowConfirm("VACUUM FULL",
+			"VACUUM FULL переписывает таблицу целиком и блокирует её на всё время выполнения. Продолжить?",
+			func(ok bool) {
+				if ok {
+					run()
+				}
+			}, panelWindow)
+	})
+
+	analyzeBtn = widget.NewButton("ANALYZE", func() {
+		runWithProgress("ANALYZE", func(table string) error {
+			return operation.AnalyzeTable(ctx, pool, table)
+		})
+	})
+
+	reindexBtn = widget.NewButton("REINDEX", func() {
+		concurrently := concurrentlyCheck.Checked
+		runWithProgress("REINDEX", func(table string) error {
+			return operation.ReindexTable(ctx, pool, table, concurrently)
+		})
+	})
+
+	refreshBtn := widget.NewButton("🔄 Обновить", reloadStats)
+
+	content := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Таблица", tableSelect)),
+		widget.NewCard("Размеры и статистика", "", statsForm),
+		widget.NewCard("Действия", "", container.NewVBox(
+			container.NewHBox(vacuumBtn, fullCheck, analyzeCheck),
+			container.NewHBox(analyzeBtn),
+			container.NewHBox(reindexBtn, concurrentlyCheck),
+			progressBar,
+			phaseLabel,
+		)),
+		refreshBtn,
+	)
+
+	panelWindow.SetContent(container.NewScroll(content))
+	panelWindow.Resize(fyne.NewSize(700, 650))
+	panelWindow.CenterOnScreen()
+	panelWindow.Show()
+}
+
+// UIBloatOverview показывает статистику обслуживания всех таблиц
+func UIBloatOverview(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	stats, err := operation.ListTablesMaintenanceStats(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка: "+err.Error())
+		return
+	}
+
+	tableData := [][]string{{"Таблица", "Размер", "Индексы", "TOAST", "Всего", "Живые", "Мёртвые", "% мёртвых",
+		"Последний VACUUM", "Последний autovacuum", "Последний ANALYZE", "Последний autoanalyze"}}
+	for _, st := range stats {
+		tableData = append(tableData, []string{
+			st.Table, st.TableSize, st.IndexesSize, st.ToastSize, st.TotalSize,
+			fmt.Sprintf("%d", st.LiveTuples), fmt.Sprintf("%d", st.DeadTuples), fmt.Sprintf("%.2f", st.DeadRatio),
+			st.LastVacuum, st.LastAutovacuum, st.LastAnalyze, st.LastAutoanalyze,
+		})
+	}
+
+	table, err := CreateTable(tableData)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+	setOptimalColumnWidths(table, tableData)
+
+	overviewWindow := fyne.CurrentApp().NewWindow("Статистика обслуживания таблиц")
+	overviewWindow.SetContent(container.NewScroll(table))
+	overviewWindow.Resize(fyne.NewSize(1100, 500))
+	overviewWindow.CenterOnScreen()
+	overviewWindow.Show()
+}
@@ -0,0 +1,212 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ Scheduled MATERIALIZED VIEW refresh UI ============
+
+var (
+	mvSchedulerOnce sync.Once
+	mvScheduler     *operation.MVRefreshScheduler
+)
+
+// getMVRefreshScheduler returns the application-wide refresh scheduler, loading saved schedules
+// and starting them on first use
+func getMVRefreshScheduler(ctx context.Context, pool *pgxpool.Pool) *operation.MVRefreshScheduler {
+	mvSchedulerOnce.Do(func() {
+		path, err := operation.DefaultMVRefreshStorePath()
+		if err != nil {
+			log.Printf("Refresh schedules will not be saved: %v", err)
+		}
+		store, err := operation.LoadMVRefreshStore(path)
+		if err != nil {
+			log.Printf("Refresh schedules will not be saved: %v", err)
+			store, _ = operation.LoadMVRefreshStore("")
+		}
+		mvScheduler = operation.NewMVRefreshScheduler(pool, store)
+		mvScheduler.Start(ctx)
+	})
+	return mvScheduler
+}
+
+const refreshTimeLayout = "2006-01-02 15:04:05"
+
+func formatRefreshTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(refreshTimeLayout)
+}
+
+// UIMVRefreshSchedules manages refresh schedules of materialized views and shows their refresh history
+func UIMVRefreshSchedules(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	mvs, err := operation.ListAllMaterializedViews(ctx, pool)
+	if err != nil {
+		showError(window, fmt.Sprintf("Failed to list materialized views: %v", err))
+		return
+	}
+	if len(mvs) == 0 {
+		showInfo(window, "There are no materialized views in the public schema")
+		return
+	}
+
+	scheduler := getMVRefreshScheduler(ctx, pool)
+	schedulesWindow := fyne.CurrentApp().NewWindow("MATERIALIZED VIEW Refresh Schedules")
+
+	mvSelect := widget.NewSelect(mvs, nil)
+	specEntry := widget.NewSelectEntry([]string{"15m", "1h", "*/30 * * * *", "0 3 * * *", "0 6 * * 1-5", "@daily"})
+	specEntry.SetPlaceHolder("interval (30m, 2h) or cron (min hour day month weekday)")
+	concurrentlyCheck := widget.NewCheck("Refresh CONCURRENTLY", nil)
+	enabledCheck := widget.NewCheck("Enabled", nil)
+	enabledCheck.SetChecked(true)
+	statusLabel := widget.NewLabel("")
+
+	schedulesBox := container.NewStack()
+	historyBox := container.NewStack()
+
+	reload := func() {
+		last := scheduler.LastRefreshed()
+		schedulesData := [][]string{{"Materialized View", "Schedule", "Concurrently", "Enabled", "Next Run", "Last Refreshed"}}
+		for _, s := range scheduler.Schedules() {
+			schedulesData = append(schedulesData, []string{s.MVName, s.Spec, fmt.Sprint(s.Concurrently),
+				fmt.Sprint(s.Enabled), formatRefreshTime(scheduler.NextRun(s.MVName)), formatRefreshTime(last[s.MVName])})
+		}
+		schedulesBox.Objects = []fyne.CanvasObject{container.NewScroll(newReportTable(schedulesData))}
+		schedulesBox.Refresh()
+
+		historyData := [][]string{{"Started", "Duration", "Trigger", "Status", "Error"}}
+		for _, entry := range scheduler.History(mvSelect.Selected) {
+			trigger := "manual"
+			if entry.Scheduled {
+				trigger = "schedule"
+			}
+			historyData = append(historyData, []string{entry.Started.Format(refreshTimeLayout),
+				entry.Duration.Round(time.Millisecond).String(), trigger, entry.Status(), entry.Error})
+		}
+		historyBox.Objects = []fyne.CanvasObject{container.NewScroll(newReportTable(historyData))}
+		historyBox.Refresh()
+	}
+
+	mvSelect.OnChanged = func(name string) {
+		specEntry.SetText("")
+		concurrentlyCheck.SetChecked(false)
+		enabledCheck.SetChecked(true)
+		for _, s := range scheduler.Schedules() {
+			if s.MVName == name {
+				specEntry.SetText(s.Spec)
+				concurrentlyCheck.SetChecked(s.Concurrently)
+				enabledCheck.SetChecked(s.Enabled)
+			}
+		}
+		reload()
+	}
+
+	saveBtn := widget.NewButton("Save Schedule", func() {
+		if mvSelect.Selected == "" {
+			showError(schedulesWindow, "Select a materialized view")
+			return
+		}
+		if concurrentlyCheck.Checked {
+			check, err := operation.CheckConcurrentRefresh(ctx, pool, mvSelect.Selected)
+			if err != nil {
+				showError(schedulesWindow, err.Error())
+				return
+			}
+			if !check.HasUniqueIndex {
+				showError(schedulesWindow, fmt.Sprintf("'%s' has no unique index usable by REFRESH CONCURRENTLY.\n"+
+					"Create one in MATERIALIZED VIEW Indexes or disable CONCURRENTLY.", mvSelect.Selected))
+				return
+			}
+		}
+		err := scheduler.SetSchedule(operation.RefreshSchedule{
+			MVName:       mvSelect.Selected,
+			Spec:         specEntry.Text,
+			Concurrently: concurrentlyCheck.Checked,
+			Enabled:      enabledCheck.Checked,
+		})
+		if err != nil {
+			showError(schedulesWindow, fmt.Sprintf("Invalid schedule: %v", err))
+			return
+		}
+		reload()
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	removeBtn := widget.NewButton("Remove Schedule", func() {
+		if mvSelect.Selected == "" {
+			return
+		}
+		if err := scheduler.RemoveSchedule(mvSelect.Selected); err != nil {
+			showError(schedulesWindow, err.Error())
+			return
+		}
+		reload()
+	})
+
+	var refreshBtn *widget.Button
+	refreshBtn = widget.NewButton("Refresh Now", func() {
+		name := mvSelect.Selected
+		if name == "" {
+			showError(schedulesWindow, "Select a materialized view")
+			return
+		}
+		refreshBtn.Disable()
+		statusLabel.SetText(fmt.Sprintf("Refreshing %s...", name))
+		concurrently := concurrentlyCheck.Checked
+		go func() {
+			entry := scheduler.RunNow(ctx, name, concurrently)
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				statusLabel.SetText(fmt.Sprintf("%s: %s", name, entry.Status()))
+				if entry.Error != "" {
+					showError(schedulesWindow, entry.Error)
+				}
+			})
+		}()
+	})
+
+	// History and next runs change in the background while the window is open
+	scheduler.SetOnRefresh(func(operation.RefreshHistoryEntry) {
+		fyne.Do(reload)
+	})
+	schedulesWindow.SetOnClosed(func() {
+		scheduler.SetOnRefresh(nil)
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem("Materialized View", mvSelect),
+		widget.NewFormItem("Schedule", specEntry),
+		widget.NewFormItem("", container.NewHBox(concurrentlyCheck, enabledCheck)),
+	)
+	top := container.NewVBox(
+		form,
+		container.NewHBox(saveBtn, removeBtn, refreshBtn, statusLabel),
+		widget.NewSeparator(),
+	)
+
+	split := container.NewVSplit(
+		container.NewBorder(widget.NewLabelWithStyle("Schedules", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			nil, nil, nil, schedulesBox),
+		container.NewBorder(widget.NewLabelWithStyle("Refresh History", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			nil, nil, nil, historyBox),
+	)
+	split.SetOffset(0.4)
+
+	schedulesWindow.SetContent(container.NewBorder(top, nil, nil, nil, split))
+	schedulesWindow.Resize(fyne.NewSize(900, 650))
+	schedulesWindow.CenterOnScreen()
+	schedulesWindow.Show()
+
+	mvSelect.SetSelected(mvs[0])
+}
@@ -0,0 +1,220 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ MATERIALIZED VIEW indexes and refresh pre-flight UI ============
+
+// refreshMaterializedView refreshes an MV through the scheduler, so the attempt is recorded in the
+// refresh history and skipped if a scheduled refresh of the same MV is already running
+func refreshMaterializedView(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, mvName string, concurrently bool) {
+	entry := getMVRefreshScheduler(ctx, pool).RunNow(ctx, mvName, concurrently)
+	if entry.Skipped {
+		showInfo(window, fmt.Sprintf("MATERIALIZED VIEW '%s' is already being refreshed, skipped", mvName))
+		return
+	}
+	if entry.Error != "" {
+		showError(window, entry.Error)
+		return
+	}
+	showInfo(window, fmt.Sprintf("MATERIALIZED VIEW '%s' refreshed successfully!", mvName))
+}
+
+// refreshMaterializedViewWithPreflight checks the CONCURRENTLY requirements before refreshing:
+// an unpopulated MV is offered a regular refresh, an MV without a unique index is offered to create one
+func refreshMaterializedViewWithPreflight(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, mvName string, concurrently bool) {
+	if !concurrently {
+		refreshMaterializedView(ctx, pool, window, mvName, false)
+		return
+	}
+
+	check, err := operation.CheckConcurrentRefresh(ctx, pool, mvName)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+	if check.Ready() {
+		refreshMaterializedView(ctx, pool, window, mvName, true)
+		return
+	}
+
+	if !check.Populated {
+		dialog.ShowConfirm("Refresh MATERIALIZED VIEW",
+			fmt.Sprintf("'%s' has never been populated (created WITH NO DATA), so CONCURRENTLY cannot be used.\n"+
+				"Run a regular REFRESH now?", mvName),
+			func(ok bool) {
+				if ok {
+					refreshMaterializedView(ctx, pool, window, mvName, false)
+				}
+			}, window)
+		return
+	}
+
+	message := fmt.Sprintf("REFRESH CONCURRENTLY needs a unique index on plain columns of '%s' (without WHERE).", mvName)
+	if len(check.CandidateColumns) > 0 {
+		message += fmt.Sprintf("\nColumns with unique non-NULL values: %s", strings.Join(check.CandidateColumns, ", "))
+	} else {
+		message += "\nNo single column is unique in the current data; choose several columns."
+	}
+
+	var preselected []string
+	if len(check.CandidateColumns) > 0 {
+		preselected = check.CandidateColumns[:1]
+	}
+
+	messageLabel := widget.NewLabel(message)
+	messageLabel.Wrapping = fyne.TextWrapWord
+
+	var preflightDialog dialog.Dialog
+	createBtn := widget.NewButton("Create Unique Index...", func() {
+		preflightDialog.Hide()
+		showCreateMVIndexDialog(ctx, pool, window, mvName, true, preselected, func() {
+			refreshMaterializedView(ctx, pool, window, mvName, true)
+		})
+	})
+	createBtn.Importance = widget.HighImportance
+	regularBtn := widget.NewButton("Regular REFRESH", func() {
+		preflightDialog.Hide()
+		refreshMaterializedView(ctx, pool, window, mvName, false)
+	})
+
+	content := container.NewVBox(messageLabel, container.NewHBox(createBtn, regularBtn))
+	preflightDialog = dialog.NewCustom("REFRESH CONCURRENTLY pre-flight", "Cancel", content, window)
+	preflightDialog.Resize(fyne.NewSize(500, 200))
+	preflightDialog.Show()
+}
+
+// showCreateMVIndexDialog asks for index columns of a materialized view and creates the index
+func showCreateMVIndexDialog(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, mvName string,
+	unique bool, preselected []string, onCreated func()) {
+	columns, err := operation.GetMaterializedViewColumns(ctx, pool, mvName)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("optional, generated from the columns")
+	columnsGroup := widget.NewCheckGroup(columns, nil)
+	columnsGroup.SetSelected(preselected)
+	uniqueCheck := widget.NewCheck("UNIQUE (required for REFRESH CONCURRENTLY)", nil)
+	uniqueCheck.SetChecked(unique)
+
+	form := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Index Name", nameEntry)),
+		widget.NewLabel("Columns (in the order they are checked):"),
+		container.NewVScroll(columnsGroup),
+		uniqueCheck,
+	)
+
+	confirm := dialog.NewCustomConfirm(fmt.Sprintf("Create INDEX on %s", mvName), "Create", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		err := operation.CreateMaterializedViewIndex(ctx, pool, mvName, nameEntry.Text, columnsGroup.Selected, uniqueCheck.Checked)
+		if err != nil {
+			showError(window, fmt.Sprintf("Failed to create index: %v", err))
+			return
+		}
+		if onCreated != nil {
+			onCreated()
+		}
+	}, window)
+	confirm.Resize(fyne.NewSize(450, 450))
+	confirm.Show()
+}
+
+// UIMaterializedViewIndexes lists, creates and drops indexes of materialized views
+func UIMaterializedViewIndexes(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	mvs, err := operation.ListAllMaterializedViews(ctx, pool)
+	if err != nil {
+		showError(window, fmt.Sprintf("Failed to list materialized views: %v", err))
+		return
+	}
+	if len(mvs) == 0 {
+		showInfo(window, "There are no materialized views in the public schema")
+		return
+	}
+
+	indexesWindow := fyne.CurrentApp().NewWindow("MATERIALIZED VIEW Indexes")
+
+	mvSelect := widget.NewSelect(mvs, nil)
+	indexSelect := widget.NewSelect(nil, nil)
+	indexSelect.PlaceHolder = "Select index to drop"
+	indexesBox := container.NewStack()
+
+	yesNo := func(b bool) string {
+		if b {
+			return "YES"
+		}
+		return "NO"
+	}
+
+	reload := func() {
+		indexes, err := operation.GetMaterializedViewIndexes(ctx, pool, mvSelect.Selected)
+		if err != nil {
+			showError(indexesWindow, err.Error())
+			return
+		}
+		data := [][]string{{"Index", "Unique", "Usable for CONCURRENTLY", "Size", "Definition"}}
+		var names []string
+		for _, idx := range indexes {
+			data = append(data, []string{idx.Name, yesNo(idx.Unique), yesNo(idx.UsableForConcurrently), idx.Size, idx.Definition})
+			names = append(names, idx.Name)
+		}
+		indexesBox.Objects = []fyne.CanvasObject{container.NewScroll(newReportTable(data))}
+		indexesBox.Refresh()
+		indexSelect.Options = names
+		indexSelect.ClearSelected()
+	}
+	mvSelect.OnChanged = func(string) { reload() }
+
+	createBtn := widget.NewButton("Create Index...", func() {
+		if mvSelect.Selected == "" {
+			return
+		}
+		showCreateMVIndexDialog(ctx, pool, indexesWindow, mvSelect.Selected, false, nil, reload)
+	})
+	createBtn.Importance = widget.HighImportance
+
+	dropBtn := widget.NewButton("Drop Index", func() {
+		indexName := indexSelect.Selected
+		if indexName == "" {
+			showError(indexesWindow, "Select an index to drop")
+			return
+		}
+		dialog.ShowConfirm("Drop INDEX", fmt.Sprintf("Drop index '%s'?", indexName), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := operation.DropMaterializedViewIndex(ctx, pool, indexName); err != nil {
+				showError(indexesWindow, err.Error())
+				return
+			}
+			reload()
+		}, indexesWindow)
+	})
+	dropBtn.Importance = widget.DangerImportance
+
+	top := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Materialized View", mvSelect)),
+		container.NewHBox(createBtn, widget.NewSeparator(), indexSelect, dropBtn),
+	)
+
+	indexesWindow.SetContent(container.NewBorder(top, nil, nil, nil, indexesBox))
+	indexesWindow.Resize(fyne.NewSize(900, 450))
+	indexesWindow.CenterOnScreen()
+	indexesWindow.Show()
+
+	mvSelect.SetSelected(mvs[0])
+}
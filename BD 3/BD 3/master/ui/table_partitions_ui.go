@@ -0,0 +1,414 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для партиционирования таблиц ==========
+
+// UICreatePartitionedTable создаёт диалог для создания партиционированной таблицы
+func UICreatePartitionedTable(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tableNameEntry := widget.NewEntry()
+	tableNameEntry.SetPlaceHolder("Имя таблицы (например, events)")
+
+	columnsEntry := widget.NewMultiLineEntry()
+	columnsEntry.SetPlaceHolder("Столбцы (формат: имя тип ограничения)\nПример:\nid BIGSERIAL\ncreated_at TIMESTAMP NOT NULL\npayload JSONB")
+	columnsEntry.SetMinRowsVisible(6)
+
+	constraintsEntry := widget.NewMultiLineEntry()
+	constraintsEntry.SetPlaceHolder("Ограничения таблицы (каждое с новой строки)\nПример: PRIMARY KEY (id, created_at)")
+	constraintsEntry.SetMinRowsVisible(2)
+
+	strategySelect := widget.NewSelect([]string{
+		string(operation.PartitionByRange),
+		string(operation.PartitionByList),
+		string(operation.PartitionByHash),
+	}, nil)
+	strategySelect.SetSelected(string(operation.PartitionByRange))
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder("Ключ партиционирования (например, created_at)")
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Имя таблицы", tableNameEntry),
+			widget.NewFormItem("Стратегия", strategySelect),
+			widget.NewFormItem("Ключ", keyEntry),
+		),
+		widget.NewLabel("Определения столбцов:"),
+		columnsEntry,
+		widget.NewLabel("Ограничения уровня таблицы:"),
+		constraintsEntry,
+	)
+
+	dlg := dialog.NewCustomConfirm("Создать партиционированную таблицу", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		tableName := strings.TrimSpace(tableNameEntry.Text)
+		if tableName == "" {
+			showError(window, "Укажите имя таблицы")
+			return
+		}
+
+		columns, err := operation.ParseColumnDefinitions(columnsEntry.Text)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		if len(columns) == 0 {
+			showError(window, "Необходимо указать хотя бы один столбец")
+			return
+		}
+
+		var constraints []string
+		for _, line := range strings.Split(constraintsEntry.Text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				constraints = append(constraints, line)
+			}
+		}
+
+		err = operation.CreatePartitionedTable(ctx, pool, tableName, columns, constraints,
+			operation.PartitionStrategy(strategySelect.Selected), strings.TrimSpace(keyEntry.Text))
+		if err != nil {
+			showError(window, "Ошибка создания таблицы: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Партиционированная таблица '%s' создана!", tableName))
+	}, window)
+
+	dlg.Resize(fyne.NewSize(650, 550))
+	dlg.Show()
+}
+
+// newPartitionBoundForm создаёт поля ввода границы партиции и функцию её сборки
+func newPartitionBoundForm() (*fyne.Container, func() (string, error)) {
+	kindSelect := widget.NewSelect([]string{"RANGE", "LIST", "HASH", "DEFAULT"}, nil)
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("FROM, например '2025-01-01'")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("TO, например '2025-02-01'")
+	valuesEntry := widget.NewEntry()
+	valuesEntry.SetPlaceHolder("Значения через запятую, например 'ru', 'by'")
+	modulusEntry := widget.NewEntry()
+	modulusEntry.SetPlaceHolder("MODULUS")
+	remainderEntry := widget.NewEntry()
+	remainderEntry.SetPlaceHolder("REMAINDER")
+
+	rangeForm := widget.NewForm(
+		widget.NewFormItem("FROM", fromEntry),
+		widget.NewFormItem("TO", toEntry),
+	)
+	listForm := widget.NewForm(widget.NewFormItem("IN", valuesEntry))
+	hashForm := widget.NewForm(
+		widget.NewFormItem("MODULUS", modulusEntry),
+		widget.NewFormItem("REMAINDER", remainderEntry),
+	)
+
+	kindSelect.OnChanged = func(kind string) {
+		rangeForm.Hide()
+		listForm.Hide()
+		hashForm.Hide()
+		switch kind {
+		case "RANGE":
+			rangeForm.Show()
+		case "LIST":
+			listForm.Show()
+		case "HASH":
+			hashForm.Show()
+		}
+	}
+	kindSelect.SetSelected("RANGE")
+
+	build := func() (string, error) {
+		switch kindSelect.Selected {
+		case "RANGE":
+			from, to := strings.TrimSpace(fromEntry.Text), strings.TrimSpace(toEntry.Text)
+			if from == "" || to == "" {
+				return "", fmt.Errorf("укажите границы FROM и TO")
+			}
+			return operation.RangeBound(from, to), nil
+		case "LIST":
+			var values []string
+			for _, v := range strings.Split(valuesEntry.Text, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+			if len(values) == 0 {
+				return "", fmt.Errorf("укажите хотя бы одно значение")
+			}
+			return operation.ListBound(values...), nil
+		case "HASH":
+			modulus, err := strconv.Atoi(strings.TrimSpace(modulusEntry.Text))
+			if err != nil || modulus <= 0 {
+				return "", fmt.Errorf("неверный MODULUS")
+			}
+			remainder, err := strconv.Atoi(strings.TrimSpace(remainderEntry.Text))
+			if err != nil || remainder < 0 || remainder >= modulus {
+				return "", fmt.Errorf("неверный REMAINDER")
+			}
+			return operation.HashBound(modulus, remainder), nil
+		default:
+			return operation.DefaultPartitionBound, nil
+		}
+	}
+
+	content := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Тип границы", kindSelect)),
+		rangeForm,
+		listForm,
+		hashForm,
+	)
+	return content, build
+}
+
+// UICreatePartition создаёт диалог для создания партиции
+func UICreatePartition(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	parents, _ := operation.ListPartitionedTables(ctx, pool)
+	parentSelect := widget.NewSelect(parents, nil)
+	parentSelect.PlaceHolder = "Партиционированная таблица"
+
+	partitionEntry := widget.NewEntry()
+	partitionEntry.SetPlaceHolder("Имя партиции (например, events_2025_01)")
+
+	boundForm, buildBound := newPartitionBoundForm()
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Родительская таблица", parentSelect),
+			widget.NewFormItem("Имя партиции", partitionEntry),
+		),
+		boundForm,
+	)
+
+	dlg := dialog.NewCustomConfirm("Создать партицию", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		bound, err := buildBound()
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+
+		partitionName := strings.TrimSpace(partitionEntry.Text)
+		err = operation.CreatePartition(ctx, pool, parentSelect.Selected, partitionName, bound)
+		if err != nil {
+			showError(window, "Ошибка создания партиции: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Партиция '%s' создана!", partitionName))
+	}, window)
+
+	dlg.Resize(fyne.NewSize(550, 400))
+	dlg.Show()
+}
+
+// UIAttachPartition создаёт диалог для подключения таблицы как партиции
+func UIAttachPartition(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	parents, _ := operation.ListPartitionedTables(ctx, pool)
+	parentSelect := widget.NewSelect(parents, nil)
+	parentSelect.PlaceHolder = "Партиционированная таблица"
+
+	tables, _ := getTablesListFromDB(ctx, pool)
+	tableSelect := widget.NewSelect(tables, nil)
+	tableSelect.PlaceHolder = "Подключаемая таблица"
+
+	boundForm, buildBound := newPartitionBoundForm()
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Родительская таблица", parentSelect),
+			widget.NewFormItem("Таблица", tableSelect),
+		),
+		boundForm,
+	)
+
+	dlg := dialog.NewCustomConfirm("Подключить партицию", "Подключить", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		bound, err := buildBound()
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+
+		err = operation.AttachPartition(ctx, pool, parentSelect.Selected, tableSelect.Selected, bound)
+		if err != nil {
+			showError(window, "Ошибка подключения партиции: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Таблица '%s' подключена к '%s'!", tableSelect.Selected, parentSelect.Selected))
+	}, window)
+
+	dlg.Resize(fyne.NewSize(550, 400))
+	dlg.Show()
+}
+
+// UIDetachPartition создаёт диалог для отключения партиции
+func UIDetachPartition(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	parents, _ := operation.ListPartitionedTables(ctx, pool)
+
+	partitionSelect := widget.NewSelect(nil, nil)
+	partitionSelect.PlaceHolder = "Партиция"
+
+	parentSelect := widget.NewSelect(parents, func(parent string) {
+		tree, err := operation.GetPartitionTree(ctx, pool, parent)
+		if err != nil {
+			showError(window, "Ошибка: "+err.Error())
+			return
+		}
+		var children []string
+		for _, p := range tree {
+			if p.Parent == parent {
+				children = append(children, p.Name)
+			}
+		}
+		partitionSelect.Options = children
+		partitionSelect.ClearSelected()
+	})
+	parentSelect.PlaceHolder = "Партиционированная таблица"
+
+	concurrentlyCheck := widget.NewCheck("DETACH CONCURRENTLY (PostgreSQL 14+)", nil)
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Родительская таблица", parentSelect),
+			widget.NewFormItem("Партиция", partitionSelect),
+		),
+		concurrentlyCheck,
+	)
+
+	dialog.ShowCustomConfirm("Отключить партицию", "Отключить", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		err := operation.DetachPartition(ctx, pool, parentSelect.Selected, partitionSelect.Selected, concurrentlyCheck.Checked)
+		if err != nil {
+			showError(window, "Ошибка отключения партиции: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Партиция '%s' отключена!", partitionSelect.Selected))
+	}, window)
+}
+
+// UIPartitionTree показывает дерево партиций таблицы
+func UIPartitionTree(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	parents, _ := operation.ListPartitionedTables(ctx, pool)
+	parentSelect := widget.NewSelect(parents, nil)
+	parentSelect.PlaceHolder = "Партиционированная таблица"
+
+	form := widget.NewForm(
+		widget.NewFormItem("Таблица", parentSelect),
+	)
+
+	dialog.ShowCustomConfirm("Дерево партиций", "Показать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		tree, err := operation.GetPartitionTree(ctx, pool, parentSelect.Selected)
+		if err != nil {
+			showError(window, "Ошибка: "+err.Error())
+			return
+		}
+
+		tableData := [][]string{{"Партиция", "Родитель", "Уровень", "Граница", "Стратегия / ключ"}}
+		for _, p := range tree {
+			name := strings.Repeat("    ", p.Level) + p.Name
+			partitioning := "—"
+			if p.Key != "" {
+				partitioning = p.Key
+			}
+			bound := p.Bound
+			if bound == "" {
+				bound = "—"
+			}
+			tableData = append(tableData, []string{name, p.Parent, strconv.Itoa(p.Level), bound, partitioning})
+		}
+
+		table, err := CreateTable(tableData)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		setOptimalColumnWidths(table, tableData)
+
+		treeWindow := fyne.CurrentApp().NewWindow("Дерево партиций: " + parentSelect.Selected)
+		treeWindow.SetContent(container.NewScroll(table))
+		treeWindow.Resize(fyne.NewSize(900, 500))
+		treeWindow.CenterOnScreen()
+		treeWindow.Show()
+	}, window)
+}
+
+// UICreateFuturePartitions создаёт месячные RANGE-партиции на несколько месяцев вперёд
+func UICreateFuturePartitions(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	parents, _ := operation.ListPartitionedTables(ctx, pool)
+	parentSelect := widget.NewSelect(parents, nil)
+	parentSelect.PlaceHolder = "Таблица с RANGE по дате"
+
+	monthsEntry := widget.NewEntry()
+	monthsEntry.SetText("3")
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Таблица", parentSelect),
+			widget.NewFormItem("Месяцев вперёд", monthsEntry),
+		),
+		widget.NewLabel("Партиции именуются как <таблица>_ГГГГ_ММ,\nсуществующие пропускаются."),
+	)
+
+	dialog.ShowCustomConfirm("Создать будущие партиции", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		months, err := strconv.Atoi(strings.TrimSpace(monthsEntry.Text))
+		if err != nil {
+			showError(window, "Неверное количество месяцев")
+			return
+		}
+
+		created, skipped, err := operation.EnsureMonthlyPartitions(ctx, pool, parentSelect.Selected, time.Now(), months)
+		if err != nil {
+			showError(window, "Ошибка создания партиций: "+err.Error())
+			return
+		}
+
+		var report []string
+		if len(created) > 0 {
+			report = append(report, "Созданы партиции:\n"+strings.Join(created, "\n"))
+		}
+		if len(skipped) > 0 {
+			report = append(report, "Пропущены месяцы:\n"+strings.Join(skipped, "\n"))
+		}
+		if len(report) == 0 {
+			showInfo(window, "Все партиции уже существуют")
+			return
+		}
+		showInfo(window, strings.Join(report, "\n\n"))
+	}, window)
+}
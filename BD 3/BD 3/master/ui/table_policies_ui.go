@@ -0,0 +1,297 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для политик строкового доступа (RLS) ==========
+
+// UIRowLevelSecurity открывает окно управления RLS и политиками таблицы
+func UIRowLevelSecurity(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tables, err := getTablesListFromDB(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения таблиц: "+err.Error())
+		return
+	}
+
+	rlsWindow := fyne.CurrentApp().NewWindow("Row-Level Security")
+
+	var policies []operation.PolicyDefinition
+	selected := -1
+
+	enabledCheck := widget.NewCheck("ENABLE ROW LEVEL SECURITY", nil)
+	forcedCheck := widget.NewCheck("FORCE (в том числе для владельца)", nil)
+
+	policyList := widget.NewList(
+		func() int {
+			return len(policies)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("policy")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			p := policies[id]
+			roles := "PUBLIC"
+			if len(p.Roles) > 0 {
+				roles = strings.Join(p.Roles, ", ")
+			}
+			text := fmt.Sprintf("%s: FOR %s TO %s", p.Name, p.Command, roles)
+			if !p.Permissive {
+				text += " [RESTRICTIVE]"
+			}
+			if p.Using != "" {
+				text += "  USING (" + p.Using + ")"
+			}
+			if p.WithCheck != "" {
+				text += "  WITH CHECK (" + p.WithCheck + ")"
+			}
+			obj.(*widget.Label).SetText(text)
+		},
+	)
+	policyList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+	}
+
+	tableSelect := widget.NewSelect(tables, nil)
+	tableSelect.PlaceHolder = "Таблица"
+
+	reload := func() {
+		if tableSelect.Selected == "" {
+			return
+		}
+		status, err := operation.GetRowLevelSecurity(ctx, pool, tableSelect.Selected)
+		if err != nil {
+			showError(rlsWindow, err.Error())
+			return
+		}
+		enabledCheck.SetChecked(status.Enabled)
+		forcedCheck.SetChecked(status.Forced)
+
+		list, err := operation.ListPolicies(ctx, pool, tableSelect.Selected)
+		if err != nil {
+			showError(rlsWindow, err.Error())
+			return
+		}
+		policies = list
+		selected = -1
+		policyList.UnselectAll()
+		policyList.Refresh()
+	}
+	tableSelect.OnChanged = func(string) {
+		reload()
+	}
+
+	applyBtn := widget.NewButton("Применить RLS", func() {
+		if tableSelect.Selected == "" {
+			showError(rlsWindow, "Выберите таблицу")
+			return
+		}
+		err := operation.SetRowLevelSecurity(ctx, pool, tableSelect.Selected, enabledCheck.Checked, forcedCheck.Checked)
+		if err != nil {
+			showError(rlsWindow, err.Error())
+			return
+		}
+		showInfo(rlsWindow, "Настройки RLS применены")
+		reload()
+	})
+
+	createBtn := widget.NewButton("➕ Политика", func() {
+		if tableSelect.Selected == "" {
+			showError(rlsWindow, "Выберите таблицу")
+			return
+		}
+		showPolicyEditor(ctx, pool, rlsWindow, operation.PolicyDefinition{
+			Table:      tableSelect.Selected,
+			Permissive: true,
+			Command:    "ALL",
+		}, "", reload)
+	})
+
+	editBtn := widget.NewButton("✏ Изменить", func() {
+		if selected < 0 || selected >= len(policies) {
+			showError(rlsWindow, "Выберите политику")
+			return
+		}
+		p := policies[selected]
+		showPolicyEditor(ctx, pool, rlsWindow, p, p.Name, reload)
+	})
+
+	dropBtn := widget.NewButton("🗑 Удалить", func() {
+		if selected < 0 || selected >= len(policies) {
+			showError(rlsWindow, "Выберите политику")
+			return
+		}
+		p := policies[selected]
+		dialog.ShowConfirm("Удалить политику", fmt.Sprintf("Удалить политику '%s' на '%s'?", p.Name, p.Table), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := operation.DropPolicy(ctx, pool, p.Table, p.Name); err != nil {
+				showError(rlsWindow, err.Error())
+				return
+			}
+			reload()
+		}, rlsWindow)
+	})
+
+	testBtn := widget.NewButton("👤 Проверить от имени роли", func() {
+		if tableSelect.Selected == "" {
+			showError(rlsWindow, "Выберите таблицу")
+			return
+		}
+		showSelectAsRole(ctx, pool, rlsWindow, tableSelect.Selected)
+	})
+
+	top := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Таблица", tableSelect)),
+		container.NewHBox(enabledCheck, forcedCheck, applyBtn),
+		widget.NewSeparator(),
+		container.NewHBox(createBtn, editBtn, dropBtn, testBtn),
+	)
+
+	rlsWindow.SetContent(container.NewBorder(top, nil, nil, nil, policyList))
+	rlsWindow.Resize(fyne.NewSize(900, 550))
+	rlsWindow.CenterOnScreen()
+	rlsWindow.Show()
+}
+
+// showPolicyEditor показывает диалог создания (oldName пусто) или изменения политики
+func showPolicyEditor(ctx context.Context, pool *pgxpool.Pool, window fyne.Window,
+	def operation.PolicyDefinition, oldName string, onSaved func()) {
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя политики")
+	nameEntry.SetText(def.Name)
+
+	commandSelect := widget.NewSelect(operation.PolicyCommands, nil)
+	commandSelect.SetSelected(def.Command)
+
+	permissiveSelect := widget.NewSelect([]string{"PERMISSIVE", "RESTRICTIVE"}, nil)
+	if def.Permissive {
+		permissiveSelect.SetSelected("PERMISSIVE")
+	} else {
+		permissiveSelect.SetSelected("RESTRICTIVE")
+	}
+
+	rolesEntry := widget.NewEntry()
+	rolesEntry.SetPlaceHolder("Роли через запятую (пусто — PUBLIC)")
+	rolesEntry.SetText(strings.Join(def.Roles, ", "))
+
+	usingEntry := widget.NewMultiLineEntry()
+	usingEntry.SetPlaceHolder("Например: owner = current_user")
+	usingEntry.SetText(def.Using)
+
+	withCheckEntry := widget.NewMultiLineEntry()
+	withCheckEntry.SetPlaceHolder("Например: owner = current_user")
+	withCheckEntry.SetText(def.WithCheck)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Имя", nameEntry),
+		widget.NewFormItem("Команда", commandSelect),
+		widget.NewFormItem("Тип", permissiveSelect),
+		widget.NewFormItem("Роли", rolesEntry),
+		widget.NewFormItem("USING", usingEntry),
+		widget.NewFormItem("WITH CHECK", withCheckEntry),
+	)
+
+	title := "Создать политику на " + def.Table
+	if oldName != "" {
+		title = "Изменить политику " + oldName
+	}
+
+	dlg := dialog.NewCustomConfirm(title, "Сохранить", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var roles []string
+		for _, r := range strings.Split(rolesEntry.Text, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				roles = append(roles, r)
+			}
+		}
+
+		newDef := operation.PolicyDefinition{
+			Name:       strings.TrimSpace(nameEntry.Text),
+			Table:      def.Table,
+			Permissive: permissiveSelect.Selected != "RESTRICTIVE",
+			Command:    commandSelect.Selected,
+			Roles:      roles,
+			Using:      usingEntry.Text,
+			WithCheck:  withCheckEntry.Text,
+		}
+
+		var err error
+		if oldName == "" {
+			err = operation.CreatePolicy(ctx, pool, newDef)
+		} else {
+			err = operation.ReplacePolicy(ctx, pool, oldName, newDef)
+		}
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Политика '%s' сохранена!", newDef.Name))
+		if onSaved != nil {
+			onSaved()
+		}
+	}, window)
+
+	dlg.Resize(fyne.NewSize(600, 500))
+	dlg.Show()
+}
+
+// showSelectAsRole выполняет SELECT под выбранной ролью и показывает видимые ей строки
+func showSelectAsRole(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, tableName string) {
+	roles, err := operation.ListRoles(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения ролей: "+err.Error())
+		return
+	}
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+	}
+	roleSelect := widget.NewSelect(roleNames, nil)
+
+	form := widget.NewForm(widget.NewFormItem("Роль", roleSelect))
+
+	dialog.ShowCustomConfirm("Проверить от имени роли", "Выполнить", "Отмена", form, func(ok bool) {
+		if !ok || roleSelect.Selected == "" {
+			return
+		}
+
+		data, err := operation.SelectAsRole(ctx, pool, tableName, roleSelect.Selected, 500)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+
+		resultTable, err := CreateTable(data)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		setOptimalColumnWidths(resultTable, data)
+
+		resultWindow := fyne.CurrentApp().NewWindow(fmt.Sprintf("%s глазами роли %s", tableName, roleSelect.Selected))
+		resultWindow.SetContent(container.NewBorder(
+			widget.NewLabel(fmt.Sprintf("Видимых строк: %d (не более 500)", len(data)-1)),
+			nil, nil, nil,
+			container.NewScroll(resultTable),
+		))
+		resultWindow.Resize(fyne.NewSize(900, 500))
+		resultWindow.CenterOnScreen()
+		resultWindow.Show()
+	}, window)
+}
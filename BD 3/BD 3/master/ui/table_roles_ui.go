@@ -0,0 +1,440 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для ролей и привилегий ==========
+
+// boolMark отображает логическое значение в таблице
+func boolMark(value bool) string {
+	if value {
+		return "✓"
+	}
+	return ""
+}
+
+// UIListRoles показывает все роли и их атрибуты
+func UIListRoles(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	roles, err := operation.ListRoles(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения ролей: "+err.Error())
+		return
+	}
+
+	tableData := [][]string{{"Роль", "LOGIN", "SUPERUSER", "CREATEDB", "CREATEROLE", "INHERIT", "Лимит", "Действует до", "Член ролей"}}
+	for _, r := range roles {
+		limit := strconv.Itoa(r.ConnLimit)
+		if r.ConnLimit < 0 {
+			limit = "∞"
+		}
+		tableData = append(tableData, []string{
+			r.Name,
+			boolMark(r.CanLogin),
+			boolMark(r.Superuser),
+			boolMark(r.CreateDB),
+			boolMark(r.CreateRole),
+			boolMark(r.Inherit),
+			limit,
+			r.ValidUntil,
+			strings.Join(r.MemberOf, ", "),
+		})
+	}
+
+	table, err := CreateTable(tableData)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+	setOptimalColumnWidths(table, tableData)
+
+	rolesWindow := fyne.CurrentApp().NewWindow("Роли")
+	rolesWindow.SetContent(container.NewScroll(table))
+	rolesWindow.Resize(fyne.NewSize(1000, 500))
+	rolesWindow.CenterOnScreen()
+	rolesWindow.Show()
+}
+
+// roleOptionsForm создаёт поля атрибутов роли и функцию их чтения
+func roleOptionsForm(initial *operation.RoleInfo) (*fyne.Container, func() (operation.RoleOptions, error)) {
+	loginCheck := widget.NewCheck("LOGIN", nil)
+	superuserCheck := widget.NewCheck("SUPERUSER", nil)
+	createDBCheck := widget.NewCheck("CREATEDB", nil)
+	createRoleCheck := widget.NewCheck("CREATEROLE", nil)
+	inheritCheck := widget.NewCheck("INHERIT", nil)
+	inheritCheck.SetChecked(true)
+
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Пароль (пусто — не менять)")
+
+	connLimitEntry := widget.NewEntry()
+	connLimitEntry.SetText("-1")
+
+	validUntilEntry := widget.NewEntry()
+	validUntilEntry.SetPlaceHolder("Например, 2026-12-31 (пусто — бессрочно)")
+
+	if initial != nil {
+		loginCheck.SetChecked(initial.CanLogin)
+		superuserCheck.SetChecked(initial.Superuser)
+		createDBCheck.SetChecked(initial.CreateDB)
+		createRoleCheck.SetChecked(initial.CreateRole)
+		inheritCheck.SetChecked(initial.Inherit)
+		connLimitEntry.SetText(strconv.Itoa(initial.ConnLimit))
+		validUntilEntry.SetText(initial.ValidUntil)
+	}
+
+	content := container.NewVBox(
+		container.NewGridWithColumns(3, loginCheck, superuserCheck, createDBCheck, createRoleCheck, inheritCheck),
+		widget.NewForm(
+			widget.NewFormItem("Пароль", passwordEntry),
+			widget.NewFormItem("Лимит подключений", connLimitEntry),
+			widget.NewFormItem("Действует до", validUntilEntry),
+		),
+	)
+
+	read := func() (operation.RoleOptions, error) {
+		limit, err := strconv.Atoi(strings.TrimSpace(connLimitEntry.Text))
+		if err != nil {
+			return operation.RoleOptions{}, fmt.Errorf("неверный лимит подключений")
+		}
+		return operation.RoleOptions{
+			Login:           loginCheck.Checked,
+			Superuser:       superuserCheck.Checked,
+			CreateDB:        createDBCheck.Checked,
+			CreateRole:      createRoleCheck.Checked,
+			Inherit:         inheritCheck.Checked,
+			Password:        passwordEntry.Text,
+			ConnectionLimit: limit,
+			ValidUntil:      strings.TrimSpace(validUntilEntry.Text),
+		}, nil
+	}
+
+	return content, read
+}
+
+// UICreateRole создаёт диалог для создания роли
+func UICreateRole(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя роли")
+
+	memberOfEntry := widget.NewEntry()
+	memberOfEntry.SetPlaceHolder("Группы через запятую (необязательно)")
+
+	optionsForm, readOptions := roleOptionsForm(nil)
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Имя роли", nameEntry),
+			widget.NewFormItem("Член ролей", memberOfEntry),
+		),
+		optionsForm,
+	)
+
+	dlg := dialog.NewCustomConfirm("Создать роль", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		opts, err := readOptions()
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+
+		var memberOf []string
+		for _, g := range strings.Split(memberOfEntry.Text, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				memberOf = append(memberOf, g)
+			}
+		}
+
+		name := strings.TrimSpace(nameEntry.Text)
+		if err := operation.CreateRole(ctx, pool, name, opts, memberOf); err != nil {
+			showError(window, "Ошибка создания роли: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Роль '%s' создана!", name))
+	}, window)
+
+	dlg.Resize(fyne.NewSize(550, 400))
+	dlg.Show()
+}
+
+// UIAlterRole создаёт диалог для изменения атрибутов и членства роли
+func UIAlterRole(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	roles, err := operation.ListRoles(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения ролей: "+err.Error())
+		return
+	}
+
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+	}
+
+	content := container.NewVBox()
+	roleSelect := widget.NewSelect(roleNames, nil)
+	roleSelect.PlaceHolder = "Роль"
+
+	var readOptions func() (operation.RoleOptions, error)
+
+	groupSelect := widget.NewSelect(roleNames, nil)
+	groupSelect.PlaceHolder = "Группа"
+
+	grantBtn := widget.NewButton("Добавить в группу", func() {
+		if err := operation.GrantRoleMembership(ctx, pool, groupSelect.Selected, roleSelect.Selected); err != nil {
+			showError(window, err.Error())
+			return
+		}
+		showInfo(window, fmt.Sprintf("'%s' добавлена в '%s'", roleSelect.Selected, groupSelect.Selected))
+	})
+	revokeBtn := widget.NewButton("Исключить из группы", func() {
+		if err := operation.RevokeRoleMembership(ctx, pool, groupSelect.Selected, roleSelect.Selected); err != nil {
+			showError(window, err.Error())
+			return
+		}
+		showInfo(window, fmt.Sprintf("'%s' исключена из '%s'", roleSelect.Selected, groupSelect.Selected))
+	})
+
+	membershipLabel := widget.NewLabel("")
+
+	roleSelect.OnChanged = func(name string) {
+		for i := range roles {
+			if roles[i].Name != name {
+				continue
+			}
+			var optionsForm *fyne.Container
+			optionsForm, readOptions = roleOptionsForm(&roles[i])
+			membershipLabel.SetText("Член ролей: " + strings.Join(roles[i].MemberOf, ", "))
+			content.Objects = []fyne.CanvasObject{
+				optionsForm,
+				widget.NewSeparator(),
+				membershipLabel,
+				container.NewHBox(groupSelect, grantBtn, revokeBtn),
+			}
+			content.Refresh()
+		}
+	}
+
+	form := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Роль", roleSelect)),
+		content,
+	)
+
+	dlg := dialog.NewCustomConfirm("Изменить роль", "Сохранить", "Отмена", form, func(ok bool) {
+		if !ok || readOptions == nil {
+			return
+		}
+
+		opts, err := readOptions()
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		if err := operation.AlterRole(ctx, pool, roleSelect.Selected, opts); err != nil {
+			showError(window, "Ошибка изменения роли: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Роль '%s' изменена!", roleSelect.Selected))
+	}, window)
+
+	dlg.Resize(fyne.NewSize(600, 450))
+	dlg.Show()
+}
+
+// UIDropRole удаляет роль
+func UIDropRole(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	roles, _ := operation.ListRoles(ctx, pool)
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+	}
+	roleSelect := widget.NewSelect(roleNames, nil)
+
+	form := widget.NewForm(widget.NewFormItem("Роль", roleSelect))
+
+	dialog.ShowCustomConfirm("Удалить роль", "Удалить", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := operation.DropRole(ctx, pool, roleSelect.Selected); err != nil {
+			showError(window, "Ошибка удаления роли: "+err.Error())
+			return
+		}
+		showInfo(window, fmt.Sprintf("Роль '%s' удалена!", roleSelect.Selected))
+	}, window)
+}
+
+// UIGrantRevoke создаёт диалог выдачи и отзыва привилегий
+func UIGrantRevoke(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	roles, _ := operation.ListRoles(ctx, pool)
+	grantees := []string{"PUBLIC"}
+	for _, r := range roles {
+		grantees = append(grantees, r.Name)
+	}
+	granteeSelect := widget.NewSelect(grantees, nil)
+	granteeSelect.PlaceHolder = "Роль"
+
+	objectSelect := widget.NewSelect(nil, nil)
+	objectSelect.PlaceHolder = "Объект"
+
+	columnsEntry := widget.NewEntry()
+	columnsEntry.SetPlaceHolder("Столбцы через запятую")
+	columnsEntry.Hide()
+
+	privilegeChecks := widget.NewCheckGroup(nil, nil)
+	privilegeChecks.Horizontal = true
+
+	grantOptionCheck := widget.NewCheck("WITH GRANT OPTION", nil)
+	cascadeCheck := widget.NewCheck("REVOKE ... CASCADE", nil)
+
+	objectTypeSelect := widget.NewSelect(operation.PrivilegeObjectTypes, func(objectType string) {
+		objects, err := operation.ListPrivilegeObjects(ctx, pool, objectType)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		objectSelect.Options = objects
+		objectSelect.ClearSelected()
+		privilegeChecks.Options = operation.ObjectPrivileges(objectType)
+		privilegeChecks.SetSelected(nil)
+		privilegeChecks.Refresh()
+		if objectType == operation.ObjectColumn {
+			columnsEntry.Show()
+		} else {
+			columnsEntry.Hide()
+		}
+	})
+	objectTypeSelect.SetSelected(operation.ObjectTable)
+
+	readColumns := func() []string {
+		var columns []string
+		for _, c := range strings.Split(columnsEntry.Text, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				columns = append(columns, c)
+			}
+		}
+		return columns
+	}
+
+	grantBtn := widget.NewButton("GRANT", func() {
+		err := operation.GrantPrivileges(ctx, pool, objectTypeSelect.Selected, objectSelect.Selected,
+			readColumns(), privilegeChecks.Selected, granteeSelect.Selected, grantOptionCheck.Checked)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		showInfo(window, "Привилегии выданы!")
+	})
+
+	revokeBtn := widget.NewButton("REVOKE", func() {
+		err := operation.RevokePrivileges(ctx, pool, objectTypeSelect.Selected, objectSelect.Selected,
+			readColumns(), privilegeChecks.Selected, granteeSelect.Selected, cascadeCheck.Checked)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		showInfo(window, "Привилегии отозваны!")
+	})
+
+	matrixBtn := widget.NewButton("Матрица привилегий", func() {
+		showPrivilegeMatrix(ctx, pool, window, objectTypeSelect.Selected, objectSelect.Selected)
+	})
+
+	content := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Тип объекта", objectTypeSelect),
+			widget.NewFormItem("Объект", objectSelect),
+			widget.NewFormItem("Получатель", granteeSelect),
+		),
+		columnsEntry,
+		widget.NewLabel("Привилегии:"),
+		privilegeChecks,
+		container.NewHBox(grantOptionCheck, cascadeCheck),
+		container.NewHBox(grantBtn, revokeBtn, matrixBtn),
+	)
+
+	grantWindow := fyne.CurrentApp().NewWindow("GRANT / REVOKE")
+	grantWindow.SetContent(container.NewPadded(content))
+	grantWindow.Resize(fyne.NewSize(750, 400))
+	grantWindow.CenterOnScreen()
+	grantWindow.Show()
+}
+
+// showPrivilegeMatrix показывает матрицу привилегий объекта
+func showPrivilegeMatrix(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, objectType, objectName string) {
+	if objectName == "" {
+		showError(window, "Выберите объект")
+		return
+	}
+
+	entries, err := operation.GetObjectPrivileges(ctx, pool, objectType, objectName)
+	if err != nil {
+		showError(window, "Ошибка: "+err.Error())
+		return
+	}
+
+	matrix := operation.BuildPrivilegeMatrix(objectType, entries)
+	if len(matrix) == 1 {
+		showInfo(window, "Явных привилегий нет")
+		return
+	}
+
+	table, err := CreateTable(matrix)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+	setOptimalColumnWidths(table, matrix)
+
+	matrixWindow := fyne.CurrentApp().NewWindow(fmt.Sprintf("Привилегии: %s %s", objectType, objectName))
+	matrixWindow.SetContent(container.NewBorder(
+		widget.NewLabel("✓ — привилегия выдана, ✓* — с правом передачи (GRANT OPTION)"),
+		nil, nil, nil,
+		container.NewScroll(table),
+	))
+	matrixWindow.Resize(fyne.NewSize(850, 450))
+	matrixWindow.CenterOnScreen()
+	matrixWindow.Show()
+}
+
+// UIPrivilegeMatrix показывает матрицу привилегий выбранного объекта
+func UIPrivilegeMatrix(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	objectSelect := widget.NewSelect(nil, nil)
+	objectSelect.PlaceHolder = "Объект"
+
+	objectTypeSelect := widget.NewSelect(operation.PrivilegeObjectTypes, func(objectType string) {
+		objects, err := operation.ListPrivilegeObjects(ctx, pool, objectType)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		objectSelect.Options = objects
+		objectSelect.ClearSelected()
+	})
+	objectTypeSelect.SetSelected(operation.ObjectTable)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Тип объекта", objectTypeSelect),
+		widget.NewFormItem("Объект", objectSelect),
+	)
+
+	dialog.ShowCustomConfirm("Матрица привилегий", "Показать", "Отмена", form, func(ok bool) {
+		if ok {
+			showPrivilegeMatrix(ctx, pool, window, objectTypeSelect.Selected, objectSelect.Selected)
+		}
+	}, window)
+}
@@ -0,0 +1,313 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для функций и процедур ==========
+
+// UIRoutineEditor открывает редактор новой функции или процедуры
+func UIRoutineEditor(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	kindSelect := widget.NewSelect([]string{"FUNCTION", "PROCEDURE"}, nil)
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя (например, products_in_category)")
+
+	argsEntry := widget.NewMultiLineEntry()
+	argsEntry.SetPlaceHolder("Аргументы, по одному на строку:\n[IN|OUT|INOUT|VARIADIC] имя тип [DEFAULT значение]\nПример: IN p_category_id integer")
+	argsEntry.SetMinRowsVisible(4)
+
+	returnsEntry := widget.NewEntry()
+	returnsEntry.SetPlaceHolder("Возвращаемый тип: integer, SETOF products, TABLE(id int, name text)")
+
+	languageSelect := widget.NewSelect([]string{"plpgsql", "sql"}, nil)
+	languageSelect.SetSelected("plpgsql")
+
+	volatilitySelect := widget.NewSelect([]string{"VOLATILE", "STABLE", "IMMUTABLE"}, nil)
+	volatilitySelect.SetSelected("VOLATILE")
+
+	bodyEntry := widget.NewMultiLineEntry()
+	bodyEntry.SetText("BEGIN\n\tRETURN QUERY SELECT * FROM products WHERE category_id = p_category_id;\nEND;")
+	bodyEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	bodyEntry.SetMinRowsVisible(10)
+
+	previewEntry := widget.NewMultiLineEntry()
+	previewEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	previewEntry.SetMinRowsVisible(6)
+	previewEntry.Disable()
+
+	kindSelect.OnChanged = func(kind string) {
+		if kind == "PROCEDURE" {
+			returnsEntry.Disable()
+			volatilitySelect.Disable()
+		} else {
+			returnsEntry.Enable()
+			volatilitySelect.Enable()
+		}
+	}
+	kindSelect.SetSelected("FUNCTION")
+
+	buildDefinition := func() (operation.RoutineDefinition, error) {
+		args, err := operation.ParseRoutineArguments(argsEntry.Text)
+		if err != nil {
+			return operation.RoutineDefinition{}, err
+		}
+		return operation.RoutineDefinition{
+			Kind:       kindSelect.Selected,
+			Name:       strings.TrimSpace(nameEntry.Text),
+			Arguments:  args,
+			Returns:    strings.TrimSpace(returnsEntry.Text),
+			Language:   languageSelect.Selected,
+			Volatility: volatilitySelect.Selected,
+			Body:       bodyEntry.Text,
+		}, nil
+	}
+
+	previewBtn := widget.NewButton("Предпросмотр SQL", func() {
+		def, err := buildDefinition()
+		if err == nil {
+			var sql string
+			sql, err = operation.BuildRoutineSQL(def, true)
+			if err == nil {
+				previewEntry.SetText(sql)
+				return
+			}
+		}
+		previewEntry.SetText("-- " + err.Error())
+	})
+
+	saveBtn := widget.NewButton("Сохранить (CREATE OR REPLACE)", func() {
+		def, err := buildDefinition()
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		if err := operation.CreateRoutine(ctx, pool, def, true); err != nil {
+			showError(window, "Ошибка: "+err.Error())
+			return
+		}
+		showInfo(window, fmt.Sprintf("%s '%s' сохранена!", def.Kind, def.Name))
+	})
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Вид", kindSelect),
+			widget.NewFormItem("Имя", nameEntry),
+			widget.NewFormItem("Возвращает", returnsEntry),
+			widget.NewFormItem("Язык", languageSelect),
+			widget.NewFormItem("Изменчивость", volatilitySelect),
+		),
+		widget.NewLabel("Аргументы:"),
+		argsEntry,
+		widget.NewLabel("Тело:"),
+		bodyEntry,
+		container.NewHBox(previewBtn, saveBtn),
+		previewEntry,
+	)
+
+	editorWindow := fyne.CurrentApp().NewWindow("Редактор функций и процедур")
+	editorWindow.SetContent(container.NewScroll(form))
+	editorWindow.Resize(fyne.NewSize(800, 750))
+	editorWindow.CenterOnScreen()
+	editorWindow.Show()
+}
+
+// UIListRoutines показывает функции и процедуры с возможностью просмотра, изменения, вызова и удаления
+func UIListRoutines(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	var routines []operation.RoutineInfo
+	selected := -1
+
+	routineList := widget.NewList(
+		func() int {
+			return len(routines)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("routine")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			r := routines[id]
+			text := fmt.Sprintf("%s %s", r.Kind, r.Signature())
+			if r.Result != "" {
+				text += " → " + r.Result
+			}
+			obj.(*widget.Label).SetText(text)
+		},
+	)
+	routineList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+	}
+
+	reload := func() {
+		list, err := operation.ListRoutines(ctx, pool)
+		if err != nil {
+			showError(window, "Ошибка: "+err.Error())
+			return
+		}
+		routines = list
+		selected = -1
+		routineList.UnselectAll()
+		routineList.Refresh()
+	}
+	reload()
+
+	withSelected := func(action func(r operation.RoutineInfo)) func() {
+		return func() {
+			if selected < 0 || selected >= len(routines) {
+				showError(window, "Выберите функцию или процедуру")
+				return
+			}
+			action(routines[selected])
+		}
+	}
+
+	definitionBtn := widget.NewButton("Определение / изменить", withSelected(func(r operation.RoutineInfo) {
+		showRoutineDefinition(ctx, pool, window, r, reload)
+	}))
+
+	callBtn := widget.NewButton("Вызвать", withSelected(func(r operation.RoutineInfo) {
+		UICallRoutine(ctx, pool, window, r)
+	}))
+
+	dropBtn := widget.NewButton("Удалить", withSelected(func(r operation.RoutineInfo) {
+		dialog.ShowConfirm("Удалить", fmt.Sprintf("Удалить %s %s?", r.Kind, r.Signature()), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := operation.DropRoutine(ctx, pool, r); err != nil {
+				showError(window, "Ошибка удаления: "+err.Error())
+				return
+			}
+			reload()
+		}, window)
+	}))
+
+	refreshBtn := widget.NewButton("🔄 Обновить", reload)
+
+	content := container.NewBorder(
+		container.NewHBox(refreshBtn, definitionBtn, callBtn, dropBtn),
+		nil, nil, nil,
+		routineList,
+	)
+
+	listWindow := fyne.CurrentApp().NewWindow("Функции и процедуры")
+	listWindow.SetContent(content)
+	listWindow.Resize(fyne.NewSize(850, 500))
+	listWindow.CenterOnScreen()
+	listWindow.Show()
+}
+
+// showRoutineDefinition показывает определение (pg_get_functiondef) с возможностью сохранить изменения
+func showRoutineDefinition(ctx context.Context, pool *pgxpool.Pool, window fyne.Window,
+	routine operation.RoutineInfo, onSaved func()) {
+
+	definition, err := operation.GetRoutineDefinition(ctx, pool, routine)
+	if err != nil {
+		showError(window, "Ошибка: "+err.Error())
+		return
+	}
+
+	definitionEntry := widget.NewMultiLineEntry()
+	definitionEntry.SetText(definition)
+	definitionEntry.TextStyle = fyne.TextStyle{Monospace: true}
+
+	definitionWindow := fyne.CurrentApp().NewWindow("Определение: " + routine.Signature())
+
+	saveBtn := widget.NewButton("Сохранить изменения", func() {
+		if err := operation.ExecuteRoutineDDL(ctx, pool, definitionEntry.Text); err != nil {
+			showError(definitionWindow, err.Error())
+			return
+		}
+		showInfo(definitionWindow, "Определение сохранено!")
+		if onSaved != nil {
+			onSaved()
+		}
+	})
+
+	definitionWindow.SetContent(container.NewBorder(nil, saveBtn, nil, nil, definitionEntry))
+	definitionWindow.Resize(fyne.NewSize(800, 550))
+	definitionWindow.CenterOnScreen()
+	definitionWindow.Show()
+}
+
+// UICallRoutine запрашивает значения аргументов и показывает результат вызова
+func UICallRoutine(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, routine operation.RoutineInfo) {
+	args, err := operation.GetRoutineArguments(ctx, pool, routine)
+	if err != nil {
+		showError(window, "Ошибка: "+err.Error())
+		return
+	}
+
+	var entries []*widget.Entry
+	var formItems []*widget.FormItem
+	for i, arg := range args {
+		if !arg.IsInputArgument() {
+			continue
+		}
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("$%d", i+1)
+		}
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(fmt.Sprintf("%s (введите NULL для пустого значения)", arg.Type))
+		if arg.Mode == "VARIADIC" {
+			entry.SetPlaceHolder(fmt.Sprintf("%s — массив, например {1,2,3}", arg.Type))
+		}
+		entries = append(entries, entry)
+		formItems = append(formItems, widget.NewFormItem(fmt.Sprintf("%s %s", arg.Mode, name), entry))
+	}
+
+	execute := func() {
+		values := make([]string, len(entries))
+		for i, entry := range entries {
+			values[i] = entry.Text
+		}
+
+		results, err := operation.CallRoutine(ctx, pool, routine, values)
+		if err != nil {
+			showError(window, "Ошибка вызова: "+err.Error())
+			return
+		}
+		if len(results) == 0 || len(results[0]) == 0 {
+			showInfo(window, fmt.Sprintf("%s выполнена", routine.Signature()))
+			return
+		}
+
+		resultTable, err := CreateTable(results)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+		setOptimalColumnWidths(resultTable, results)
+
+		resultWindow := fyne.CurrentApp().NewWindow("Результат: " + routine.Name)
+		resultWindow.SetContent(container.NewBorder(
+			widget.NewLabel(fmt.Sprintf("Строк: %d", len(results)-1)),
+			nil, nil, nil,
+			container.NewScroll(resultTable),
+		))
+		resultWindow.Resize(fyne.NewSize(900, 500))
+		resultWindow.CenterOnScreen()
+		resultWindow.Show()
+	}
+
+	if len(formItems) == 0 {
+		execute()
+		return
+	}
+
+	dialog.ShowCustomConfirm("Вызов "+routine.Signature(), "Выполнить", "Отмена",
+		widget.NewForm(formItems...), func(ok bool) {
+			if ok {
+				execute()
+			}
+		}, window)
+}
@@ -0,0 +1,128 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для экспорта схемы ==========
+
+// UIExportSchema экспортирует структуру схемы (таблицы, столбцы, FK, ENUM) в DOT, Mermaid, PlantUML или SVG
+func UIExportSchema(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tables, err := getTablesListFromDB(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения таблиц: "+err.Error())
+		return
+	}
+
+	exportWindow := fyne.CurrentApp().NewWindow("Экспорт схемы")
+
+	tablesCheck := widget.NewCheckGroup(tables, nil)
+	tablesCheck.SetSelected(tables)
+
+	selectAllBtn := widget.NewButton("Все", func() {
+		tablesCheck.SetSelected(tables)
+	})
+	selectNoneBtn := widget.NewButton("Ни одной", func() {
+		tablesCheck.SetSelected(nil)
+	})
+
+	formatSelect := widget.NewSelect(operation.SchemaExportFormats, nil)
+	formatSelect.SetSelected(operation.ExportMermaid)
+
+	outputEntry := widget.NewMultiLineEntry()
+	outputEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	outputEntry.SetPlaceHolder("Нажмите «Сгенерировать»")
+
+	generate := func() bool {
+		if len(tablesCheck.Selected) == 0 {
+			showError(exportWindow, "Выберите хотя бы одну таблицу")
+			return false
+		}
+
+		// Все таблицы — экспортируем схему целиком, включая неиспользуемые ENUM типы
+		var subset []string
+		if len(tablesCheck.Selected) < len(tables) {
+			subset = tablesCheck.Selected
+		}
+
+		model, err := operation.GetSchemaModel(ctx, pool, subset)
+		if err != nil {
+			showError(exportWindow, err.Error())
+			return false
+		}
+		text, err := operation.ExportSchema(model, formatSelect.Selected)
+		if err != nil {
+			showError(exportWindow, err.Error())
+			return false
+		}
+		outputEntry.SetText(text)
+		return true
+	}
+
+	generateBtn := widget.NewButton("Сгенерировать", func() {
+		generate()
+	})
+
+	copyBtn := widget.NewButton("📋 Копировать", func() {
+		if outputEntry.Text == "" && !generate() {
+			return
+		}
+		exportWindow.Clipboard().SetContent(outputEntry.Text)
+		showInfo(exportWindow, "Скопировано в буфер обмена")
+	})
+
+	saveBtn := widget.NewButton("💾 Сохранить в файл", func() {
+		if !generate() {
+			return
+		}
+		content := outputEntry.Text
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				showError(exportWindow, err.Error())
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write([]byte(content)); err != nil {
+				showError(exportWindow, "Ошибка записи файла: "+err.Error())
+				return
+			}
+			showInfo(exportWindow, fmt.Sprintf("Схема сохранена: %s", writer.URI().Path()))
+		}, exportWindow)
+		saveDialog.SetFileName("schema" + operation.SchemaExportExtension(formatSelect.Selected))
+		saveDialog.Show()
+	})
+
+	left := container.NewBorder(
+		container.NewVBox(widget.NewLabel("Таблицы:"), container.NewHBox(selectAllBtn, selectNoneBtn)),
+		nil, nil, nil,
+		container.NewVScroll(tablesCheck),
+	)
+	right := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(widget.NewFormItem("Формат", formatSelect)),
+			container.NewHBox(generateBtn, copyBtn, saveBtn),
+		),
+		nil, nil, nil,
+		outputEntry,
+	)
+
+	split := container.NewHSplit(left, right)
+	split.Offset = 0.25
+
+	exportWindow.SetContent(split)
+	exportWindow.Resize(fyne.NewSize(1000, 650))
+	exportWindow.CenterOnScreen()
+	exportWindow.Show()
+	generate()
+}
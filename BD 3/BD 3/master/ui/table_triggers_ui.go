@@ -0,0 +1,313 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для триггеров ==========
+
+// UICreateTriggerFunction создаёт диалог для создания триггерной функции
+func UICreateTriggerFunction(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя функции (например, set_updated_at)")
+
+	bodyEntry := widget.NewMultiLineEntry()
+	bodyEntry.SetText("\tNEW.updated_at := NOW();\n\tRETURN NEW;")
+	bodyEntry.SetMinRowsVisible(10)
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Имя функции", nameEntry),
+		),
+		widget.NewLabel("Тело функции (между BEGIN и END):"),
+		bodyEntry,
+	)
+
+	dlg := dialog.NewCustomConfirm("Создать триггерную функцию", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		name := strings.TrimSpace(nameEntry.Text)
+		if err := operation.CreateTriggerFunction(ctx, pool, name, bodyEntry.Text); err != nil {
+			showError(window, "Ошибка создания функции: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Функция '%s' создана!", name))
+	}, window)
+
+	dlg.Resize(fyne.NewSize(650, 450))
+	dlg.Show()
+}
+
+// UICreateTrigger создаёт диалог для создания триггера
+func UICreateTrigger(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя триггера")
+
+	tables, _ := getAllTables(ctx, pool)
+	tableSelect := widget.NewSelect(tables, nil)
+	tableSelect.PlaceHolder = "Таблица или представление"
+
+	timingSelect := widget.NewSelect([]string{"BEFORE", "AFTER", "INSTEAD OF"}, nil)
+	timingSelect.SetSelected("BEFORE")
+
+	insertCheck := widget.NewCheck("INSERT", nil)
+	updateCheck := widget.NewCheck("UPDATE", nil)
+	deleteCheck := widget.NewCheck("DELETE", nil)
+	truncateCheck := widget.NewCheck("TRUNCATE", nil)
+
+	updateColumnsEntry := widget.NewEntry()
+	updateColumnsEntry.SetPlaceHolder("UPDATE OF: столбцы через запятую (необязательно)")
+
+	forEachSelect := widget.NewSelect([]string{"ROW", "STATEMENT"}, nil)
+	forEachSelect.SetSelected("ROW")
+
+	whenEntry := widget.NewEntry()
+	whenEntry.SetPlaceHolder("WHEN, например: OLD.price IS DISTINCT FROM NEW.price")
+
+	functions, _ := operation.ListTriggerFunctions(ctx, pool)
+	functionSelect := widget.NewSelect(functions, nil)
+	functionSelect.PlaceHolder = "Триггерная функция"
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Имя", nameEntry),
+			widget.NewFormItem("Таблица", tableSelect),
+			widget.NewFormItem("Момент", timingSelect),
+			widget.NewFormItem("Уровень", forEachSelect),
+			widget.NewFormItem("Функция", functionSelect),
+		),
+		widget.NewLabel("События:"),
+		container.NewHBox(insertCheck, updateCheck, deleteCheck, truncateCheck),
+		updateColumnsEntry,
+		widget.NewForm(
+			widget.NewFormItem("WHEN", whenEntry),
+		),
+	)
+
+	dlg := dialog.NewCustomConfirm("Создать триггер", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var events []string
+		if insertCheck.Checked {
+			events = append(events, "INSERT")
+		}
+		if updateCheck.Checked {
+			if cols := strings.TrimSpace(updateColumnsEntry.Text); cols != "" {
+				events = append(events, "UPDATE OF "+cols)
+			} else {
+				events = append(events, "UPDATE")
+			}
+		}
+		if deleteCheck.Checked {
+			events = append(events, "DELETE")
+		}
+		if truncateCheck.Checked {
+			events = append(events, "TRUNCATE")
+		}
+
+		def := operation.TriggerDefinition{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			Table:    tableSelect.Selected,
+			Timing:   timingSelect.Selected,
+			Events:   events,
+			ForEach:  forEachSelect.Selected,
+			When:     strings.TrimSpace(whenEntry.Text),
+			Function: functionSelect.Selected,
+		}
+
+		if err := operation.CreateTrigger(ctx, pool, def); err != nil {
+			showError(window, "Ошибка создания триггера: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Триггер '%s' создан!", def.Name))
+	}, window)
+
+	dlg.Resize(fyne.NewSize(650, 500))
+	dlg.Show()
+}
+
+// UIListTriggers показывает триггеры таблицы с возможностью включения/отключения и удаления
+func UIListTriggers(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	var triggers []operation.TriggerInfo
+	selected := -1
+
+	definitionLabel := widget.NewLabel("Выберите триггер")
+	definitionLabel.Wrapping = fyne.TextWrapWord
+
+	triggerList := widget.NewList(
+		func() int {
+			return len(triggers)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("trigger")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			state := "✅"
+			if !triggers[id].Enabled {
+				state = "⛔"
+			}
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s %s → %s()", state, triggers[id].Name, triggers[id].Function))
+		},
+	)
+	triggerList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		definitionLabel.SetText(triggers[id].Definition)
+	}
+
+	tables, _ := getAllTables(ctx, pool)
+	var tableSelect *widget.Select
+	reload := func() {
+		list, err := operation.ListTriggers(ctx, pool, tableSelect.Selected)
+		if err != nil {
+			showError(window, "Ошибка: "+err.Error())
+			return
+		}
+		triggers = list
+		selected = -1
+		triggerList.UnselectAll()
+		triggerList.Refresh()
+		definitionLabel.SetText(fmt.Sprintf("Триггеров: %d", len(triggers)))
+	}
+	tableSelect = widget.NewSelect(tables, func(string) { reload() })
+	tableSelect.PlaceHolder = "Таблица"
+
+	toggleBtn := widget.NewButton("Включить / Отключить", func() {
+		if selected < 0 {
+			showError(window, "Выберите триггер")
+			return
+		}
+		t := triggers[selected]
+		if err := operation.SetTriggerEnabled(ctx, pool, t.Table, t.Name, !t.Enabled); err != nil {
+			showError(window, "Ошибка: "+err.Error())
+			return
+		}
+		reload()
+	})
+
+	dropBtn := widget.NewButton("Удалить", func() {
+		if selected < 0 {
+			showError(window, "Выберите триггер")
+			return
+		}
+		t := triggers[selected]
+		dialog.ShowConfirm("Удалить триггер", fmt.Sprintf("Удалить триггер '%s'?", t.Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := operation.DropTrigger(ctx, pool, t.Table, t.Name); err != nil {
+				showError(window, "Ошибка удаления: "+err.Error())
+				return
+			}
+			reload()
+		}, window)
+	})
+
+	sourceBtn := widget.NewButton("Исходный код функции", func() {
+		if selected < 0 {
+			showError(window, "Выберите триггер")
+			return
+		}
+		showTriggerFunctionSource(ctx, pool, window, triggers[selected].Function)
+	})
+
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(widget.NewFormItem("Таблица", tableSelect)),
+			container.NewHBox(toggleBtn, dropBtn, sourceBtn),
+		),
+		widget.NewCard("Определение", "", definitionLabel),
+		nil, nil,
+		triggerList,
+	)
+
+	triggersWindow := fyne.CurrentApp().NewWindow("Триггеры")
+	triggersWindow.SetContent(content)
+	triggersWindow.Resize(fyne.NewSize(800, 550))
+	triggersWindow.CenterOnScreen()
+	triggersWindow.Show()
+}
+
+// showTriggerFunctionSource показывает исходный код триггерной функции
+func showTriggerFunctionSource(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, functionName string) {
+	source, err := operation.GetTriggerFunctionSource(ctx, pool, functionName)
+	if err != nil {
+		showError(window, "Ошибка: "+err.Error())
+		return
+	}
+
+	sourceEntry := widget.NewMultiLineEntry()
+	sourceEntry.SetText(source)
+	sourceEntry.TextStyle = fyne.TextStyle{Monospace: true}
+
+	sourceWindow := fyne.CurrentApp().NewWindow("Функция: " + functionName)
+	sourceWindow.SetContent(sourceEntry)
+	sourceWindow.Resize(fyne.NewSize(700, 450))
+	sourceWindow.CenterOnScreen()
+	sourceWindow.Show()
+}
+
+// UITriggerFunctionSource показывает исходный код выбранной триггерной функции
+func UITriggerFunctionSource(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	functions, err := operation.ListTriggerFunctions(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка: "+err.Error())
+		return
+	}
+	functionSelect := widget.NewSelect(functions, nil)
+	functionSelect.PlaceHolder = "Триггерная функция"
+
+	form := widget.NewForm(
+		widget.NewFormItem("Функция", functionSelect),
+	)
+
+	dialog.ShowCustomConfirm("Исходный код функции", "Показать", "Отмена", form, func(ok bool) {
+		if ok {
+			showTriggerFunctionSource(ctx, pool, window, functionSelect.Selected)
+		}
+	}, window)
+}
+
+// UIUpdatedAtTrigger создаёт триггер, автоматически обновляющий столбец updated_at
+func UIUpdatedAtTrigger(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	tables, _ := getTablesListFromDB(ctx, pool)
+	tableSelect := widget.NewSelect(tables, nil)
+	tableSelect.SetSelected("products")
+
+	columnEntry := widget.NewEntry()
+	columnEntry.SetText("updated_at")
+
+	form := widget.NewForm(
+		widget.NewFormItem("Таблица", tableSelect),
+		widget.NewFormItem("Столбец", columnEntry),
+	)
+
+	dialog.ShowCustomConfirm("Триггер updated_at", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		err := operation.CreateUpdatedAtTrigger(ctx, pool, tableSelect.Selected, strings.TrimSpace(columnEntry.Text))
+		if err != nil {
+			showError(window, "Ошибка: "+err.Error())
+			return
+		}
+
+		showInfo(window, fmt.Sprintf("Столбец '%s' таблицы '%s' теперь обновляется триггером!",
+			strings.TrimSpace(columnEntry.Text), tableSelect.Selected))
+	}, window)
+}
@@ -0,0 +1,141 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для TRUNCATE ==========
+
+// UITruncateTables открывает окно очистки таблиц (TRUNCATE) с предпросмотром затрагиваемых таблиц
+// preselected — таблицы, отмеченные изначально; onDone вызывается после успешной очистки
+func UITruncateTables(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, preselected []string, onDone func()) {
+	tables, err := getTablesListFromDB(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения таблиц: "+err.Error())
+		return
+	}
+
+	truncateWindow := fyne.CurrentApp().NewWindow("Очистка таблиц (TRUNCATE)")
+
+	tablesCheck := widget.NewCheckGroup(tables, nil)
+	tablesCheck.SetSelected(preselected)
+
+	identityRadio := widget.NewRadioGroup([]string{"CONTINUE IDENTITY", "RESTART IDENTITY"}, nil)
+	identityRadio.SetSelected("CONTINUE IDENTITY")
+	identityRadio.Horizontal = true
+
+	cascadeCheck := widget.NewCheck("CASCADE (очистить и ссылающиеся таблицы)", nil)
+
+	var impact []operation.TruncateImpact
+	impactData := [][]string{{"Таблица", "Строк", "Причина"}}
+	impactTable := widget.NewTable(
+		func() (int, int) {
+			return len(impactData), len(impactData[0])
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(impactData[id.Row][id.Col])
+		},
+	)
+	summaryLabel := widget.NewLabel("Выберите таблицы и нажмите «Показать влияние»")
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	showImpact := func() bool {
+		var err error
+		impact, err = operation.GetTruncateImpact(ctx, pool, tablesCheck.Selected)
+		if err != nil {
+			showError(truncateWindow, err.Error())
+			return false
+		}
+
+		data := [][]string{{"Таблица", "Строк", "Причина"}}
+		var total int64
+		var blocking []string
+		for _, item := range impact {
+			reason := "выбрана"
+			if !item.Selected {
+				reason = fmt.Sprintf("ссылается на %s (FK)", item.Referenced)
+				blocking = append(blocking, item.Table)
+			}
+			if item.Selected || cascadeCheck.Checked {
+				total += item.RowCount
+			}
+			data = append(data, []string{item.Table, fmt.Sprintf("%d", item.RowCount), reason})
+		}
+
+		impactData = data
+		setOptimalColumnWidths(impactTable, impactData)
+		impactTable.Refresh()
+
+		switch {
+		case len(blocking) == 0:
+			summaryLabel.SetText(fmt.Sprintf("Будет удалено строк: %d", total))
+		case cascadeCheck.Checked:
+			summaryLabel.SetText(fmt.Sprintf("Будет удалено строк: %d, включая %d ссылающихся таблиц (CASCADE)", total, len(blocking)))
+		default:
+			summaryLabel.SetText(fmt.Sprintf("⚠ На выбранные таблицы ссылаются %d таблиц — без CASCADE TRUNCATE завершится ошибкой", len(blocking)))
+		}
+		return true
+	}
+
+	impactBtn := widget.NewButton("Показать влияние", func() {
+		showImpact()
+	})
+
+	truncateBtn := widget.NewButton("🧹 TRUNCATE", func() {
+		if !showImpact() {
+			return
+		}
+
+		selected := append([]string(nil), tablesCheck.Selected...)
+		restart := identityRadio.Selected == "RESTART IDENTITY"
+		cascade := cascadeCheck.Checked
+
+		dialog.ShowConfirm("Подтверждение TRUNCATE", summaryLabel.Text+"\n\nОперация необратима. Продолжить?", func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := operation.TruncateTables(ctx, pool, selected, restart, cascade); err != nil {
+				showError(truncateWindow, err.Error())
+				return
+			}
+			showInfo(truncateWindow, "Таблицы очищены!")
+			showImpact()
+			if onDone != nil {
+				onDone()
+			}
+		}, truncateWindow)
+	})
+
+	options := container.NewVBox(
+		identityRadio,
+		cascadeCheck,
+		container.NewHBox(impactBtn, truncateBtn),
+		summaryLabel,
+	)
+
+	content := container.NewHSplit(
+		container.NewBorder(widget.NewLabel("Таблицы:"), nil, nil, nil, container.NewVScroll(tablesCheck)),
+		container.NewBorder(options, nil, nil, nil, container.NewScroll(impactTable)),
+	)
+	content.Offset = 0.3
+
+	truncateWindow.SetContent(content)
+	truncateWindow.Resize(fyne.NewSize(900, 550))
+	truncateWindow.CenterOnScreen()
+	truncateWindow.Show()
+
+	if len(preselected) > 0 {
+		showImpact()
+	}
+}
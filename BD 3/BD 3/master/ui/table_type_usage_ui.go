@@ -0,0 +1,138 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для отчёта об использовании типа ==========
+
+// typeUsageKindLabels подписи видов использования типа
+var typeUsageKindLabels = map[string]string{
+	operation.TypeUsageTableColumn:   "Столбец таблицы",
+	operation.TypeUsageViewColumn:    "Столбец представления",
+	operation.TypeUsageMatViewColumn: "Столбец мат. представления",
+	operation.TypeUsageForeignColumn: "Столбец внешней таблицы",
+	operation.TypeUsageCompositeType: "Поле составного типа",
+	operation.TypeUsageDomain:        "Домен",
+	operation.TypeUsageFunction:      "Функция",
+	operation.TypeUsageRange:         "Диапазонный тип",
+}
+
+// typeUsageDetailLabels подписи роли типа в функциях и диапазонах
+var typeUsageDetailLabels = strings.NewReplacer(
+	"argument", "аргумент",
+	"result", "результат",
+	"subtype", "подтип",
+)
+
+// UITypeUsage предлагает выбрать пользовательский тип и показывает, где он используется
+func UITypeUsage(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	types, err := operation.GetCustomTypes(ctx, pool)
+	if err != nil {
+		showError(window, "Ошибка получения типов: "+err.Error())
+		return
+	}
+	var names []string
+	for _, t := range types {
+		names = append(names, t["type_name"].(string))
+	}
+	if len(names) == 0 {
+		showInfo(window, "В схеме public нет пользовательских типов")
+		return
+	}
+
+	typeSelect := widget.NewSelect(names, nil)
+	typeSelect.SetSelected(names[0])
+
+	dialog.ShowCustomConfirm("Использование типа", "Показать", "Отмена",
+		widget.NewForm(widget.NewFormItem("Тип", typeSelect)), func(ok bool) {
+			if ok && typeSelect.Selected != "" {
+				showTypeUsageReport(ctx, pool, window, typeSelect.Selected)
+			}
+		}, window)
+}
+
+// showTypeUsageReport открывает отчёт об использовании типа: столбцы, поля составных типов,
+// домены, функции и столбцы представлений; для ENUM — число строк по каждому значению
+func showTypeUsageReport(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, typeName string) {
+	usages, err := operation.GetTypeUsage(ctx, pool, typeName)
+	if err != nil {
+		showError(window, err.Error())
+		return
+	}
+
+	usageData := [][]string{{"Вид", "Объект", "Тип / роль"}}
+	for _, usage := range usages {
+		kind := typeUsageKindLabels[usage.Kind]
+		if kind == "" {
+			kind = usage.Kind
+		}
+		usageData = append(usageData, []string{kind, usage.Object, typeUsageDetailLabels.Replace(usage.Detail)})
+	}
+
+	summary := fmt.Sprintf("Тип %s используется в %d местах", typeName, len(usages))
+	if len(usages) == 0 {
+		summary = fmt.Sprintf("Тип %s нигде не используется — его можно удалить без CASCADE", typeName)
+	}
+	summaryLabel := widget.NewLabel(summary)
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	usageTable := newReportTable(usageData)
+	tabs := container.NewAppTabs(container.NewTabItem("Использование", container.NewScroll(usageTable)))
+
+	if getCustomTypeKind(ctx, pool, typeName) == "ENUM" {
+		columns, values, err := operation.GetEnumValueUsage(ctx, pool, typeName)
+		if err != nil {
+			showError(window, err.Error())
+			return
+		}
+
+		header := []string{"Значение", "Всего строк"}
+		for _, col := range columns {
+			header = append(header, col.Table+"."+col.Column)
+		}
+		valuesData := [][]string{header}
+		for _, value := range values {
+			row := []string{value.Value, fmt.Sprintf("%d", value.Total)}
+			for _, col := range columns {
+				row = append(row, fmt.Sprintf("%d", value.ByColumn[col.Table+"."+col.Column]))
+			}
+			valuesData = append(valuesData, row)
+		}
+		tabs.Append(container.NewTabItem("Значения ENUM", container.NewScroll(newReportTable(valuesData))))
+	}
+
+	reportWindow := fyne.CurrentApp().NewWindow("Использование типа: " + typeName)
+	reportWindow.SetContent(container.NewBorder(summaryLabel, nil, nil, nil, tabs))
+	reportWindow.Resize(fyne.NewSize(800, 500))
+	reportWindow.CenterOnScreen()
+	reportWindow.Show()
+}
+
+// newReportTable создаёт таблицу только для чтения с жирной строкой заголовка
+func newReportTable(data [][]string) *widget.Table {
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(data), len(data[0])
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.SetText(data[id.Row][id.Col])
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+		},
+	)
+	setOptimalColumnWidths(table, data)
+	return table
+}
@@ -0,0 +1,256 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== UI для диапазонных и составных типов ==========
+
+// UICreateRangeType открывает диалог создания диапазонного типа с выбором подтипа
+// и класса операторов B-дерева
+func UICreateRangeType(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	typeOptions, err := operation.GetColumnTypeOptions(ctx, pool)
+	if err != nil {
+		log.Printf("Ошибка получения пользовательских типов: %v", err)
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя типа, например: price_range")
+	subtypePicker := newTypePicker(typeOptions)
+	subtypePicker.SetPlaceHolder("Подтип, например: NUMERIC")
+	opclassEntry := widget.NewSelectEntry(nil)
+	opclassEntry.SetPlaceHolder("По умолчанию")
+	collationEntry := widget.NewEntry()
+	collationEntry.SetPlaceHolder("Необязательно, для строковых подтипов")
+	diffEntry := widget.NewEntry()
+	diffEntry.SetPlaceHolder("Необязательно, например: float8mi")
+
+	previewLabel := widget.NewLabel("")
+	previewLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	definition := func() operation.RangeTypeDefinition {
+		return operation.RangeTypeDefinition{
+			Name:           strings.TrimSpace(nameEntry.Text),
+			Subtype:        strings.TrimSpace(subtypePicker.Text),
+			SubtypeOpClass: strings.TrimSpace(opclassEntry.Text),
+			Collation:      strings.TrimSpace(collationEntry.Text),
+			SubtypeDiff:    strings.TrimSpace(diffEntry.Text),
+		}
+	}
+
+	updatePreview := func() {
+		rangeType := definition()
+		if rangeType.Name == "" {
+			rangeType.Name = "<имя_типа>"
+		}
+		if rangeType.Subtype == "" {
+			rangeType.Subtype = "<подтип>"
+		}
+		previewLabel.SetText(operation.BuildCreateRangeTypeSQL(rangeType) + ";")
+	}
+
+	nameEntry.OnChanged = func(string) { updatePreview() }
+	subtypePicker.OnChanged = func(subtype string) {
+		// Классы операторов зависят от подтипа; ошибка означает, что тип ещё не введён полностью
+		classes, err := operation.GetSubtypeOperatorClasses(ctx, pool, subtype)
+		if err == nil {
+			opclassEntry.SetOptions(classes)
+		}
+		updatePreview()
+	}
+	opclassEntry.OnChanged = func(string) { updatePreview() }
+	collationEntry.OnChanged = func(string) { updatePreview() }
+	diffEntry.OnChanged = func(string) { updatePreview() }
+	updatePreview()
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Имя типа", nameEntry),
+			widget.NewFormItem("Подтип", subtypePicker),
+			widget.NewFormItem("SUBTYPE_OPCLASS", opclassEntry),
+			widget.NewFormItem("COLLATION", collationEntry),
+			widget.NewFormItem("SUBTYPE_DIFF", diffEntry),
+		),
+		widget.NewLabel("Предпросмотр:"),
+		previewLabel,
+	)
+
+	dlg := dialog.NewCustomConfirm("Создать диапазонный тип", "Создать", "Отмена", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		rangeType := definition()
+		if err := operation.CreateRangeType(ctx, pool, rangeType); err != nil {
+			showError(window, "Ошибка создания типа: "+err.Error())
+			return
+		}
+		showInfo(window, fmt.Sprintf("Диапазонный тип '%s' успешно создан!", rangeType.Name))
+	}, window)
+	dlg.Resize(fyne.NewSize(600, 450))
+	dlg.Show()
+}
+
+// UIEditCompositeType предлагает выбрать составной тип и открывает редактор его полей
+func UIEditCompositeType(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	types, err := getCustomTypeNames(ctx, pool, "COMPOSITE")
+	if err != nil {
+		showError(window, "Ошибка получения типов: "+err.Error())
+		return
+	}
+	if len(types) == 0 {
+		showInfo(window, "В схеме public нет составных типов")
+		return
+	}
+
+	typeSelect := widget.NewSelect(types, nil)
+	typeSelect.SetSelected(types[0])
+
+	dialog.ShowCustomConfirm("Изменить составной тип", "Открыть", "Отмена",
+		widget.NewForm(widget.NewFormItem("Тип", typeSelect)), func(ok bool) {
+			if ok && typeSelect.Selected != "" {
+				showCompositeTypeEditor(ctx, pool, window, typeSelect.Selected)
+			}
+		}, window)
+}
+
+// showCompositeTypeEditor открывает окно добавления, удаления, переименования
+// и изменения типа полей составного типа (ALTER TYPE ... ATTRIBUTE)
+func showCompositeTypeEditor(ctx context.Context, pool *pgxpool.Pool, window fyne.Window, typeName string) {
+	typeOptions, err := operation.GetColumnTypeOptions(ctx, pool)
+	if err != nil {
+		log.Printf("Ошибка получения пользовательских типов: %v", err)
+	}
+
+	editorWindow := fyne.CurrentApp().NewWindow("Составной тип: " + typeName)
+
+	cascadeCheck := widget.NewCheck("CASCADE — изменить также типизированные таблицы на основе типа", nil)
+	fieldsBox := container.NewVBox()
+
+	var reload func()
+
+	renameField := func(field operation.CompositeField) {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(field.Name)
+		dialog.ShowCustomConfirm("Переименовать поле "+field.Name, "Переименовать", "Отмена",
+			widget.NewForm(widget.NewFormItem("Новое имя", nameEntry)), func(ok bool) {
+				if !ok {
+					return
+				}
+				err := operation.RenameCompositeAttribute(ctx, pool, typeName, field.Name,
+					strings.TrimSpace(nameEntry.Text), cascadeCheck.Checked)
+				if err != nil {
+					showError(editorWindow, err.Error())
+					return
+				}
+				reload()
+			}, editorWindow)
+	}
+
+	retypeField := func(field operation.CompositeField) {
+		typePicker := newTypePicker(typeOptions)
+		typePicker.SetText(field.Type)
+		dlg := dialog.NewCustomConfirm("Изменить тип поля "+field.Name, "Изменить", "Отмена",
+			widget.NewForm(widget.NewFormItem("Новый тип", typePicker)), func(ok bool) {
+				if !ok {
+					return
+				}
+				err := operation.AlterCompositeAttributeType(ctx, pool, typeName, field.Name,
+					typePicker.Text, cascadeCheck.Checked)
+				if err != nil {
+					showError(editorWindow, err.Error())
+					return
+				}
+				reload()
+			}, editorWindow)
+		dlg.Resize(fyne.NewSize(450, 200))
+		dlg.Show()
+	}
+
+	dropField := func(field operation.CompositeField) {
+		dialog.ShowConfirm("Удаление поля",
+			fmt.Sprintf("Удалить поле %s типа %s?", field.Name, typeName),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := operation.DropCompositeAttribute(ctx, pool, typeName, field.Name, cascadeCheck.Checked); err != nil {
+					showError(editorWindow, err.Error())
+					return
+				}
+				reload()
+			}, editorWindow)
+	}
+
+	reload = func() {
+		fields, err := operation.GetCompositeTypeFields(ctx, pool, typeName)
+		if err != nil {
+			showError(editorWindow, err.Error())
+			return
+		}
+
+		fieldsBox.Objects = nil
+		if len(fields) == 0 {
+			fieldsBox.Add(widget.NewLabel("У типа нет полей"))
+		}
+		for _, field := range fields {
+			field := field
+			label := widget.NewLabel(fmt.Sprintf("%s  %s", field.Name, field.Type))
+			fieldsBox.Add(container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButton("Переименовать", func() { renameField(field) }),
+					widget.NewButton("Тип", func() { retypeField(field) }),
+					widget.NewButtonWithIcon("", theme.DeleteIcon(), func() { dropField(field) }),
+				),
+				label,
+			))
+		}
+		fieldsBox.Refresh()
+	}
+
+	newNameEntry := widget.NewEntry()
+	newNameEntry.SetPlaceHolder("Имя поля")
+	newTypeEntry := newTypePicker(typeOptions)
+
+	addFieldBtn := widget.NewButtonWithIcon("Добавить поле", theme.ContentAddIcon(), func() {
+		field := operation.CompositeField{
+			Name: strings.TrimSpace(newNameEntry.Text),
+			Type: strings.TrimSpace(newTypeEntry.Text),
+		}
+		if err := operation.AddCompositeAttribute(ctx, pool, typeName, field, cascadeCheck.Checked); err != nil {
+			showError(editorWindow, err.Error())
+			return
+		}
+		newNameEntry.SetText("")
+		newTypeEntry.SetText("")
+		reload()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("Поля (в порядке объявления):"),
+		fieldsBox,
+		widget.NewSeparator(),
+		widget.NewLabel("Новое поле (добавляется в конец):"),
+		container.NewGridWithColumns(2, newNameEntry, newTypeEntry),
+		addFieldBtn,
+		widget.NewSeparator(),
+		cascadeCheck,
+	)
+
+	reload()
+
+	editorWindow.SetContent(container.NewScroll(content))
+	editorWindow.Resize(fyne.NewSize(700, 500))
+	editorWindow.CenterOnScreen()
+	editorWindow.Show()
+}
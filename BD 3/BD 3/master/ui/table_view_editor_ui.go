@@ -0,0 +1,121 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ VIEW editor UI ============
+
+// UIEditView opens an editor for the SELECT of a view or materialized view. Incompatible changes
+// are applied by dropping and recreating all dependent views/MVs in one transaction
+func UIEditView(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	views, err := operation.ListAllViews(ctx, pool)
+	if err != nil {
+		showError(window, fmt.Sprintf("Failed to list views: %v", err))
+		return
+	}
+	mvs, err := operation.ListAllMaterializedViews(ctx, pool)
+	if err != nil {
+		showError(window, fmt.Sprintf("Failed to list materialized views: %v", err))
+		return
+	}
+	names := append(views, mvs...)
+	if len(names) == 0 {
+		showInfo(window, "There are no views in the public schema")
+		return
+	}
+
+	editorWindow := fyne.CurrentApp().NewWindow("Edit VIEW")
+
+	definitionEntry := widget.NewMultiLineEntry()
+	definitionEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	definitionEntry.SetMinRowsVisible(12)
+
+	dependentsLabel := widget.NewLabel("")
+	dependentsLabel.Wrapping = fyne.TextWrapWord
+
+	viewSelect := widget.NewSelect(names, nil)
+
+	load := func(name string) {
+		definition, err := operation.GetViewDefinition(ctx, pool, name)
+		if err != nil {
+			definition, err = operation.GetMaterializedViewDefinition(ctx, pool, name)
+		}
+		if err != nil {
+			showError(editorWindow, err.Error())
+			return
+		}
+		definitionEntry.SetText(strings.TrimSpace(definition))
+
+		dependents, err := operation.GetDependentViews(ctx, pool, name)
+		if err != nil {
+			showError(editorWindow, err.Error())
+			return
+		}
+		if len(dependents) == 0 {
+			dependentsLabel.SetText("No views depend on " + name)
+			return
+		}
+		var lines []string
+		for _, dep := range dependents {
+			lines = append(lines, fmt.Sprintf("%s%s (%s)", strings.Repeat("  ", dep.Level-1), dep.Name, strings.ToLower(dep.Kind)))
+		}
+		dependentsLabel.SetText(fmt.Sprintf("Dependent views (recreated in this order if the change is incompatible):\n%s",
+			strings.Join(lines, "\n")))
+	}
+	viewSelect.OnChanged = load
+
+	saveBtn := widget.NewButton("Save", func() {
+		name := viewSelect.Selected
+		if name == "" {
+			showError(editorWindow, "Select a view")
+			return
+		}
+
+		apply := func() {
+			result, err := operation.ReplaceViewPreservingDependents(ctx, pool, name, definitionEntry.Text)
+			if err != nil {
+				showError(editorWindow, fmt.Sprintf("Failed to update view (nothing was changed): %v", err))
+				return
+			}
+			if !result.Rebuilt {
+				showInfo(editorWindow, fmt.Sprintf("VIEW '%s' updated with CREATE OR REPLACE", name))
+			} else if len(result.Recreated) == 0 {
+				showInfo(editorWindow, fmt.Sprintf("'%s' was recreated", name))
+			} else {
+				showInfo(editorWindow, fmt.Sprintf("'%s' was recreated together with: %s", name, strings.Join(result.Recreated, ", ")))
+			}
+			load(name)
+		}
+
+		dialog.ShowConfirm("Save view",
+			fmt.Sprintf("Apply the new definition of '%s'?\n"+
+				"If the column list changes, dependent views will be dropped and recreated in one transaction\n"+
+				"(materialized views are repopulated).", name),
+			func(ok bool) {
+				if ok {
+					apply()
+				}
+			}, editorWindow)
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	top := widget.NewForm(widget.NewFormItem("View", viewSelect))
+	bottom := container.NewVBox(dependentsLabel, container.NewHBox(saveBtn))
+
+	editorWindow.SetContent(container.NewBorder(top, bottom, nil, nil, definitionEntry))
+	editorWindow.Resize(fyne.NewSize(800, 600))
+	editorWindow.CenterOnScreen()
+	editorWindow.Show()
+
+	viewSelect.SetSelected(names[0])
+}
@@ -0,0 +1,74 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ============ VIEW options form ============
+
+const checkOptionNoneLabel = "NONE"
+
+// viewOptionsForm holds the widgets for the optional CREATE VIEW clauses
+type viewOptionsForm struct {
+	columns         *widget.Entry
+	checkOption     *widget.Select
+	securityBarrier *widget.Check
+	securityInvoker *widget.Check
+	temporary       *widget.Check
+	recursive       *widget.Check
+}
+
+func newViewOptionsForm() *viewOptionsForm {
+	f := &viewOptionsForm{
+		columns: widget.NewEntry(),
+		checkOption: widget.NewSelect([]string{
+			checkOptionNoneLabel, operation.CheckOptionLocal, operation.CheckOptionCascaded,
+		}, nil),
+		securityBarrier: widget.NewCheck("security_barrier", nil),
+		securityInvoker: widget.NewCheck("security_invoker (PostgreSQL 15+)", nil),
+		temporary:       widget.NewCheck("TEMPORARY (kept until the session ends)", nil),
+		recursive:       widget.NewCheck("RECURSIVE (requires column names)", nil),
+	}
+	f.columns.SetPlaceHolder("optional: id, title, price")
+	f.checkOption.SetSelected(checkOptionNoneLabel)
+	return f
+}
+
+// content returns the options section for a dialog
+func (f *viewOptionsForm) content() fyne.CanvasObject {
+	return container.NewVBox(
+		widget.NewLabel("Column Names:"),
+		f.columns,
+		widget.NewForm(widget.NewFormItem("WITH CHECK OPTION", f.checkOption)),
+		container.NewHBox(f.securityBarrier, f.securityInvoker),
+		container.NewHBox(f.temporary, f.recursive),
+	)
+}
+
+// options collects the entered values
+func (f *viewOptionsForm) options(orReplace bool) operation.ViewOptions {
+	var columns []string
+	for _, col := range strings.Split(f.columns.Text, ",") {
+		if col = strings.TrimSpace(col); col != "" {
+			columns = append(columns, col)
+		}
+	}
+	checkOption := f.checkOption.Selected
+	if checkOption == checkOptionNoneLabel {
+		checkOption = operation.CheckOptionNone
+	}
+	return operation.ViewOptions{
+		OrReplace:       orReplace,
+		Temporary:       f.temporary.Checked,
+		Recursive:       f.recursive.Checked,
+		Columns:         columns,
+		CheckOption:     checkOption,
+		SecurityBarrier: f.securityBarrier.Checked,
+		SecurityInvoker: f.securityInvoker.Checked,
+	}
+}
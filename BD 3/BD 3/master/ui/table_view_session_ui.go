@@ -0,0 +1,125 @@
+package table
+
+import (
+	operation "BD_Mirea/internal"
+	"context"
+	"fmt"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============ Temporary VIEW session UI ============
+
+var (
+	viewSessionOnce sync.Once
+	viewSession     *operation.ViewSession
+)
+
+// getViewSession returns the application-wide session that holds temporary views
+func getViewSession(pool *pgxpool.Pool) *operation.ViewSession {
+	viewSessionOnce.Do(func() {
+		viewSession = operation.NewViewSession(pool)
+	})
+	return viewSession
+}
+
+// createViewWithOptions creates temporary views on the session connection and all others through the pool
+func createViewWithOptions(ctx context.Context, pool *pgxpool.Pool, viewName, selectQuery string, opts operation.ViewOptions) error {
+	if opts.Temporary {
+		return getViewSession(pool).CreateView(ctx, viewName, selectQuery, opts)
+	}
+	return operation.CreateViewWithOptions(ctx, pool, viewName, selectQuery, opts)
+}
+
+// UITemporaryViews previews and drops the temporary views of the application session
+func UITemporaryViews(ctx context.Context, pool *pgxpool.Pool, window fyne.Window) {
+	session := getViewSession(pool)
+	sessionWindow := fyne.CurrentApp().NewWindow("Temporary VIEWs")
+
+	viewSelect := widget.NewSelect(nil, nil)
+	viewSelect.PlaceHolder = "Select temporary view"
+	previewBox := container.NewStack()
+
+	reload := func() {
+		views, err := session.TemporaryViews(ctx)
+		if err != nil {
+			showError(sessionWindow, err.Error())
+			return
+		}
+		var names []string
+		for _, v := range views {
+			names = append(names, v.Name)
+		}
+		viewSelect.Options = names
+		viewSelect.ClearSelected()
+		previewBox.Objects = nil
+		if len(names) == 0 {
+			previewBox.Objects = []fyne.CanvasObject{widget.NewLabel(
+				"No temporary views in this session. Create one with the TEMPORARY option.")}
+		}
+		previewBox.Refresh()
+	}
+
+	viewSelect.OnChanged = func(name string) {
+		if name == "" {
+			return
+		}
+		data, err := session.PreviewView(ctx, name, 100)
+		if err != nil {
+			showError(sessionWindow, err.Error())
+			return
+		}
+		previewBox.Objects = []fyne.CanvasObject{container.NewScroll(newReportTable(data))}
+		previewBox.Refresh()
+	}
+
+	dropBtn := widget.NewButton("Drop VIEW", func() {
+		name := viewSelect.Selected
+		if name == "" {
+			showError(sessionWindow, "Select a temporary view to drop")
+			return
+		}
+		dialog.ShowConfirm("Drop VIEW", fmt.Sprintf("Drop temporary view '%s'?", name), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := session.DropView(ctx, name); err != nil {
+				showError(sessionWindow, err.Error())
+				return
+			}
+			reload()
+		}, sessionWindow)
+	})
+	dropBtn.Importance = widget.DangerImportance
+
+	endBtn := widget.NewButton("End Session", func() {
+		dialog.ShowConfirm("End Session", "Drop all temporary views and release the session connection?", func(ok bool) {
+			if !ok {
+				return
+			}
+			session.Close(ctx)
+			reload()
+		}, sessionWindow)
+	})
+
+	infoLabel := widget.NewLabel("Temporary views live on one dedicated connection until the session ends or the application closes.")
+	infoLabel.Wrapping = fyne.TextWrapWord
+
+	top := container.NewVBox(
+		infoLabel,
+		container.NewHBox(viewSelect, dropBtn, endBtn),
+		widget.NewSeparator(),
+	)
+
+	sessionWindow.SetContent(container.NewBorder(top, nil, nil, nil, previewBox))
+	sessionWindow.Resize(fyne.NewSize(800, 450))
+	sessionWindow.CenterOnScreen()
+	sessionWindow.Show()
+
+	reload()
+}